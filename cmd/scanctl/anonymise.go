@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/ministryofjustice/opg-scanning/internal/synthetic"
+)
+
+func anonymiseCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("anonymise", flag.ContinueOnError)
+	key := flags.String("key", "", "secret the made-up values are derived from; the same key gives the same values")
+	out := flags.String("out", "", "file to write the set to, instead of standard output")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return false, err
+	}
+	if *key == "" {
+		return false, errUsage
+	}
+
+	body, err := os.ReadFile(path) //#nosec G304 the path is given by the user running the command
+	if err != nil {
+		return false, err
+	}
+
+	set, err := synthetic.NewAnonymiser([]byte(*key)).Set(body)
+	if err != nil {
+		return false, err
+	}
+
+	if *out == "" {
+		_, err := stdout.Write(set)
+		return err == nil, err
+	}
+
+	return true, writeFile(stdout, *out, set)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ministryofjustice/opg-scanning/internal/setbuilder"
+)
+
+// buildManifest describes a set to build. Paths to files are relative to the
+// manifest.
+type buildManifest struct {
+	Header    setbuilder.Header `json:"header"`
+	Documents []struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		XML  string `json:"xml"`
+		PDF  string `json:"pdf"`
+	} `json:"documents"`
+}
+
+func buildCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	out := flags.String("out", "", "file to write the set to, instead of standard output")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path) //#nosec G304 the path is given by the user running the command
+	if err != nil {
+		return false, err
+	}
+
+	var manifest buildManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return false, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	readRelative := func(name string) ([]byte, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+
+		return os.ReadFile(name) //#nosec G304 the path is given by the user running the command
+	}
+
+	var documents []setbuilder.Document
+	for i, document := range manifest.Documents {
+		xml, err := readRelative(document.XML)
+		if err != nil {
+			return false, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		pdf, err := readRelative(document.PDF)
+		if err != nil {
+			return false, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		documents = append(documents, setbuilder.Document{
+			Type: document.Type,
+			ID:   document.ID,
+			XML:  xml,
+			PDF:  pdf,
+		})
+	}
+
+	set, err := setbuilder.Build(manifest.Header, documents)
+	if err != nil {
+		return false, err
+	}
+
+	if *out == "" {
+		_, err := stdout.Write(set)
+		return err == nil, err
+	}
+
+	return true, writeFile(stdout, *out, set)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/synthetic"
+)
+
+func generateCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	documentTypes := flags.String("types", "LP1F", "comma separated types of the documents in each set")
+	defects := flags.String("defects", "", "comma separated defects to make on the forms")
+	count := flags.Int("count", 1, "number of sets to generate")
+	seed := flags.Uint64("seed", 1, "seed for the random forms")
+	out := flags.String("out", ".", "directory to write sets to")
+	xsdPath := flags.String("xsd", "", "directory containing the XSDs")
+
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *count < 1 {
+		return false, errUsage
+	}
+
+	appConfig, err := config.Read()
+	if err != nil {
+		return false, err
+	}
+	if *xsdPath != "" {
+		appConfig.App.XSDPath = *xsdPath
+	}
+
+	var setDefects []synthetic.Defect
+	for _, defect := range splitList(*defects) {
+		setDefects = append(setDefects, synthetic.Defect(defect))
+	}
+
+	if err := os.MkdirAll(*out, 0o750); err != nil {
+		return false, err
+	}
+
+	generator := synthetic.New(appConfig.App.XSDPath, *seed)
+	for i := range *count {
+		set, err := generator.Set(splitList(*documentTypes), setDefects...)
+		if err != nil {
+			return false, err
+		}
+
+		if err := writeFile(stdout, filepath.Join(*out, fmt.Sprintf("set-%04d.xml", i+1)), set); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for item := range strings.SplitSeq(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,397 @@
+// Command scanctl works with scanned set files locally, using the same
+// validation and parsing as the scanning API.
+//
+// Usage:
+//
+//	scanctl validate [-json] [-xsd dir] set.xml
+//	scanctl list set.xml
+//	scanctl extract [-out dir] set.xml
+//	scanctl parse [-json] set.xml
+//	scanctl build [-out set.xml] manifest.json
+//	scanctl generate [-types LP1F,LPC] [-defects defect,...] [-count n] [-seed n] [-out dir]
+//	scanctl anonymise -key secret [-out set.xml] set.xml
+//	scanctl verify-audit [-store file|dynamodb|s3] [audit.jsonl]
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
+	"github.com/ministryofjustice/opg-scanning/internal/factory"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+const usage = `usage: scanctl <command> [flags] <file>
+
+commands:
+  validate  check a set against the XSDs and the rules used by the API
+  list      list the documents in a set
+  extract   decode the XML and PDF of each document to a directory
+  parse     parse each form and print the output of its validator
+  build     assemble a set from the forms and scans listed in a manifest
+  generate  write sets of random forms, optionally with defects
+            (missing-witness, future-date, wrong-attorney-count)
+  anonymise replace the personal details in a set with made-up ones, and
+            its scans with blank pages
+  verify-audit
+            check that the chain of hashes in the audit log is intact
+`
+
+// Exit codes.
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	commands := map[string]func([]string, io.Writer) (bool, error){
+		"validate":     validateCommand,
+		"list":         listCommand,
+		"extract":      extractCommand,
+		"parse":        parseCommand,
+		"build":        buildCommand,
+		"generate":     generateCommand,
+		"anonymise":    anonymiseCommand,
+		"verify-audit": verifyAuditCommand,
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	ok, err := command(args[1:], stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "scanctl %s: %v\n", args[0], err)
+		return exitInvalid
+	}
+	if !ok {
+		return exitInvalid
+	}
+
+	return exitOK
+}
+
+// parseFlags parses the flags for a command, which must be followed by the
+// path to a single file.
+func parseFlags(flags *flag.FlagSet, args []string) (string, error) {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return "", errUsage
+	}
+
+	return flags.Arg(0), nil
+}
+
+func readSet(path string) (*types.BaseSet, error) {
+	body, err := os.ReadFile(path) //#nosec G304 the path is given by the user running the command
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := parser.BaseParserXml(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse set: %w", err)
+	}
+
+	return set, nil
+}
+
+func validateCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	xsdPath := flags.String("xsd", "", "directory containing the XSDs")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return false, err
+	}
+
+	appConfig, err := config.Read()
+	if err != nil {
+		return false, err
+	}
+	if *xsdPath != "" {
+		appConfig.App.XSDPath = *xsdPath
+	}
+
+	body, err := os.ReadFile(path) //#nosec G304 the path is given by the user running the command
+	if err != nil {
+		return false, err
+	}
+
+	// Validate does not store anything, so the worker needs no AWS clients.
+	worker := ingestion.NewWorker(slog.New(slog.DiscardHandler), appConfig, nil, nil, nil)
+
+	report, err := worker.Validate(context.Background(), body)
+	if err != nil {
+		return false, err
+	}
+
+	if *asJSON {
+		return report.Valid, writeJSON(stdout, report)
+	}
+
+	if report.Valid {
+		fmt.Fprintf(stdout, "%s: valid\n", path)
+	} else {
+		fmt.Fprintf(stdout, "%s: invalid\n", path)
+	}
+
+	return report.Valid, writeIssues(stdout, report.Issues, true)
+}
+
+func listCommand(args []string, stdout io.Writer) (bool, error) {
+	path, err := parseFlags(flag.NewFlagSet("list", flag.ContinueOnError), args)
+	if err != nil {
+		return false, err
+	}
+
+	set, err := readSet(path)
+	if err != nil {
+		return false, err
+	}
+
+	if set.Header != nil {
+		fmt.Fprintf(stdout, "Case number: %s\nSchedule: %s\nScanned: %s by %s on scanner %s\n\n",
+			set.Header.CaseNo, set.Header.Schedule, set.Header.ScanTime, set.Header.ScannerOperator, set.Header.Scanner)
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tID\tTYPE\tPAGES\tXML BYTES\tPDF BYTES")
+	for i, doc := range set.Body.Documents {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\n", i, doc.ID, doc.Type, doc.NoPages,
+			decodedSize(doc.EmbeddedXML), decodedSize(doc.EmbeddedPDF))
+	}
+
+	return true, tw.Flush()
+}
+
+// decodedSize describes the size of base64 encoded content once decoded.
+func decodedSize(encoded string) string {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "invalid"
+	}
+
+	return fmt.Sprint(len(data))
+}
+
+func extractCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	out := flags.String("out", ".", "directory to write documents to")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return false, err
+	}
+
+	set, err := readSet(path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(*out, 0o750); err != nil {
+		return false, err
+	}
+
+	for i, doc := range set.Body.Documents {
+		name := documentFileName(i, &doc)
+
+		if doc.EmbeddedXML != "" {
+			data, err := util.DecodeEmbeddedXML(doc.EmbeddedXML)
+			if err != nil {
+				return false, fmt.Errorf("document %d: %w", i, err)
+			}
+			if err := writeFile(stdout, filepath.Join(*out, name+".xml"), data); err != nil {
+				return false, err
+			}
+		}
+
+		if doc.EmbeddedPDF != "" {
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(doc.EmbeddedPDF))
+			if err != nil {
+				return false, fmt.Errorf("document %d: failed to decode embedded PDF: %w", i, err)
+			}
+			if err := writeFile(stdout, filepath.Join(*out, name+".pdf"), data); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// documentFileName names the files extracted from a document by its position
+// in the set, type and ID, keeping only characters that are safe in a path.
+func documentFileName(i int, doc *types.BaseDocument) string {
+	safe := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+				return r
+			}
+			return '_'
+		}, s)
+	}
+
+	name := fmt.Sprintf("%02d-%s", i, safe(doc.Type))
+	if doc.ID != "" {
+		name += "-" + safe(doc.ID)
+	}
+
+	return name
+}
+
+func writeFile(stdout io.Writer, path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, path)
+	return nil
+}
+
+type documentReport struct {
+	Index      int                  `json:"index"`
+	ID         string               `json:"id,omitempty"`
+	Type       string               `json:"type"`
+	Error      string               `json:"error,omitempty"`
+	Issues     []validation.Issue   `json:"issues"`
+	Extraction *extraction.Document `json:"extraction,omitempty"`
+}
+
+func parseCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("parse", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the report, including the parsed forms, as JSON")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return false, err
+	}
+
+	set, err := readSet(path)
+	if err != nil {
+		return false, err
+	}
+
+	registry, err := factory.NewRegistry()
+	if err != nil {
+		return false, err
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+	ok := true
+
+	var reports []documentReport
+	for i := range set.Body.Documents {
+		doc := &set.Body.Documents[i]
+		report := documentReport{Index: i, ID: doc.ID, Type: doc.Type, Issues: []validation.Issue{}}
+
+		parsed, issues, err := parseDocument(doc, registry, logger)
+		if err != nil {
+			ok = false
+			report.Error = err.Error()
+		} else {
+			report.Issues = issues
+			if *asJSON {
+				report.Extraction, _ = extraction.New(doc.Type, doc.ID, parsed)
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	if *asJSON {
+		return ok, writeJSON(stdout, reports)
+	}
+
+	for _, report := range reports {
+		fmt.Fprintf(stdout, "%d %s %s\n", report.Index, report.Type, report.ID)
+
+		switch {
+		case report.Error != "":
+			fmt.Fprintf(stdout, "  error: %s\n", report.Error)
+		case len(report.Issues) == 0:
+			fmt.Fprintln(stdout, "  no issues")
+		default:
+			if err := writeIssues(stdout, report.Issues, false); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return ok, nil
+}
+
+func parseDocument(doc *types.BaseDocument, registry *factory.Registry, logger *slog.Logger) (any, []validation.Issue, error) {
+	processor, err := factory.NewDocumentProcessor(doc, doc.Type, registry, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := processor.Process(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsed, processor.Issues(), nil
+}
+
+// writeIssues prints issues as a table, optionally with the document each
+// belongs to.
+func writeIssues(w io.Writer, issues []validation.Issue, withDocument bool) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, issue := range issues {
+		fmt.Fprint(tw, "  ", issue.Severity, "\t")
+		if withDocument {
+			fmt.Fprint(tw, strings.TrimSpace(issue.DocumentType+" "+issue.DocumentID), "\t")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", issue.Code, issue.Field, issue.Message)
+	}
+
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSet(t *testing.T, docType, fixture string) string {
+	xml, err := os.ReadFile("../../testdata/xml/" + fixture)
+	require.NoError(t, err)
+
+	set := fmt.Sprintf(`<Set xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="SET.xsd">
+	<Header CaseNo="" Scanner="9" ScanTime="2014-09-26T12:38:53" ScannerOperator="Administrator" Schedule="02-0001112-20160909185000"/>
+	<Body>
+		<Document Type="%s" Encoding="UTF-8" NoPages="19" ID="doc-1">
+			<XML>%s</XML>
+			<PDF>SGVsbG8gd29ybGQ=</PDF>
+		</Document>
+	</Body>
+</Set>`, docType, base64.StdEncoding.EncodeToString(xml))
+
+	path := filepath.Join(t.TempDir(), "set.xml")
+	require.NoError(t, os.WriteFile(path, []byte(set), 0o600))
+
+	return path
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitUsage, run(nil, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"unknown"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"list"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "usage: scanctl")
+}
+
+func TestRunValidate(t *testing.T) {
+	t.Setenv("XSD_PATH", "../../xsd")
+	path := writeSet(t, "LP1F", "LP1F-valid.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"validate", "-json", path}, &stdout, &stderr), stderr.String())
+
+	var report ingestion.Report
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.True(t, report.Valid)
+}
+
+func TestRunValidateInvalid(t *testing.T) {
+	t.Setenv("XSD_PATH", "../../xsd")
+	path := writeSet(t, "LP1F", "LP2-valid.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitInvalid, run([]string{"validate", path}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), path+": invalid")
+	assert.Contains(t, stdout.String(), "document-unreadable")
+}
+
+func TestRunList(t *testing.T) {
+	path := writeSet(t, "LP1F", "LP1F-valid.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"list", path}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "Schedule: 02-0001112-20160909185000")
+	assert.Regexp(t, `0\s+doc-1\s+LP1F\s+19\s+\d+\s+11\n`, stdout.String())
+}
+
+func TestRunExtract(t *testing.T) {
+	path := writeSet(t, "LP1F", "LP1F-valid.xml")
+	out := filepath.Join(t.TempDir(), "out")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"extract", "-out", out, path}, &stdout, &stderr), stderr.String())
+
+	pdf, err := os.ReadFile(filepath.Join(out, "00-LP1F-doc-1.pdf"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", string(pdf))
+
+	xml, err := os.ReadFile(filepath.Join(out, "00-LP1F-doc-1.xml"))
+	require.NoError(t, err)
+	expected, _ := os.ReadFile("../../testdata/xml/LP1F-valid.xml")
+	assert.Equal(t, expected, xml)
+}
+
+func TestRunParse(t *testing.T) {
+	path := writeSet(t, "LP1F", "LP1F-invalid-dates.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"parse", path}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "0 LP1F doc-1")
+	assert.Regexp(t, `warning\s+signature-missing\s+Page10\.Section9\.Witness\.Signature`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"parse", "-json", path}, &stdout, &stderr), stderr.String())
+
+	var reports []documentReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &reports))
+	require.Len(t, reports, 1)
+	assert.NotEmpty(t, reports[0].Issues)
+	assert.Equal(t, "LP1F", reports[0].Extraction.DocumentType)
+}
+
+func TestRunBuild(t *testing.T) {
+	t.Setenv("XSD_PATH", "../../xsd")
+	dir := t.TempDir()
+
+	// paths in the manifest are relative to it
+	for name, fixture := range map[string]string{"lp1f.xml": "xml/LP1F-valid.xml", "scan.pdf": "pdf/dummy.pdf"} {
+		data, err := os.ReadFile("../../testdata/" + fixture)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	manifestPath := filepath.Join(dir, "manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`{
+	"header": {"scanner": "9", "scanTime": "2014-09-26T12:38:53", "scannerOperator": "Administrator", "schedule": "02-0001112-20160909185000"},
+	"documents": [{"type": "LP1F", "id": "lp1f", "xml": "lp1f.xml", "pdf": "scan.pdf"}]
+}`), 0o600))
+
+	setPath := filepath.Join(dir, "set.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"build", "-out", setPath, manifestPath}, &stdout, &stderr), stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"validate", setPath}, &stdout, &stderr), stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"list", setPath}, &stdout, &stderr))
+	assert.Regexp(t, `0\s+lp1f\s+LP1F\s+1\s`, stdout.String())
+}
+
+func TestRunGenerate(t *testing.T) {
+	t.Setenv("XSD_PATH", "../../xsd")
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"generate", "-types", "LP1F,LPC", "-count", "2", "-out", dir}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, filepath.Join(dir, "set-0001.xml")+"\n"+filepath.Join(dir, "set-0002.xml")+"\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"validate", filepath.Join(dir, "set-0002.xml")}, &stdout, &stderr), stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"generate", "-defects", "future-date", "-out", dir}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, exitOK, run([]string{"validate", "-json", filepath.Join(dir, "set-0001.xml")}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), `"code": "date-in-future"`)
+
+	assert.Equal(t, exitInvalid, run([]string{"generate", "-types", "LP2", "-defects", "missing-witness", "-out", dir}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"generate", "set.xml"}, &stdout, &stderr))
+}
+
+func TestRunAnonymise(t *testing.T) {
+	t.Setenv("XSD_PATH", "../../xsd")
+	path := writeSet(t, "LP1F", "LP1F-valid.xml")
+	out := filepath.Join(t.TempDir(), "anonymised.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"anonymise", "-key", "secret", "-out", out, path}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, out+"\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"validate", out}, &stdout, &stderr), stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"anonymise", "-key", "secret", path}, &stdout, &stderr))
+	anonymised, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, string(anonymised), stdout.String())
+
+	assert.Equal(t, exitUsage, run([]string{"anonymise", path}, &stdout, &stderr))
+}
+
+func TestRunVerifyAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"verify-audit", path}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "audit log is empty\n", stdout.String())
+
+	appConfig := &config.Config{}
+	appConfig.Audit.File = path
+	log := audit.NewLog(audit.NewStore(appConfig, nil, nil), slog.New(slog.DiscardHandler))
+	log.Record(context.Background(), audit.Event{Action: audit.ActionLogin, Principal: "cop@example.com", Outcome: audit.OutcomeSuccess})
+	log.Record(context.Background(), audit.Event{Action: audit.ActionLogout, Principal: "cop@example.com", Outcome: audit.OutcomeSuccess})
+	require.NoError(t, log.Close(context.Background()))
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"verify-audit", "-store", "file", path}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "audit log is intact: 2 entries")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte(`"logout"`), []byte(`"login"`), 1), 0o600))
+
+	stdout.Reset()
+	assert.Equal(t, exitInvalid, run([]string{"verify-audit", path}, &stdout, &stderr))
+	assert.Equal(t, "audit log is broken at entry 2: hash does not match its content\n", stdout.String())
+
+	assert.Equal(t, exitUsage, run([]string{"verify-audit"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"verify-audit", "-store", "dynamodb", path}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"verify-audit", "-store", "postgres"}, &stdout, &stderr))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+)
+
+func verifyAuditCommand(args []string, stdout io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("verify-audit", flag.ContinueOnError)
+	store := flags.String("store", "file", "where the audit log is kept: file, dynamodb or s3")
+
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return false, errUsage
+	}
+
+	appConfig, err := config.Read()
+	if err != nil {
+		return false, err
+	}
+	appConfig.Audit.Store = *store
+
+	// Only the file store is given a path. The others are found from the
+	// same environment variables as the API uses.
+	switch *store {
+	case "file":
+		if flags.NArg() != 1 {
+			return false, errUsage
+		}
+		appConfig.Audit.File = flags.Arg(0)
+	case "dynamodb", "s3":
+		if flags.NArg() != 0 {
+			return false, errUsage
+		}
+	default:
+		return false, errUsage
+	}
+
+	ctx := context.Background()
+
+	var (
+		dynamoClient *dynamodb.Client
+		s3Client     *s3.Client
+	)
+	if *store != "file" {
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(appConfig.Aws.Region))
+		if err != nil {
+			return false, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		if appConfig.Aws.Endpoint != "" {
+			cfg.BaseEndpoint = aws.String(appConfig.Aws.Endpoint)
+		}
+
+		dynamoClient = dynamodb.NewFromConfig(cfg)
+		s3Client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.UsePathStyle = appConfig.App.Environment == "local"
+		})
+	}
+
+	last, err := audit.Verify(ctx, audit.NewStore(appConfig, dynamoClient, s3Client))
+
+	var verifyError audit.VerifyError
+	if errors.As(err, &verifyError) {
+		fmt.Fprintf(stdout, "audit log is broken at %v\n", err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if last == nil {
+		fmt.Fprintln(stdout, "audit log is empty")
+		return true, nil
+	}
+
+	fmt.Fprintf(stdout, "audit log is intact: %d entries, last recorded %s with hash %s\n",
+		last.Sequence, last.Time.Format(time.RFC3339), last.Hash)
+	return true, nil
+}
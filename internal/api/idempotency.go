@@ -0,0 +1,354 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/problem"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const maxIdempotencyKeyLength = 255
+
+var errIdempotencyClaimLost = errors.New("idempotency key is no longer held by the request")
+
+// maxTrackedIdempotencyKeys is how many keys are remembered in memory.
+// Forgetting a key before it expires would let a retry be processed again, so
+// when that many are held, requests with new keys are refused until some
+// expire.
+const maxTrackedIdempotencyKeys = 100000
+
+// idempotencyLockTimeout is how long a request holds its key while it is
+// processed. If the instance handling it stops before it finishes, the key
+// can be used again after this.
+const idempotencyLockTimeout = 5 * time.Minute
+
+// idempotencyRecord is what is kept for a key. Status is 0 until the first
+// request with the key has finished.
+type idempotencyRecord struct {
+	BodyHash    string
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+type idempotencyStore interface {
+	// Start claims a key for a request. If the key has already been claimed
+	// and has not expired, its record is returned instead.
+	Start(ctx context.Context, key string, record idempotencyRecord) (*idempotencyRecord, error)
+	// Complete stores the response to the request holding a key. It fails
+	// with errIdempotencyClaimLost if the key is no longer held by the claim
+	// Start was given, such as when the claim expired and another request
+	// claimed the key.
+	Complete(ctx context.Context, key string, claim, record idempotencyRecord) error
+	// Release forgets a key, so that the request can be tried again.
+	Release(ctx context.Context, key string) error
+}
+
+func newIdempotencyStore(appConfig *config.Config, dynamoClient dynamoClient) idempotencyStore {
+	if appConfig.HTTP.IdempotencyStore == "dynamodb" {
+		return &dynamoIdempotencyStore{dynamo: dynamoClient, tableName: appConfig.Aws.DocumentsTable, now: time.Now}
+	}
+
+	return newMemoryIdempotencyStore()
+}
+
+// idempotent wraps a handler so that a request retried with the same
+// Idempotency-Key is given the response to the first, rather than being
+// processed again. Keys are scoped to the principal, so it must be used after
+// authentication. Server errors are not kept, so that the request can be
+// retried with the same key.
+func (c *IndexController) idempotent(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.respondWithError(w, r, problem.IdempotencyKeyInvalid, fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength), nil)
+			return
+		}
+
+		body, err := c.readRequestBody(w, r)
+		if err != nil {
+			c.respondWithError(w, r, problem.InvalidRequestBody, "Invalid request body", err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+
+		key := "IDEMPOTENCY#" + idempotencyKey
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			key = "IDEMPOTENCY#" + principal.ID + "#" + idempotencyKey
+		}
+
+		sum := sha256.Sum256(body)
+		bodyHash := hex.EncodeToString(sum[:])
+
+		claim := idempotencyRecord{
+			BodyHash:  bodyHash,
+			ExpiresAt: time.Now().Add(idempotencyLockTimeout),
+		}
+
+		existing, err := c.idempotency.Start(ctx, key, claim)
+		if err != nil {
+			c.respondWithError(w, r, problem.InternalError, "Failed to check idempotency key", err)
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.BodyHash != bodyHash:
+				c.respondWithError(w, r, problem.IdempotencyKeyReused, "Idempotency-Key has already been used for a different request", nil)
+			case existing.Status == 0:
+				c.respondWithError(w, r, problem.RequestInProgress, "A request with this Idempotency-Key is still being processed", nil)
+			default:
+				c.logger.InfoContext(ctx, "Replaying response for idempotency key", slog.Int("status", existing.Status))
+
+				replay := audit.Event{
+					Action:  audit.ActionReplayResponse,
+					Hashes:  map[string]string{"set": bodyHash},
+					Outcome: audit.OutcomeSuccess,
+					Detail:  fmt.Sprintf("replayed a %d response", existing.Status),
+				}
+				if principal, ok := auth.PrincipalFromContext(ctx); ok {
+					replay.Principal = principal.ID
+				}
+				c.audit.Record(ctx, replay)
+
+				if existing.ContentType != "" {
+					w.Header().Set("Content-Type", existing.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(existing.Status)
+
+				if _, err := w.Write(existing.Body); err != nil {
+					c.logger.ErrorContext(ctx, "Failed to write response", slog.Any("error", err))
+				}
+			}
+
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// The client may have gone, but what happened must still be kept.
+		ctx = context.WithoutCancel(ctx)
+
+		if recorder.status >= http.StatusInternalServerError {
+			if err := c.idempotency.Release(ctx, key); err != nil {
+				c.logger.ErrorContext(ctx, "Failed to release idempotency key", slog.Any("error", err))
+			}
+			return
+		}
+
+		if err := c.idempotency.Complete(ctx, key, claim, idempotencyRecord{
+			BodyHash:    bodyHash,
+			Status:      recorder.status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			ExpiresAt:   time.Now().Add(c.config.HTTP.IdempotencyTTL),
+		}); err != nil {
+			c.logger.ErrorContext(ctx, "Failed to store response for idempotency key", slog.Any("error", err))
+		}
+	}
+}
+
+// responseRecorder passes a response on, keeping a copy of its status and
+// body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+
+	return r.ResponseWriter.Write(p)
+}
+
+type memoryIdempotencyStore struct {
+	now     func() time.Time
+	records *cache.Cache[string, idempotencyRecord]
+
+	// mu makes checking a claim and completing it one step
+	mu sync.Mutex
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{
+		now:     time.Now,
+		records: cache.New[string, idempotencyRecord](maxTrackedIdempotencyKeys),
+	}
+}
+
+func (s *memoryIdempotencyStore) Start(ctx context.Context, key string, record idempotencyRecord) (*idempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, added, err := s.records.Insert(key, record, record.ExpiresAt, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("too many idempotency keys held: %w", err)
+	}
+	if !added {
+		return &existing, nil
+	}
+
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key string, claim, record idempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records.Get(key, s.now())
+	if !ok || existing.Status != 0 || existing.BodyHash != claim.BodyHash || !existing.ExpiresAt.Equal(claim.ExpiresAt) {
+		return errIdempotencyClaimLost
+	}
+
+	return s.records.Put(key, record, record.ExpiresAt, s.now())
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.records.Delete(key)
+	return nil
+}
+
+type dynamoClient interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// dynamoIdempotencyStore keeps records in DynamoDB, so that a retry is
+// recognised by any instance. Items expire using the table's TTL, but that
+// can lag, so expiry is also checked when claiming a key.
+type dynamoIdempotencyStore struct {
+	dynamo    dynamoClient
+	tableName string
+	now       func() time.Time
+}
+
+func (s *dynamoIdempotencyStore) Start(ctx context.Context, key string, record idempotencyRecord) (*idempotencyRecord, error) {
+	_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                           aws.String(s.tableName),
+		Item:                                idempotencyItem(key, record),
+		ConditionExpression:                 aws.String("attribute_not_exists(PK) OR ExpiresAt < :Now"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":Now": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Unix(), 10)},
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		var v struct {
+			BodyHash    string
+			Status      int
+			ContentType string
+			Body        []byte
+			ExpiresAt   int64
+		}
+		if err := attributevalue.UnmarshalMap(conditionFailed.Item, &v); err != nil {
+			return nil, err
+		}
+
+		return &idempotencyRecord{
+			BodyHash:    v.BodyHash,
+			Status:      v.Status,
+			ContentType: v.ContentType,
+			Body:        v.Body,
+			ExpiresAt:   time.Unix(v.ExpiresAt, 0),
+		}, nil
+	}
+
+	return nil, err
+}
+
+func (s *dynamoIdempotencyStore) Complete(ctx context.Context, key string, claim, record idempotencyRecord) error {
+	_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                idempotencyItem(key, record),
+		ConditionExpression: aws.String("BodyHash = :BodyHash AND ExpiresAt = :ExpiresAt"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":BodyHash":  &types.AttributeValueMemberS{Value: claim.BodyHash},
+			":ExpiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(claim.ExpiresAt.Unix(), 10)},
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return errIdempotencyClaimLost
+	}
+
+	return err
+}
+
+func (s *dynamoIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.dynamo.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: key},
+			"SK": &types.AttributeValueMemberS{Value: key},
+		},
+	})
+
+	return err
+}
+
+func idempotencyItem(key string, record idempotencyRecord) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"PK":        &types.AttributeValueMemberS{Value: key},
+		"SK":        &types.AttributeValueMemberS{Value: key},
+		"BodyHash":  &types.AttributeValueMemberS{Value: record.BodyHash},
+		"Status":    &types.AttributeValueMemberN{Value: strconv.Itoa(record.Status)},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(record.ExpiresAt.Unix(), 10)},
+	}
+
+	if record.ContentType != "" {
+		item["ContentType"] = &types.AttributeValueMemberS{Value: record.ContentType}
+	}
+	if len(record.Body) > 0 {
+		item["Body"] = &types.AttributeValueMemberB{Value: record.Body}
+	}
+
+	return item
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/ministryofjustice/opg-scanning/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupIdempotentController(t *testing.T, worker *mockWorker) (*IndexController, http.Handler) {
+	appConfig, _ := config.Read()
+
+	controller := &IndexController{
+		config:      appConfig,
+		logger:      slog.New(slog.DiscardHandler),
+		worker:      worker,
+		idempotency: newMemoryIdempotencyStore(),
+	}
+
+	return controller, controller.idempotent(http.HandlerFunc(controller.ingestHandler))
+}
+
+func idempotentRequest(principal, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/ddc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set(IdempotencyKeyHeader, key)
+
+	return req.WithContext(auth.ContextWithPrincipal(req.Context(), auth.Principal{ID: principal}))
+}
+
+func TestIdempotent_ReplaysResponse(t *testing.T) {
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, []byte(xmlPayload)).
+		Return(&ingestion.Result{UID: "700012341234"}, nil).
+		Once()
+
+	controller, handler := setupIdempotentController(t, worker)
+
+	auditConfig := &config.Config{}
+	auditConfig.Audit.File = filepath.Join(t.TempDir(), "audit.jsonl")
+	auditStore := audit.NewStore(auditConfig, nil, nil)
+	controller.audit = audit.NewLog(auditStore, slog.New(slog.DiscardHandler))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("supplier@example.com", "key-1", xmlPayload))
+	assert.Equal(t, http.StatusAccepted, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest("supplier@example.com", "key-1", xmlPayload))
+	assert.Equal(t, http.StatusAccepted, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	assert.NoError(t, controller.audit.Close(context.Background()))
+
+	var events []audit.Event
+	assert.NoError(t, auditStore.Entries(context.Background(), func(entry audit.Entry) error {
+		events = append(events, entry.Event)
+		return nil
+	}))
+	assert.Equal(t, []audit.Event{{
+		Action:    audit.ActionReplayResponse,
+		Principal: "supplier@example.com",
+		Hashes:    map[string]string{"set": audit.ContentHash([]byte(xmlPayload))},
+		Outcome:   audit.OutcomeSuccess,
+		Detail:    "replayed a 202 response",
+	}}, events)
+}
+
+func TestIdempotent_ScopedToPrincipal(t *testing.T) {
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(&ingestion.Result{UID: "700012341234"}, nil).
+		Twice()
+
+	_, handler := setupIdempotentController(t, worker)
+
+	for _, principal := range []string{"supplier@example.com", "other@example.com"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, idempotentRequest(principal, "key-1", xmlPayload))
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func TestIdempotent_WithoutKey(t *testing.T) {
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(&ingestion.Result{UID: "700012341234"}, nil).
+		Twice()
+
+	_, handler := setupIdempotentController(t, worker)
+
+	for range 2 {
+		req := idempotentRequest("supplier@example.com", "", xmlPayload)
+		req.Header.Del(IdempotencyKeyHeader)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	}
+}
+
+func TestIdempotent_ReleasesKeyOnServerError(t *testing.T) {
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(nil, errors.New("a generic error")).
+		Once()
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(&ingestion.Result{UID: "700012341234"}, nil).
+		Once()
+
+	_, handler := setupIdempotentController(t, worker)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("supplier@example.com", "key-1", xmlPayload))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("supplier@example.com", "key-1", xmlPayload))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotent_Conflicts(t *testing.T) {
+	sum := sha256.Sum256([]byte(xmlPayload))
+
+	testCases := map[string]struct {
+		existing    idempotencyRecord
+		problemType problem.Type
+	}{
+		"different body": {
+			existing:    idempotencyRecord{BodyHash: "other", Status: http.StatusAccepted},
+			problemType: problem.IdempotencyKeyReused,
+		},
+		"in progress": {
+			existing:    idempotencyRecord{BodyHash: hex.EncodeToString(sum[:])},
+			problemType: problem.RequestInProgress,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			controller, handler := setupIdempotentController(t, newMockWorker(t))
+
+			tc.existing.ExpiresAt = time.Now().Add(time.Minute)
+			_, _ = controller.idempotency.Start(context.Background(), "IDEMPOTENCY#supplier@example.com#key-1", tc.existing)
+
+			req := idempotentRequest("supplier@example.com", "key-1", xmlPayload)
+			req.Header.Set("Accept", problem.ContentType)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			var details problem.Details
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&details))
+			assert.Equal(t, tc.problemType.Status, w.Code)
+			assert.Equal(t, tc.problemType.Code, details.Code)
+		})
+	}
+}
+
+func TestIdempotent_KeyTooLong(t *testing.T) {
+	_, handler := setupIdempotentController(t, newMockWorker(t))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("supplier@example.com", strings.Repeat("a", 256), xmlPayload))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	now := time.Now()
+	store := newMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+
+	existing, err := store.Start(context.Background(), "key", idempotencyRecord{BodyHash: "a", ExpiresAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = store.Start(context.Background(), "key", idempotencyRecord{BodyHash: "b", ExpiresAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, &idempotencyRecord{BodyHash: "a", ExpiresAt: now.Add(time.Minute)}, existing)
+
+	// expired keys can be claimed again
+	now = now.Add(time.Minute)
+	existing, err = store.Start(context.Background(), "key", idempotencyRecord{BodyHash: "b", ExpiresAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	assert.NoError(t, store.Release(context.Background(), "key"))
+	claim := idempotencyRecord{BodyHash: "c", ExpiresAt: now.Add(time.Minute)}
+	existing, err = store.Start(context.Background(), "key", claim)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// only the request holding the key can complete it
+	lostClaim := idempotencyRecord{BodyHash: "b", ExpiresAt: now.Add(time.Minute)}
+	assert.ErrorIs(t, store.Complete(context.Background(), "key", lostClaim, idempotencyRecord{BodyHash: "b", Status: http.StatusAccepted, ExpiresAt: now.Add(time.Hour)}), errIdempotencyClaimLost)
+
+	completed := idempotencyRecord{BodyHash: "c", Status: http.StatusAccepted, ExpiresAt: now.Add(time.Hour)}
+	assert.NoError(t, store.Complete(context.Background(), "key", claim, completed))
+	assert.ErrorIs(t, store.Complete(context.Background(), "key", claim, completed), errIdempotencyClaimLost)
+
+	existing, err = store.Start(context.Background(), "key", idempotencyRecord{BodyHash: "c", ExpiresAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, &completed, existing)
+}
+
+func TestMemoryIdempotencyStoreFull(t *testing.T) {
+	now := time.Now()
+	store := newMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+	store.records = cache.New[string, idempotencyRecord](1)
+
+	claim := idempotencyRecord{BodyHash: "a", ExpiresAt: now.Add(time.Minute)}
+	existing, err := store.Start(context.Background(), "a", claim)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// the claim on a is not forgotten to make room
+	_, err = store.Start(context.Background(), "b", idempotencyRecord{BodyHash: "b", ExpiresAt: now.Add(time.Minute)})
+	assert.ErrorIs(t, err, cache.ErrFull)
+
+	existing, err = store.Start(context.Background(), "a", claim)
+	assert.NoError(t, err)
+	assert.Equal(t, &claim, existing)
+}
+
+func TestDynamoIdempotencyStoreStart(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	record := idempotencyRecord{BodyHash: "a", ExpiresAt: now.Add(time.Minute)}
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, &dynamodb.PutItemInput{
+			TableName:                           aws.String("documents"),
+			Item:                                idempotencyItem("key", record),
+			ConditionExpression:                 aws.String("attribute_not_exists(PK) OR ExpiresAt < :Now"),
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":Now": &types.AttributeValueMemberN{Value: "1700000000"},
+			},
+		}).
+		Return(&dynamodb.PutItemOutput{}, nil).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{Item: idempotencyItem("key", idempotencyRecord{
+			BodyHash:    "b",
+			Status:      http.StatusAccepted,
+			ContentType: "application/json",
+			Body:        []byte(`{}`),
+			ExpiresAt:   now.Add(time.Hour),
+		})}).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, errors.New("err")).
+		Once()
+
+	store := &dynamoIdempotencyStore{dynamo: dynamo, tableName: "documents", now: func() time.Time { return now }}
+
+	existing, err := store.Start(context.Background(), "key", record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = store.Start(context.Background(), "key", record)
+	assert.NoError(t, err)
+	assert.Equal(t, &idempotencyRecord{
+		BodyHash:    "b",
+		Status:      http.StatusAccepted,
+		ContentType: "application/json",
+		Body:        []byte(`{}`),
+		ExpiresAt:   now.Add(time.Hour),
+	}, existing)
+
+	_, err = store.Start(context.Background(), "key", record)
+	assert.Error(t, err)
+}
+
+func TestDynamoIdempotencyStoreCompleteAndRelease(t *testing.T) {
+	claim := idempotencyRecord{BodyHash: "a", ExpiresAt: time.Unix(1700000000, 0)}
+	record := idempotencyRecord{BodyHash: "a", Status: http.StatusAccepted, Body: []byte(`{}`), ExpiresAt: time.Unix(1700003600, 0)}
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, &dynamodb.PutItemInput{
+			TableName:           aws.String("documents"),
+			Item:                idempotencyItem("key", record),
+			ConditionExpression: aws.String("BodyHash = :BodyHash AND ExpiresAt = :ExpiresAt"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":BodyHash":  &types.AttributeValueMemberS{Value: "a"},
+				":ExpiresAt": &types.AttributeValueMemberN{Value: "1700000000"},
+			},
+		}).
+		Return(&dynamodb.PutItemOutput{}, nil).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{}).
+		Once()
+	dynamo.EXPECT().
+		DeleteItem(mock.Anything, &dynamodb.DeleteItemInput{
+			TableName: aws.String("documents"),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: "key"},
+				"SK": &types.AttributeValueMemberS{Value: "key"},
+			},
+		}).
+		Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	store := &dynamoIdempotencyStore{dynamo: dynamo, tableName: "documents", now: time.Now}
+
+	assert.NoError(t, store.Complete(context.Background(), "key", claim, record))
+	assert.ErrorIs(t, store.Complete(context.Background(), "key", claim, record), errIdempotencyClaimLost)
+	assert.NoError(t, store.Release(context.Background(), "key"))
+}
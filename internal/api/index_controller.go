@@ -9,33 +9,46 @@ import (
 	"log/slog"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
 	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/aws"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
 	"github.com/ministryofjustice/opg-scanning/internal/logger"
+	"github.com/ministryofjustice/opg-scanning/internal/problem"
 	"github.com/ministryofjustice/opg-scanning/internal/sirius"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Auth interface {
 	Authenticate(w http.ResponseWriter, r *http.Request) (auth.AuthenticatedUser, error)
-	Check(next http.Handler) http.HandlerFunc
+	Logout(w http.ResponseWriter, r *http.Request) error
+	RevokePrincipal(ctx context.Context, id string) error
+	Check(next http.Handler, scopes ...string) http.HandlerFunc
+	CheckSigned(next http.Handler, scopes ...string) http.HandlerFunc
+	KeySet(ctx context.Context) (auth.KeySet, error)
 }
 
 type worker interface {
-	Process(ctx context.Context, body []byte) (*sirius.ScannedCaseResponse, error)
+	Process(ctx context.Context, body []byte) (*ingestion.Result, error)
+	Validate(ctx context.Context, body []byte) (*ingestion.Report, error)
 }
 
 type IndexController struct {
-	config *config.Config
-	logger *slog.Logger
-	auth   Auth
-	worker worker
+	config      *config.Config
+	logger      *slog.Logger
+	auth        Auth
+	worker      worker
+	idempotency idempotencyStore
+	audit       *audit.Log
+	server      *http.Server
 }
 
 type response struct {
@@ -43,20 +56,32 @@ type response struct {
 }
 
 type responseData struct {
-	Success          bool     `json:"success"`
-	Message          string   `json:"message"`
-	Uid              string   `json:"uid,omitempty"`
-	ValidationErrors []string `json:"validationErrors,omitempty"`
+	Success          bool               `json:"success"`
+	Message          string             `json:"message"`
+	Uid              string             `json:"uid,omitempty"`
+	ValidationErrors []string           `json:"validationErrors,omitempty"`
+	ValidationIssues []validation.Issue `json:"validationIssues,omitempty"`
+}
+
+type validateResponse struct {
+	Data ingestion.Report `json:"data"`
 }
 
 var uidReplacementRegex = regexp.MustCompile(`^7[0-9]{3}-[0-9]{4}-[0-9]{4}$`)
 
-func NewIndexController(logger *slog.Logger, awsClient aws.AwsClientInterface, appConfig *config.Config, dynamoClient *dynamodb.Client) *IndexController {
+func NewIndexController(logger *slog.Logger, awsClient aws.AwsClientInterface, appConfig *config.Config, dynamoClient *dynamodb.Client, auditLog *audit.Log) *IndexController {
 	return &IndexController{
 		config: appConfig,
 		logger: logger,
-		auth:   auth.New(appConfig, logger, awsClient),
-		worker: ingestion.NewWorker(logger, appConfig, awsClient, dynamoClient),
+		auth:   auth.New(appConfig, logger, awsClient, dynamoClient, auditLog),
+		worker: ingestion.NewWorker(logger, appConfig, awsClient, dynamoClient, auditLog),
+
+		idempotency: newIdempotencyStore(appConfig, dynamoClient),
+		audit:       auditLog,
+		server: &http.Server{
+			Addr:              ":" + appConfig.HTTP.Port,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
 	}
 }
 
@@ -69,79 +94,182 @@ func (c *IndexController) HandleRequests() {
 		}
 	})
 
-	// Create the route to handle user authentication and issue JWT token
+	// Create the route to handle user authentication and issue JWT token, or
+	// revoke it on DELETE
 	http.HandleFunc("/auth/sessions", c.authHandler)
 
-	// Protect the route with JWT validation (using the authMiddleware)
+	// Publish the public keys tokens can be verified with
+	http.HandleFunc("/.well-known/jwks.json", c.jwksHandler)
+
+	// Revoke every token issued to a principal
+	http.Handle("/auth/revocations", otelhttp.NewHandler(logger.UseTelemetry(
+		c.auth.Check(http.HandlerFunc(c.revocationsHandler), auth.ScopeAdmin),
+	), "scanning"))
+
+	// Protect the route with JWT validation (using the authMiddleware). Only
+	// this route takes signed requests.
 	http.Handle("/api/ddc", otelhttp.NewHandler(logger.UseTelemetry(
-		c.auth.Check(http.HandlerFunc(c.ingestHandler)),
+		c.auth.CheckSigned(c.idempotent(http.HandlerFunc(c.ingestHandler)), auth.ScopeSubmit),
+	), "scanning"))
+
+	// Check a set as /api/ddc would, without creating a case
+	http.Handle("/api/ddc/validate", otelhttp.NewHandler(logger.UseTelemetry(
+		c.auth.Check(http.HandlerFunc(c.validateHandler), auth.ScopeSubmit),
 	), "scanning"))
 
-	c.logger.Info("Starting server on :" + c.config.HTTP.Port)
+	if c.config.HTTP.TLSCertFile == "" {
+		c.logger.Info("Starting server on :" + c.config.HTTP.Port)
 
-	server := &http.Server{
-		Addr:              ":" + c.config.HTTP.Port,
-		ReadHeaderTimeout: 5 * time.Second,
+		if err := c.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.logger.Error(err.Error())
+		}
+		return
 	}
 
-	if err := server.ListenAndServe(); err != nil {
+	tlsConfig, err := serverTLSConfig(c.config)
+	if err != nil {
+		c.logger.Error("Failed to configure TLS", slog.Any("error", err))
+		return
+	}
+	c.server.TLSConfig = tlsConfig
+
+	c.logger.Info("Starting TLS server on :" + c.config.HTTP.Port)
+
+	if err := c.server.ListenAndServeTLS(c.config.HTTP.TLSCertFile, c.config.HTTP.TLSKeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		c.logger.Error(err.Error())
 	}
 }
 
+// Shutdown stops accepting requests and waits for those in progress to finish,
+// until the context is done.
+func (c *IndexController) Shutdown(ctx context.Context) error {
+	return c.server.Shutdown(ctx)
+}
+
 func (c *IndexController) authHandler(w http.ResponseWriter, r *http.Request) {
 	// Define response error struct
 	type ErrorResponse struct {
 		Error string `json:"error"`
 	}
 
+	if r.Method == http.MethodDelete {
+		c.logoutHandler(w, r)
+		return
+	}
+
 	// Authenticate user credentials and issue JWT token
 	user, err := c.auth.Authenticate(w, r)
 	if err != nil {
 		errMsg := fmt.Sprintf("Authentication failed: %v", err)
 		c.logger.ErrorContext(r.Context(), errMsg)
-		c.authResponse(r.Context(), w, ErrorResponse{Error: errMsg})
+
+		var lockedOut auth.LockedOutError
+		if errors.As(err, &lockedOut) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(lockedOut.RetryAfter.Seconds())))
+
+			if problem.Wanted(r) {
+				c.writeProblem(r.Context(), w, problem.New(problem.TooManyLoginAttempts, r, errMsg))
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			c.authResponse(w, r, ErrorResponse{Error: errMsg})
+			return
+		}
+
+		if problem.Wanted(r) {
+			c.writeProblem(r.Context(), w, problem.New(problem.AuthenticationFailed, r, errMsg))
+			return
+		}
+
+		c.authResponse(w, r, ErrorResponse{Error: errMsg})
 		return
 	}
 
-	c.authResponse(r.Context(), w, user)
+	c.authResponse(w, r, user)
 }
 
-func (c *IndexController) authResponse(ctx context.Context, w http.ResponseWriter, resp any) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		c.respondWithError(ctx, w, http.StatusInternalServerError, "Failed to encode response", err)
+func (c *IndexController) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if err := c.auth.Logout(w, r); err != nil {
+		c.logger.InfoContext(r.Context(), "Logout failed", slog.Any("error", err))
+
+		if problem.Wanted(r) {
+			c.writeProblem(r.Context(), w, problem.New(problem.Unauthorized, r, "Unauthorized: Invalid token"))
+			return
+		}
+
+		http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
+		return
 	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (c *IndexController) ingestHandler(w http.ResponseWriter, r *http.Request) {
-	reqCtx := r.Context()
+func (c *IndexController) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		c.respondWithError(w, r, problem.MethodNotAllowed, "Invalid HTTP method", nil)
+		return
+	}
 
+	keySet, err := c.auth.KeySet(r.Context())
+	if err != nil {
+		c.respondWithError(w, r, problem.InternalError, "Failed to load signing keys", err)
+		return
+	}
+
+	// Keys are published before they are used, so they can be cached for as
+	// long as an instance might hold on to them.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	c.authResponse(w, r, keySet)
+}
+
+func (c *IndexController) revocationsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		c.respondWithError(reqCtx, w, http.StatusMethodNotAllowed, "Invalid HTTP method", nil)
+		c.respondWithError(w, r, problem.MethodNotAllowed, "Invalid HTTP method", nil)
 		return
 	}
 
-	c.logger.InfoContext(reqCtx, "Received ingestion request")
+	var revocation struct {
+		Principal string `json:"principal"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&revocation); err != nil || revocation.Principal == "" {
+		c.respondWithError(w, r, problem.InvalidRequestBody, "Invalid request body", err)
+		return
+	}
 
-	body, err := c.readRequestBody(r)
-	if err != nil {
-		c.respondWithError(reqCtx, w, http.StatusBadRequest, "Invalid request body", err)
+	if err := c.auth.RevokePrincipal(r.Context(), revocation.Principal); err != nil {
+		c.respondWithError(w, r, problem.InternalError, "Failed to revoke tokens", err)
 		return
 	}
 
-	contentType := r.Header.Get("Content-Type")
-	if !strings.HasPrefix(contentType, "application/xml") && !strings.HasPrefix(contentType, "text/xml") {
-		c.respondWithError(reqCtx, w, http.StatusBadRequest, "Invalid content type", fmt.Errorf("expected application/xml or text/xml, got %s", contentType))
+	c.logger.InfoContext(r.Context(), "Revoked tokens", slog.String("revoked_principal", revocation.Principal))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *IndexController) authResponse(w http.ResponseWriter, r *http.Request, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		c.respondWithError(w, r, problem.InternalError, "Failed to encode response", err)
+	}
+}
+
+func (c *IndexController) ingestHandler(w http.ResponseWriter, r *http.Request) {
+	reqCtx := r.Context()
+
+	c.logger.InfoContext(reqCtx, "Received ingestion request")
+
+	body, ok := c.readXMLRequest(w, r)
+	if !ok {
 		return
 	}
 
-	scannedCaseResponse, err := c.worker.Process(context.WithoutCancel(reqCtx), body)
-	if scannedCaseResponse == nil {
-		scannedCaseResponse = &sirius.ScannedCaseResponse{}
+	result, err := c.worker.Process(context.WithoutCancel(reqCtx), body)
+	if result == nil {
+		result = &ingestion.Result{}
 	}
 
-	uid := scannedCaseResponse.UID
+	uid := result.UID
 	statusCode := http.StatusAccepted
 
 	if err != nil {
@@ -150,8 +278,8 @@ func (c *IndexController) ingestHandler(w http.ResponseWriter, r *http.Request)
 			uid = aperr.CaseNo
 			statusCode = http.StatusAlreadyReported
 		} else {
-			statusCode, message := getPublicError(err, scannedCaseResponse.UID)
-			c.respondWithError(reqCtx, w, statusCode, message, err)
+			problemType, message := getPublicError(err, result.UID)
+			c.respondWithError(w, r, problemType, message, err)
 
 			return
 		}
@@ -164,9 +292,10 @@ func (c *IndexController) ingestHandler(w http.ResponseWriter, r *http.Request)
 
 	resp := response{
 		Data: responseData{
-			Success: true,
-			Message: fmt.Sprintf("The document set for case %s has been queued for processing", uid),
-			Uid:     uid,
+			Success:          true,
+			Message:          fmt.Sprintf("The document set for case %s has been queued for processing", uid),
+			Uid:              uid,
+			ValidationIssues: result.Issues,
 		},
 	}
 
@@ -178,35 +307,84 @@ func (c *IndexController) ingestHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		c.respondWithError(reqCtx, w, http.StatusInternalServerError, "Failed to encode response", err)
+		c.respondWithError(w, r, problem.InternalError, "Failed to encode response", err)
 	} else {
-		c.logger.InfoContext(reqCtx, "Ingestion request processed successfully", slog.String("uid", scannedCaseResponse.UID))
+		c.logger.InfoContext(reqCtx, "Ingestion request processed successfully", slog.String("uid", result.UID))
 	}
 }
 
-func getPublicError(err error, uid string) (int, string) {
+func (c *IndexController) validateHandler(w http.ResponseWriter, r *http.Request) {
+	reqCtx := r.Context()
+
+	c.logger.InfoContext(reqCtx, "Received validation request")
+
+	body, ok := c.readXMLRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := c.worker.Validate(reqCtx, body)
+	if err != nil {
+		var lookupError ingestion.FingerprintLookupError
+		if errors.As(err, &lookupError) {
+			c.respondWithError(w, r, problem.DuplicateCheckFailed, "Could not check for earlier submissions", err)
+			return
+		}
+
+		c.respondWithError(w, r, problem.InternalError, "Failed to validate set", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(validateResponse{Data: *report}); err != nil {
+		c.logger.ErrorContext(reqCtx, "Failed to encode response", slog.Any("error", err))
+	} else {
+		c.logger.InfoContext(reqCtx, "Validation request processed", slog.Bool("valid", report.Valid), slog.Int("issues", len(report.Issues)))
+	}
+}
+
+// getPublicError maps an error from the worker to its entry in the error
+// catalogue and the message to show the client.
+func getPublicError(err error, uid string) (problem.Type, string) {
 	if errors.Is(err, ingestion.ErrScannedCaseResponseUIDMissing) {
-		return http.StatusInternalServerError, "Invalid response from Sirius when creating case stub, scannedCaseResponse is nil or missing UID"
+		return problem.SiriusResponseInvalid, "Invalid response from Sirius when creating case stub, scannedCaseResponse is nil or missing UID"
 	}
 
 	var stubError ingestion.FailedToCreateCaseStubError
 	if errors.As(err, &stubError) {
-		return http.StatusInternalServerError, "Failed to create case stub in Sirius"
+		return problem.CaseStubFailed, "Failed to create case stub in Sirius"
+	}
+
+	var forbiddenError ingestion.ForbiddenError
+	if errors.As(err, &forbiddenError) {
+		return problem.Forbidden, "Set contains documents the client may not submit"
+	}
+
+	var duplicateError ingestion.DuplicateError
+	if errors.As(err, &duplicateError) {
+		return problem.DuplicateSubmission, "Set has already been submitted"
 	}
 
 	var setError ingestion.ValidateSetError
 	if errors.As(err, &setError) {
-		return http.StatusBadRequest, "Validate set failed"
+		return problem.SetValidationFailed, "Validate set failed"
 	}
 
 	var sanitizeError ingestion.ValidateAndSanitizeError
 	if errors.As(err, &sanitizeError) {
-		return http.StatusBadRequest, "Validate and sanitize XML failed"
+		return problem.XMLValidationFailed, "Validate and sanitize XML failed"
 	}
 
 	var persistError ingestion.PersistSetError
 	if errors.As(err, &persistError) {
-		return http.StatusInternalServerError, "Could not persist set to S3"
+		return problem.PersistSetFailed, "Could not persist set to S3"
+	}
+
+	var lookupError ingestion.FingerprintLookupError
+	if errors.As(err, &lookupError) {
+		return problem.DuplicateCheckFailed, "Could not check for earlier submissions"
 	}
 
 	var clientError sirius.Error
@@ -215,25 +393,44 @@ func getPublicError(err error, uid string) (int, string) {
 		case http.StatusBadRequest:
 			_, ok := clientError.ValidationErrors["caseReference"]
 			if ok {
-				return http.StatusBadRequest, fmt.Sprintf("%s is not a valid case UID", uid)
+				return problem.CaseReferenceInvalid, fmt.Sprintf("%s is not a valid case UID", uid)
 			}
 		case http.StatusNotFound:
-			return http.StatusBadRequest, fmt.Sprintf("Case not found with UID %s", uid)
+			return problem.CaseNotFound, fmt.Sprintf("Case not found with UID %s", uid)
 		case http.StatusRequestEntityTooLarge:
-			return http.StatusRequestEntityTooLarge, "Request content too large: the XML document exceeds the maximum allowed size"
+			return problem.PayloadTooLarge, "Request content too large: the XML document exceeds the maximum allowed size"
 		}
 	}
 
-	return http.StatusInternalServerError, "Failed to persist document to Sirius"
+	return problem.SiriusUnavailable, "Failed to persist document to Sirius"
 }
 
-func (c *IndexController) respondWithError(ctx context.Context, w http.ResponseWriter, statusCode int, message string, err error) {
-	if statusCode >= 500 {
+// respondWithError sends an error as problem details when the client asks for
+// them, and in the legacy response envelope otherwise.
+func (c *IndexController) respondWithError(w http.ResponseWriter, r *http.Request, problemType problem.Type, message string, err error) {
+	ctx := r.Context()
+
+	if problemType.Status >= 500 {
 		c.logger.ErrorContext(ctx, message, slog.Any("error", err))
 	} else {
 		c.logger.InfoContext(ctx, message, slog.Any("error", err))
 	}
 
+	var ingestionProblem ingestion.Problem
+	isIngestionProblem := errors.As(err, &ingestionProblem)
+
+	if problem.Wanted(r) {
+		details := problem.New(problemType, r, message)
+		if isIngestionProblem {
+			details.Detail = ingestionProblem.Title
+			details.ValidationErrors = ingestionProblem.ValidationErrors
+			details.ValidationIssues = ingestionProblem.Issues
+		}
+
+		c.writeProblem(ctx, w, details)
+		return
+	}
+
 	resp := response{
 		Data: responseData{
 			Success: false,
@@ -241,26 +438,55 @@ func (c *IndexController) respondWithError(ctx context.Context, w http.ResponseW
 		},
 	}
 
-	var problem ingestion.Problem
-	if errors.As(err, &problem) {
-		resp.Data.Message = problem.Title
-		resp.Data.ValidationErrors = problem.ValidationErrors
+	if isIngestionProblem {
+		resp.Data.Message = ingestionProblem.Title
+		resp.Data.ValidationErrors = ingestionProblem.ValidationErrors
+		resp.Data.ValidationIssues = ingestionProblem.Issues
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	w.WriteHeader(problemType.Status)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		c.respondWithError(ctx, w, http.StatusInternalServerError, "Failed to encode response", err)
+		c.logger.ErrorContext(ctx, "Failed to encode response", slog.Any("error", err))
+	}
+}
+
+func (c *IndexController) writeProblem(ctx context.Context, w http.ResponseWriter, details problem.Details) {
+	if err := problem.Write(w, details); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to encode response", slog.Any("error", err))
+	}
+}
+
+// readXMLRequest reads the body of a request that posts a set. If the request
+// is not a POST of XML, an error is sent and ok is false.
+func (c *IndexController) readXMLRequest(w http.ResponseWriter, r *http.Request) (body []byte, ok bool) {
+	if r.Method != http.MethodPost {
+		c.respondWithError(w, r, problem.MethodNotAllowed, "Invalid HTTP method", nil)
+		return nil, false
+	}
+
+	body, err := c.readRequestBody(w, r)
+	if err != nil {
+		c.respondWithError(w, r, problem.InvalidRequestBody, "Invalid request body", err)
+		return nil, false
 	}
+
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/xml") && !strings.HasPrefix(contentType, "text/xml") {
+		c.respondWithError(w, r, problem.InvalidContentType, "Invalid content type", fmt.Errorf("expected application/xml or text/xml, got %s", contentType))
+		return nil, false
+	}
+
+	return body, true
 }
 
-func (c *IndexController) readRequestBody(r *http.Request) ([]byte, error) {
+func (c *IndexController) readRequestBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
 	if r.Body == nil {
 		return nil, errors.New("request body is empty")
 	}
 
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, constants.MaxRequestBodySize))
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -10,11 +9,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/ministryofjustice/opg-scanning/internal/problem"
 	"github.com/ministryofjustice/opg-scanning/internal/sirius"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -41,7 +43,7 @@ func setupController(t *testing.T) *IndexController {
 	worker := newMockWorker(t)
 	worker.EXPECT().
 		Process(mock.Anything, mock.Anything).
-		Return(&sirius.ScannedCaseResponse{UID: "700012341234"}, nil).
+		Return(&ingestion.Result{UID: "700012341234"}, nil).
 		Maybe()
 
 	return &IndexController{
@@ -59,9 +61,6 @@ func TestIngestHandler_SetValid(t *testing.T) {
 	req.Header.Set("Content-Type", "application/xml")
 	w := httptest.NewRecorder()
 
-	reqCtx := context.WithValue(context.Background(), constants.TokenContextKey, "my-token")
-	req = req.WithContext(reqCtx)
-
 	controller.ingestHandler(w, req)
 
 	resp := w.Result()
@@ -78,6 +77,39 @@ func TestIngestHandler_SetValid(t *testing.T) {
 	assert.Equal(t, "700012341234", responseObj.Data.Uid)
 }
 
+func TestIngestHandler_SetValidWithIssues(t *testing.T) {
+	issues := []validation.Issue{{
+		DocumentID:   "2",
+		DocumentType: "LP2",
+		Field:        "Page1.Section1",
+		Code:         "selection-invalid",
+		Severity:     validation.SeverityWarning,
+		Message:      "Neither LPA sub-type is selected",
+	}}
+
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(&ingestion.Result{UID: "700012341234", Issues: issues}, nil)
+
+	controller := setupController(t)
+	controller.worker = worker
+
+	req := httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewBuffer([]byte(xmlPayload)))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+
+	controller.ingestHandler(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var responseObj response
+	jsonUnmarshalReader(resp.Body, &responseObj)
+	assert.True(t, responseObj.Data.Success)
+	assert.Equal(t, issues, responseObj.Data.ValidationIssues)
+}
+
 func TestIngestHandler_InvalidContentType(t *testing.T) {
 	controller := setupController(t)
 
@@ -134,7 +166,15 @@ func TestIngestHandler_InvalidXMLExplainsXSDErrors(t *testing.T) {
 	worker.EXPECT().
 		Process(mock.Anything, mock.Anything).
 		Return(nil, ingestion.ValidateAndSanitizeError{
-			Err: ingestion.Problem{ValidationErrors: []string{"Element 'Set': Missing child element(s). Expected is ( Body )."}},
+			Err: ingestion.Problem{
+				ValidationErrors: []string{"Element 'Set': Missing child element(s). Expected is ( Body )."},
+				Issues: []validation.Issue{{
+					Field:    "Set",
+					Code:     "schema-invalid",
+					Severity: validation.SeverityError,
+					Message:  "Element 'Set': Missing child element(s). Expected is ( Body ).",
+				}},
+			},
 		})
 
 	controller.worker = worker
@@ -151,6 +191,10 @@ func TestIngestHandler_InvalidXMLExplainsXSDErrors(t *testing.T) {
 	assert.Nil(t, err)
 	assert.False(t, responseObj.Data.Success)
 	assert.Contains(t, responseObj.Data.ValidationErrors, "Element 'Set': Missing child element(s). Expected is ( Body ).")
+	if assert.Len(t, responseObj.Data.ValidationIssues, 1) {
+		assert.Equal(t, "Set", responseObj.Data.ValidationIssues[0].Field)
+		assert.Equal(t, "schema-invalid", responseObj.Data.ValidationIssues[0].Code)
+	}
 }
 
 func TestIngestHandler_SiriusErrors(t *testing.T) {
@@ -209,6 +253,21 @@ func TestIngestHandler_SiriusErrors(t *testing.T) {
 			expectedStatusCode: 500,
 			expectedMessage:    "Failed to persist document to Sirius",
 		},
+		"document type not permitted": {
+			siriusError:        ingestion.ForbiddenError{Err: ingestion.Problem{Title: "Set contains documents the client may not submit"}},
+			expectedStatusCode: 403,
+			expectedMessage:    "Set contains documents the client may not submit",
+		},
+		"duplicate": {
+			siriusError:        ingestion.DuplicateError{Err: ingestion.Problem{Title: "Set has already been submitted"}},
+			expectedStatusCode: 409,
+			expectedMessage:    "Set has already been submitted",
+		},
+		"earlier submissions unavailable": {
+			siriusError:        ingestion.FingerprintLookupError{Err: errors.New("dynamodb unavailable")},
+			expectedStatusCode: 500,
+			expectedMessage:    "Could not check for earlier submissions",
+		},
 		"other error": {
 			siriusError:        errors.New("a generic error"),
 			expectedStatusCode: 500,
@@ -223,16 +282,13 @@ func TestIngestHandler_SiriusErrors(t *testing.T) {
 			worker := newMockWorker(t)
 			worker.EXPECT().
 				Process(mock.Anything, mock.Anything).
-				Return(&sirius.ScannedCaseResponse{UID: "700012341234"}, tc.siriusError)
+				Return(&ingestion.Result{UID: "700012341234"}, tc.siriusError)
 			controller.worker = worker
 
 			req := httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewBuffer([]byte(xmlPayloadCorrespondence)))
 			req.Header.Set("Content-Type", "application/xml")
 			w := httptest.NewRecorder()
 
-			reqCtx := context.WithValue(context.Background(), constants.TokenContextKey, "my-token")
-			req = req.WithContext(reqCtx)
-
 			controller.ingestHandler(w, req)
 
 			resp := w.Result()
@@ -274,9 +330,6 @@ func TestIngestHandler_DuplicateRequest(t *testing.T) {
 	req.Header.Set("Content-Type", "application/xml")
 	w := httptest.NewRecorder()
 
-	reqCtx := context.WithValue(context.Background(), constants.TokenContextKey, "my-token")
-	req = req.WithContext(reqCtx)
-
 	controller.ingestHandler(w, req)
 
 	resp := w.Result()
@@ -291,8 +344,261 @@ func TestIngestHandler_DuplicateRequest(t *testing.T) {
 	assert.Equal(t, "Document has already been processed", responseObj.Data.Message)
 }
 
+func TestValidateHandler(t *testing.T) {
+	controller := setupController(t)
+
+	report := &ingestion.Report{
+		Valid: false,
+		Issues: []validation.Issue{{
+			DocumentType: "LP2",
+			Field:        "Page5.Section5.Attorney[0].Date",
+			Code:         "date-invalid",
+			Severity:     validation.SeverityError,
+			Message:      "Page5 Section5 Attorney 0 date is invalid",
+		}},
+	}
+
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Validate(mock.Anything, []byte(xmlPayload)).
+		Return(report, nil)
+	controller.worker = worker
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ddc/validate", bytes.NewBuffer([]byte(xmlPayload)))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+
+	controller.validateHandler(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseObj validateResponse
+	jsonUnmarshalReader(resp.Body, &responseObj)
+
+	assert.Equal(t, *report, responseObj.Data)
+}
+
+func TestValidateHandler_EarlierSubmissionsUnavailable(t *testing.T) {
+	controller := setupController(t)
+
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Validate(mock.Anything, []byte(xmlPayload)).
+		Return(nil, ingestion.FingerprintLookupError{Err: errors.New("dynamodb unavailable")})
+	controller.worker = worker
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ddc/validate", bytes.NewBuffer([]byte(xmlPayload)))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+
+	controller.validateHandler(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	var details problem.Details
+	jsonUnmarshalReader(resp.Body, &details)
+
+	assert.Equal(t, problem.DuplicateCheckFailed.URI(), details.Type)
+}
+
+func TestValidateHandler_InvalidMethod(t *testing.T) {
+	controller := setupController(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ddc/validate", nil)
+	w := httptest.NewRecorder()
+
+	controller.validateHandler(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Result().StatusCode)
+}
+
+func TestAuthHandler_LockedOut(t *testing.T) {
+	testCases := map[string]struct {
+		accept      string
+		contentType string
+	}{
+		"legacy": {
+			contentType: "application/json",
+		},
+		"problem details": {
+			accept:      problem.ContentType,
+			contentType: problem.ContentType,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			controller := setupController(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/sessions", nil)
+			req.Header.Set("Accept", tc.accept)
+			w := httptest.NewRecorder()
+
+			mockAuth := newMockAuth(t)
+			mockAuth.EXPECT().
+				Authenticate(w, req).
+				Return(auth.AuthenticatedUser{}, auth.LockedOutError{RetryAfter: 90 * time.Second})
+			controller.auth = mockAuth
+
+			controller.authHandler(w, req)
+
+			resp := w.Result()
+			assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+			assert.Equal(t, "90", resp.Header.Get("Retry-After"))
+			assert.Equal(t, tc.contentType, resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	controller := setupController(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/auth/sessions", nil)
+	w := httptest.NewRecorder()
+
+	mockAuth := newMockAuth(t)
+	mockAuth.EXPECT().
+		Logout(w, req).
+		Return(nil)
+	controller.auth = mockAuth
+
+	controller.authHandler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+}
+
+func TestAuthHandler_LogoutInvalidToken(t *testing.T) {
+	controller := setupController(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/auth/sessions", nil)
+	w := httptest.NewRecorder()
+
+	mockAuth := newMockAuth(t)
+	mockAuth.EXPECT().
+		Logout(w, req).
+		Return(errors.New("invalid token"))
+	controller.auth = mockAuth
+
+	controller.authHandler(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	controller := setupController(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	keySet := auth.KeySet{Keys: []auth.JSONWebKey{{KeyType: "EC", ID: "2026-03", Algorithm: "ES256", Use: "sig", Curve: "P-256", X: "x", Y: "y"}}}
+
+	mockAuth := newMockAuth(t)
+	mockAuth.EXPECT().
+		KeySet(mock.Anything).
+		Return(keySet, nil)
+	controller.auth = mockAuth
+
+	controller.jwksHandler(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=300", resp.Header.Get("Cache-Control"))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"keys":[{"kty":"EC","kid":"2026-03","alg":"ES256","use":"sig","crv":"P-256","x":"x","y":"y"}]}`, string(body))
+}
+
+func TestJWKSHandler_Errors(t *testing.T) {
+	controller := setupController(t)
+
+	w := httptest.NewRecorder()
+	controller.jwksHandler(w, httptest.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Result().StatusCode)
+
+	mockAuth := newMockAuth(t)
+	mockAuth.EXPECT().
+		KeySet(mock.Anything).
+		Return(auth.KeySet{}, errors.New("secrets manager down"))
+	controller.auth = mockAuth
+
+	w = httptest.NewRecorder()
+	controller.jwksHandler(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+}
+
+func TestRevocationsHandler(t *testing.T) {
+	controller := setupController(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/revocations", bytes.NewBufferString(`{"principal":"cop@example.com"}`))
+	w := httptest.NewRecorder()
+
+	mockAuth := newMockAuth(t)
+	mockAuth.EXPECT().
+		RevokePrincipal(mock.Anything, "cop@example.com").
+		Return(nil)
+	controller.auth = mockAuth
+
+	controller.revocationsHandler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+}
+
+func TestRevocationsHandler_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		method     string
+		body       string
+		revokeErr  error
+		statusCode int
+	}{
+		"invalid method": {
+			method:     http.MethodGet,
+			statusCode: http.StatusMethodNotAllowed,
+		},
+		"invalid body": {
+			method:     http.MethodPost,
+			body:       `{"principal":`,
+			statusCode: http.StatusBadRequest,
+		},
+		"missing principal": {
+			method:     http.MethodPost,
+			body:       `{}`,
+			statusCode: http.StatusBadRequest,
+		},
+		"revoke fails": {
+			method:     http.MethodPost,
+			body:       `{"principal":"cop@example.com"}`,
+			revokeErr:  errors.New("dynamo down"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			controller := setupController(t)
+
+			if tc.revokeErr != nil {
+				mockAuth := newMockAuth(t)
+				mockAuth.EXPECT().
+					RevokePrincipal(mock.Anything, "cop@example.com").
+					Return(tc.revokeErr)
+				controller.auth = mockAuth
+			}
+
+			req := httptest.NewRequest(tc.method, "/auth/revocations", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+
+			controller.revocationsHandler(w, req)
+
+			assert.Equal(t, tc.statusCode, w.Result().StatusCode)
+		})
+	}
+}
+
 func TestRespondWithErrorHandle5XX(t *testing.T) {
-	ctx := context.Background()
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc", nil)
 	w := httptest.NewRecorder()
 
 	c := setupController(t)
@@ -300,7 +606,7 @@ func TestRespondWithErrorHandle5XX(t *testing.T) {
 	outBuf := bytes.NewBuffer([]byte{})
 	c.logger = slog.New(slog.NewJSONHandler(outBuf, nil))
 
-	c.respondWithError(ctx, w, 500, "something went wrong", errors.New("what really went wrong"))
+	c.respondWithError(w, r, problem.InternalError, "something went wrong", errors.New("what really went wrong"))
 
 	var logMessage map[string]string
 	jsonUnmarshalReader(outBuf, &logMessage)
@@ -319,7 +625,7 @@ func TestRespondWithErrorHandle5XX(t *testing.T) {
 }
 
 func TestRespondWithErrorHandle4XX(t *testing.T) {
-	ctx := context.Background()
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc", nil)
 	w := httptest.NewRecorder()
 
 	c := setupController(t)
@@ -327,7 +633,7 @@ func TestRespondWithErrorHandle4XX(t *testing.T) {
 	outBuf := bytes.NewBuffer([]byte{})
 	c.logger = slog.New(slog.NewJSONHandler(outBuf, nil))
 
-	c.respondWithError(ctx, w, 400, "you sent us something wrong", errors.New("what really went wrong"))
+	c.respondWithError(w, r, problem.InvalidRequestBody, "you sent us something wrong", errors.New("what really went wrong"))
 
 	var logMessage map[string]string
 	jsonUnmarshalReader(outBuf, &logMessage)
@@ -345,6 +651,77 @@ func TestRespondWithErrorHandle4XX(t *testing.T) {
 	assert.Equal(t, "you sent us something wrong", respBody.Data.Message)
 }
 
+func TestRespondWithErrorProblemDetails(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc", nil)
+	r.Header.Set("Accept", "application/json, application/problem+json")
+	w := httptest.NewRecorder()
+
+	c := setupController(t)
+
+	c.respondWithError(w, r, problem.SetValidationFailed, "Validate set failed", ingestion.ValidateSetError{
+		Err: ingestion.Problem{
+			Title:            "Set failed cross-document checks",
+			ValidationErrors: []string{"LP2 donor does not match"},
+			Issues: []validation.Issue{{
+				DocumentID: "2",
+				Code:       "donor-name-mismatch",
+				Severity:   validation.SeverityError,
+				Message:    "LP2 donor does not match",
+			}},
+		},
+	})
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+
+	var details problem.Details
+	jsonUnmarshalReader(resp.Body, &details)
+
+	assert.Equal(t, problem.Details{
+		Type:             "urn:opg-scanning:problem:set-validation-failed",
+		Title:            "Validate set failed",
+		Status:           http.StatusBadRequest,
+		Detail:           "Set failed cross-document checks",
+		Instance:         "/api/ddc",
+		Code:             "set-validation-failed",
+		ValidationErrors: []string{"LP2 donor does not match"},
+		ValidationIssues: []validation.Issue{{
+			DocumentID: "2",
+			Code:       "donor-name-mismatch",
+			Severity:   validation.SeverityError,
+			Message:    "LP2 donor does not match",
+		}},
+	}, details)
+}
+
+func TestIngestHandler_SiriusErrorsAsProblemDetails(t *testing.T) {
+	controller := setupController(t)
+
+	worker := newMockWorker(t)
+	worker.EXPECT().
+		Process(mock.Anything, mock.Anything).
+		Return(&ingestion.Result{UID: "700012341234"}, sirius.Error{StatusCode: 404})
+	controller.worker = worker
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ddc", bytes.NewBuffer([]byte(xmlPayload)))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+
+	controller.ingestHandler(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var details problem.Details
+	jsonUnmarshalReader(resp.Body, &details)
+
+	assert.Equal(t, "case-not-found", details.Code)
+	assert.Equal(t, "Case not found", details.Title)
+	assert.Equal(t, "Case not found with UID 700012341234", details.Detail)
+}
+
 func jsonUnmarshalReader(reader io.Reader, v any) {
 	body, err := io.ReadAll(reader)
 
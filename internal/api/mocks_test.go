@@ -8,11 +8,440 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/ministryofjustice/opg-scanning/internal/auth"
-	"github.com/ministryofjustice/opg-scanning/internal/sirius"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
 	mock "github.com/stretchr/testify/mock"
 )
 
+// newMockIdempotencyStore creates a new instance of mockIdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockIdempotencyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockIdempotencyStore {
+	mock := &mockIdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockIdempotencyStore is an autogenerated mock type for the idempotencyStore type
+type mockIdempotencyStore struct {
+	mock.Mock
+}
+
+type mockIdempotencyStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockIdempotencyStore) EXPECT() *mockIdempotencyStore_Expecter {
+	return &mockIdempotencyStore_Expecter{mock: &_m.Mock}
+}
+
+// Complete provides a mock function for the type mockIdempotencyStore
+func (_mock *mockIdempotencyStore) Complete(ctx context.Context, key string, claim idempotencyRecord, record idempotencyRecord) error {
+	ret := _mock.Called(ctx, key, claim, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, idempotencyRecord, idempotencyRecord) error); ok {
+		r0 = returnFunc(ctx, key, claim, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockIdempotencyStore_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type mockIdempotencyStore_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - claim idempotencyRecord
+//   - record idempotencyRecord
+func (_e *mockIdempotencyStore_Expecter) Complete(ctx interface{}, key interface{}, claim interface{}, record interface{}) *mockIdempotencyStore_Complete_Call {
+	return &mockIdempotencyStore_Complete_Call{Call: _e.mock.On("Complete", ctx, key, claim, record)}
+}
+
+func (_c *mockIdempotencyStore_Complete_Call) Run(run func(ctx context.Context, key string, claim idempotencyRecord, record idempotencyRecord)) *mockIdempotencyStore_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 idempotencyRecord
+		if args[2] != nil {
+			arg2 = args[2].(idempotencyRecord)
+		}
+		var arg3 idempotencyRecord
+		if args[3] != nil {
+			arg3 = args[3].(idempotencyRecord)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Complete_Call) Return(err error) *mockIdempotencyStore_Complete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Complete_Call) RunAndReturn(run func(ctx context.Context, key string, claim idempotencyRecord, record idempotencyRecord) error) *mockIdempotencyStore_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type mockIdempotencyStore
+func (_mock *mockIdempotencyStore) Release(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockIdempotencyStore_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type mockIdempotencyStore_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *mockIdempotencyStore_Expecter) Release(ctx interface{}, key interface{}) *mockIdempotencyStore_Release_Call {
+	return &mockIdempotencyStore_Release_Call{Call: _e.mock.On("Release", ctx, key)}
+}
+
+func (_c *mockIdempotencyStore_Release_Call) Run(run func(ctx context.Context, key string)) *mockIdempotencyStore_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Release_Call) Return(err error) *mockIdempotencyStore_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Release_Call) RunAndReturn(run func(ctx context.Context, key string) error) *mockIdempotencyStore_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Start provides a mock function for the type mockIdempotencyStore
+func (_mock *mockIdempotencyStore) Start(ctx context.Context, key string, record idempotencyRecord) (*idempotencyRecord, error) {
+	ret := _mock.Called(ctx, key, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 *idempotencyRecord
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, idempotencyRecord) (*idempotencyRecord, error)); ok {
+		return returnFunc(ctx, key, record)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, idempotencyRecord) *idempotencyRecord); ok {
+		r0 = returnFunc(ctx, key, record)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*idempotencyRecord)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, idempotencyRecord) error); ok {
+		r1 = returnFunc(ctx, key, record)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockIdempotencyStore_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type mockIdempotencyStore_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - record idempotencyRecord
+func (_e *mockIdempotencyStore_Expecter) Start(ctx interface{}, key interface{}, record interface{}) *mockIdempotencyStore_Start_Call {
+	return &mockIdempotencyStore_Start_Call{Call: _e.mock.On("Start", ctx, key, record)}
+}
+
+func (_c *mockIdempotencyStore_Start_Call) Run(run func(ctx context.Context, key string, record idempotencyRecord)) *mockIdempotencyStore_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 idempotencyRecord
+		if args[2] != nil {
+			arg2 = args[2].(idempotencyRecord)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Start_Call) Return(idempotencyRecordMoqParam *idempotencyRecord, err error) *mockIdempotencyStore_Start_Call {
+	_c.Call.Return(idempotencyRecordMoqParam, err)
+	return _c
+}
+
+func (_c *mockIdempotencyStore_Start_Call) RunAndReturn(run func(ctx context.Context, key string, record idempotencyRecord) (*idempotencyRecord, error)) *mockIdempotencyStore_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDynamoClient creates a new instance of mockDynamoClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDynamoClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDynamoClient {
+	mock := &mockDynamoClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockDynamoClient is an autogenerated mock type for the dynamoClient type
+type mockDynamoClient struct {
+	mock.Mock
+}
+
+type mockDynamoClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDynamoClient) EXPECT() *mockDynamoClient_Expecter {
+	return &mockDynamoClient_Expecter{mock: &_m.Mock}
+}
+
+// DeleteItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 *dynamodb.DeleteItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) *dynamodb.DeleteItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DeleteItemOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockDynamoClient_DeleteItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteItem'
+type mockDynamoClient_DeleteItem_Call struct {
+	*mock.Call
+}
+
+// DeleteItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DeleteItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) DeleteItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_DeleteItem_Call {
+	return &mockDynamoClient_DeleteItem_Call{Call: _e.mock.On("DeleteItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockDynamoClient_DeleteItem_Call) Run(run func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.DeleteItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.DeleteItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockDynamoClient_DeleteItem_Call) Return(deleteItemOutput *dynamodb.DeleteItemOutput, err error) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Return(deleteItemOutput, err)
+	return _c
+}
+
+func (_c *mockDynamoClient_DeleteItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutItem")
+	}
+
+	var r0 *dynamodb.PutItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) *dynamodb.PutItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.PutItemOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockDynamoClient_PutItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutItem'
+type mockDynamoClient_PutItem_Call struct {
+	*mock.Call
+}
+
+// PutItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.PutItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) PutItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_PutItem_Call {
+	return &mockDynamoClient_PutItem_Call{Call: _e.mock.On("PutItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockDynamoClient_PutItem_Call) Run(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_PutItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.PutItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.PutItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockDynamoClient_PutItem_Call) Return(putItemOutput *dynamodb.PutItemOutput, err error) *mockDynamoClient_PutItem_Call {
+	_c.Call.Return(putItemOutput, err)
+	return _c
+}
+
+func (_c *mockDynamoClient_PutItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)) *mockDynamoClient_PutItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockAuth creates a new instance of mockAuth. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockAuth(t interface {
@@ -107,16 +536,24 @@ func (_c *mockAuth_Authenticate_Call) RunAndReturn(run func(w http.ResponseWrite
 }
 
 // Check provides a mock function for the type mockAuth
-func (_mock *mockAuth) Check(next http.Handler) http.HandlerFunc {
-	ret := _mock.Called(next)
+func (_mock *mockAuth) Check(next http.Handler, scopes ...string) http.HandlerFunc {
+	// string
+	_va := make([]interface{}, len(scopes))
+	for _i := range scopes {
+		_va[_i] = scopes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, next)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Check")
 	}
 
 	var r0 http.HandlerFunc
-	if returnFunc, ok := ret.Get(0).(func(http.Handler) http.HandlerFunc); ok {
-		r0 = returnFunc(next)
+	if returnFunc, ok := ret.Get(0).(func(http.Handler, ...string) http.HandlerFunc); ok {
+		r0 = returnFunc(next, scopes...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(http.HandlerFunc)
@@ -132,18 +569,29 @@ type mockAuth_Check_Call struct {
 
 // Check is a helper method to define mock.On call
 //   - next http.Handler
-func (_e *mockAuth_Expecter) Check(next interface{}) *mockAuth_Check_Call {
-	return &mockAuth_Check_Call{Call: _e.mock.On("Check", next)}
+//   - scopes ...string
+func (_e *mockAuth_Expecter) Check(next interface{}, scopes ...interface{}) *mockAuth_Check_Call {
+	return &mockAuth_Check_Call{Call: _e.mock.On("Check",
+		append([]interface{}{next}, scopes...)...)}
 }
 
-func (_c *mockAuth_Check_Call) Run(run func(next http.Handler)) *mockAuth_Check_Call {
+func (_c *mockAuth_Check_Call) Run(run func(next http.Handler, scopes ...string)) *mockAuth_Check_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 http.Handler
 		if args[0] != nil {
 			arg0 = args[0].(http.Handler)
 		}
+		var arg1 []string
+		variadicArgs := make([]string, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		arg1 = variadicArgs
 		run(
 			arg0,
+			arg1...,
 		)
 	})
 	return _c
@@ -154,7 +602,253 @@ func (_c *mockAuth_Check_Call) Return(handlerFunc http.HandlerFunc) *mockAuth_Ch
 	return _c
 }
 
-func (_c *mockAuth_Check_Call) RunAndReturn(run func(next http.Handler) http.HandlerFunc) *mockAuth_Check_Call {
+func (_c *mockAuth_Check_Call) RunAndReturn(run func(next http.Handler, scopes ...string) http.HandlerFunc) *mockAuth_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckSigned provides a mock function for the type mockAuth
+func (_mock *mockAuth) CheckSigned(next http.Handler, scopes ...string) http.HandlerFunc {
+	// string
+	_va := make([]interface{}, len(scopes))
+	for _i := range scopes {
+		_va[_i] = scopes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, next)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckSigned")
+	}
+
+	var r0 http.HandlerFunc
+	if returnFunc, ok := ret.Get(0).(func(http.Handler, ...string) http.HandlerFunc); ok {
+		r0 = returnFunc(next, scopes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.HandlerFunc)
+		}
+	}
+	return r0
+}
+
+// mockAuth_CheckSigned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckSigned'
+type mockAuth_CheckSigned_Call struct {
+	*mock.Call
+}
+
+// CheckSigned is a helper method to define mock.On call
+//   - next http.Handler
+//   - scopes ...string
+func (_e *mockAuth_Expecter) CheckSigned(next interface{}, scopes ...interface{}) *mockAuth_CheckSigned_Call {
+	return &mockAuth_CheckSigned_Call{Call: _e.mock.On("CheckSigned",
+		append([]interface{}{next}, scopes...)...)}
+}
+
+func (_c *mockAuth_CheckSigned_Call) Run(run func(next http.Handler, scopes ...string)) *mockAuth_CheckSigned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 http.Handler
+		if args[0] != nil {
+			arg0 = args[0].(http.Handler)
+		}
+		var arg1 []string
+		variadicArgs := make([]string, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAuth_CheckSigned_Call) Return(handlerFunc http.HandlerFunc) *mockAuth_CheckSigned_Call {
+	_c.Call.Return(handlerFunc)
+	return _c
+}
+
+func (_c *mockAuth_CheckSigned_Call) RunAndReturn(run func(next http.Handler, scopes ...string) http.HandlerFunc) *mockAuth_CheckSigned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// KeySet provides a mock function for the type mockAuth
+func (_mock *mockAuth) KeySet(ctx context.Context) (auth.KeySet, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KeySet")
+	}
+
+	var r0 auth.KeySet
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (auth.KeySet, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) auth.KeySet); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(auth.KeySet)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAuth_KeySet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KeySet'
+type mockAuth_KeySet_Call struct {
+	*mock.Call
+}
+
+// KeySet is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockAuth_Expecter) KeySet(ctx interface{}) *mockAuth_KeySet_Call {
+	return &mockAuth_KeySet_Call{Call: _e.mock.On("KeySet", ctx)}
+}
+
+func (_c *mockAuth_KeySet_Call) Run(run func(ctx context.Context)) *mockAuth_KeySet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAuth_KeySet_Call) Return(keySet auth.KeySet, err error) *mockAuth_KeySet_Call {
+	_c.Call.Return(keySet, err)
+	return _c
+}
+
+func (_c *mockAuth_KeySet_Call) RunAndReturn(run func(ctx context.Context) (auth.KeySet, error)) *mockAuth_KeySet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function for the type mockAuth
+func (_mock *mockAuth) Logout(w http.ResponseWriter, r *http.Request) error {
+	ret := _mock.Called(w, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) error); ok {
+		r0 = returnFunc(w, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockAuth_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type mockAuth_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - w http.ResponseWriter
+//   - r *http.Request
+func (_e *mockAuth_Expecter) Logout(w interface{}, r interface{}) *mockAuth_Logout_Call {
+	return &mockAuth_Logout_Call{Call: _e.mock.On("Logout", w, r)}
+}
+
+func (_c *mockAuth_Logout_Call) Run(run func(w http.ResponseWriter, r *http.Request)) *mockAuth_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 http.ResponseWriter
+		if args[0] != nil {
+			arg0 = args[0].(http.ResponseWriter)
+		}
+		var arg1 *http.Request
+		if args[1] != nil {
+			arg1 = args[1].(*http.Request)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAuth_Logout_Call) Return(err error) *mockAuth_Logout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockAuth_Logout_Call) RunAndReturn(run func(w http.ResponseWriter, r *http.Request) error) *mockAuth_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokePrincipal provides a mock function for the type mockAuth
+func (_mock *mockAuth) RevokePrincipal(ctx context.Context, id string) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokePrincipal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockAuth_RevokePrincipal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokePrincipal'
+type mockAuth_RevokePrincipal_Call struct {
+	*mock.Call
+}
+
+// RevokePrincipal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *mockAuth_Expecter) RevokePrincipal(ctx interface{}, id interface{}) *mockAuth_RevokePrincipal_Call {
+	return &mockAuth_RevokePrincipal_Call{Call: _e.mock.On("RevokePrincipal", ctx, id)}
+}
+
+func (_c *mockAuth_RevokePrincipal_Call) Run(run func(ctx context.Context, id string)) *mockAuth_RevokePrincipal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAuth_RevokePrincipal_Call) Return(err error) *mockAuth_RevokePrincipal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockAuth_RevokePrincipal_Call) RunAndReturn(run func(ctx context.Context, id string) error) *mockAuth_RevokePrincipal_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -187,23 +881,23 @@ func (_m *mockWorker) EXPECT() *mockWorker_Expecter {
 }
 
 // Process provides a mock function for the type mockWorker
-func (_mock *mockWorker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseResponse, error) {
+func (_mock *mockWorker) Process(ctx context.Context, body []byte) (*ingestion.Result, error) {
 	ret := _mock.Called(ctx, body)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Process")
 	}
 
-	var r0 *sirius.ScannedCaseResponse
+	var r0 *ingestion.Result
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) (*sirius.ScannedCaseResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) (*ingestion.Result, error)); ok {
 		return returnFunc(ctx, body)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) *sirius.ScannedCaseResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) *ingestion.Result); ok {
 		r0 = returnFunc(ctx, body)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sirius.ScannedCaseResponse)
+			r0 = ret.Get(0).(*ingestion.Result)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
@@ -244,12 +938,80 @@ func (_c *mockWorker_Process_Call) Run(run func(ctx context.Context, body []byte
 	return _c
 }
 
-func (_c *mockWorker_Process_Call) Return(scannedCaseResponse *sirius.ScannedCaseResponse, err error) *mockWorker_Process_Call {
-	_c.Call.Return(scannedCaseResponse, err)
+func (_c *mockWorker_Process_Call) Return(result *ingestion.Result, err error) *mockWorker_Process_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *mockWorker_Process_Call) RunAndReturn(run func(ctx context.Context, body []byte) (*ingestion.Result, error)) *mockWorker_Process_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Validate provides a mock function for the type mockWorker
+func (_mock *mockWorker) Validate(ctx context.Context, body []byte) (*ingestion.Report, error) {
+	ret := _mock.Called(ctx, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 *ingestion.Report
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) (*ingestion.Report, error)); ok {
+		return returnFunc(ctx, body)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) *ingestion.Report); ok {
+		r0 = returnFunc(ctx, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ingestion.Report)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = returnFunc(ctx, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockWorker_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type mockWorker_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - body []byte
+func (_e *mockWorker_Expecter) Validate(ctx interface{}, body interface{}) *mockWorker_Validate_Call {
+	return &mockWorker_Validate_Call{Call: _e.mock.On("Validate", ctx, body)}
+}
+
+func (_c *mockWorker_Validate_Call) Run(run func(ctx context.Context, body []byte)) *mockWorker_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []byte
+		if args[1] != nil {
+			arg1 = args[1].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockWorker_Validate_Call) Return(report *ingestion.Report, err error) *mockWorker_Validate_Call {
+	_c.Call.Return(report, err)
 	return _c
 }
 
-func (_c *mockWorker_Process_Call) RunAndReturn(run func(ctx context.Context, body []byte) (*sirius.ScannedCaseResponse, error)) *mockWorker_Process_Call {
+func (_c *mockWorker_Validate_Call) RunAndReturn(run func(ctx context.Context, body []byte) (*ingestion.Report, error)) *mockWorker_Validate_Call {
 	_c.Call.Return(run)
 	return _c
 }
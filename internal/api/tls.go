@@ -0,0 +1,47 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+)
+
+// serverTLSConfig is the TLS config to serve HTTPS with. When there is a client
+// CA, clients are asked for certificates signed by it, and must send one if
+// RequireClientCert is set. Otherwise certificates are optional, so that
+// clients can still log in with a password.
+func serverTLSConfig(appConfig *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if appConfig.HTTP.TLSClientCAFile == "" {
+		if appConfig.HTTP.TLSRequireClientCert {
+			return nil, errors.New("client certificates are required, but no client CA is set")
+		}
+
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(appConfig.HTTP.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("client CA file contains no certificates")
+	}
+
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if appConfig.HTTP.TLSRequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+func writeCertificatePEM(t *testing.T, cert *x509.Certificate) string {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600))
+
+	return path
+}
+
+func TestServerTLSConfig_ClientCertificates(t *testing.T) {
+	ca := newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	otherCA := newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Other CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+
+	client := newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "scanner"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	untrusted := newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "scanner"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, otherCA)
+
+	testCases := map[string]struct {
+		require  bool
+		cert     *testCertificate
+		ok       bool
+		verified bool
+	}{
+		"trusted certificate": {
+			cert:     client,
+			ok:       true,
+			verified: true,
+		},
+		"untrusted certificate": {
+			cert: untrusted,
+		},
+		"no certificate": {
+			ok: true,
+		},
+		"no certificate when required": {
+			require: true,
+		},
+		"trusted certificate when required": {
+			require:  true,
+			cert:     client,
+			ok:       true,
+			verified: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			appConfig := &config.Config{}
+			appConfig.HTTP.TLSClientCAFile = writeCertificatePEM(t, ca.cert)
+			appConfig.HTTP.TLSRequireClientCert = tc.require
+
+			tlsConfig, err := serverTLSConfig(appConfig)
+			assert.Nil(t, err)
+
+			var verified bool
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				verified = len(r.TLS.VerifiedChains) > 0
+			}))
+			server.TLS = tlsConfig
+			server.StartTLS()
+			defer server.Close()
+
+			httpClient := server.Client()
+			transport := httpClient.Transport.(*http.Transport)
+			if tc.cert != nil {
+				// sent whatever CAs the server asks for
+				transport.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					cert := tc.cert.tlsCertificate()
+					return &cert, nil
+				}
+			}
+
+			resp, err := httpClient.Get(server.URL)
+			if tc.ok {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, tc.verified, verified)
+				_ = resp.Body.Close()
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestServerTLSConfig_WithoutClientCA(t *testing.T) {
+	tlsConfig, err := serverTLSConfig(&config.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, tlsConfig.ClientCAs)
+	assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+}
+
+func TestServerTLSConfig_Invalid(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	testCases := map[string]func(*config.Config){
+		"required without CA": func(c *config.Config) {
+			c.HTTP.TLSRequireClientCert = true
+		},
+		"missing CA file": func(c *config.Config) {
+			c.HTTP.TLSClientCAFile = filepath.Join(t.TempDir(), "missing.pem")
+		},
+		"CA file without certificates": func(c *config.Config) {
+			c.HTTP.TLSClientCAFile = notPEM
+		},
+	}
+
+	for name, configure := range testCases {
+		t.Run(name, func(t *testing.T) {
+			appConfig := &config.Config{}
+			configure(appConfig)
+
+			_, err := serverTLSConfig(appConfig)
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package audit keeps a tamper-evident record of who did what, and with what
+// outcome. Each entry holds the hash of the entry before it, so that changing
+// or removing an entry breaks the chain from that point on.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Actions that are recorded.
+const (
+	ActionLogin          = "login"
+	ActionLogout         = "logout"
+	ActionRevokeTokens   = "revoke-tokens"
+	ActionSubmitSet      = "submit-set"
+	ActionCreateCaseStub = "create-case-stub"
+	ActionAttachDocument = "attach-document"
+	// ActionReplayResponse is a request retried with an Idempotency-Key,
+	// which is given the first request's response without being processed.
+	ActionReplayResponse = "replay-response"
+)
+
+// Outcomes of an action.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// maxAppendAttempts is how many times an entry is retried when other
+// instances append at the same time.
+const maxAppendAttempts = 5
+
+// maxQueuedEvents is how many events can wait to be appended. Record blocks
+// while the queue is full, so that events are delayed rather than dropped.
+const maxQueuedEvents = 1000
+
+// Delays between attempts to append an entry when the store fails, doubling
+// from the first to the second.
+const (
+	minRetryDelay = 100 * time.Millisecond
+	maxRetryDelay = 30 * time.Second
+)
+
+// Event is what happened, as given by the caller.
+type Event struct {
+	Action string `json:"action"`
+	// Principal is who the action was done by, or for a failed login who it
+	// was attempted as.
+	Principal  string `json:"principal,omitempty"`
+	SetID      string `json:"setId,omitempty"`
+	DocumentID string `json:"documentId,omitempty"`
+	CaseUID    string `json:"caseUid,omitempty"`
+	// Hashes are the hex encoded SHA-256 hashes of the content the action
+	// was done with, by what it is, such as "set" or "pdf".
+	Hashes  map[string]string `json:"hashes,omitempty"`
+	Outcome string            `json:"outcome"`
+	Detail  string            `json:"detail,omitempty"`
+}
+
+// Entry is an Event as it is stored, chained to the entry before it.
+type Entry struct {
+	Sequence int64     `json:"sequence"`
+	Time     time.Time `json:"time"`
+	Event
+	PreviousHash string `json:"previousHash"`
+	Hash         string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, which covers every field but
+// Hash itself.
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ContentHash returns the hex encoded SHA-256 hash of some content.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Log appends events to a Store. Events are queued and appended in order by a
+// single goroutine, so recording one doesn't wait on the store. A nil Log
+// records nothing.
+type Log struct {
+	store      Store
+	logger     *slog.Logger
+	now        func() time.Time
+	retryDelay time.Duration
+
+	mu        sync.RWMutex
+	closed    bool
+	queue     chan queuedEvent
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	// Only used by the goroutine appending entries.
+	last   *Entry
+	loaded bool
+}
+
+type queuedEvent struct {
+	time  time.Time
+	event Event
+}
+
+func NewLog(store Store, logger *slog.Logger) *Log {
+	l := &Log{
+		store:      store,
+		logger:     logger,
+		now:        time.Now,
+		retryDelay: minRetryDelay,
+		queue:      make(chan queuedEvent, maxQueuedEvents),
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go l.run()
+
+	return l
+}
+
+// Record queues an event to be appended. Failures to append it are retried
+// until the Log is closed, and are logged rather than returned, so that they
+// don't fail the action being recorded.
+func (l *Log) Record(ctx context.Context, event Event) {
+	if l == nil {
+		return
+	}
+
+	queued := queuedEvent{time: l.now().UTC(), event: event}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		l.lost(ctx, queued, errors.New("audit log is closed"))
+		return
+	}
+
+	select {
+	case l.queue <- queued:
+	case <-l.closing:
+		l.lost(ctx, queued, errors.New("audit log is closed"))
+	}
+}
+
+// Close stops accepting events and waits for those queued to be appended. If
+// ctx ends first, each entry that still can't be appended is logged in full,
+// so that it can be added by hand, and ctx's error is returned.
+func (l *Log) Close(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	go func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !l.closed {
+			l.closed = true
+			close(l.queue)
+		}
+	}()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		l.closeOnce.Do(func() { close(l.closing) })
+		<-l.done
+		return ctx.Err()
+	}
+}
+
+func (l *Log) run() {
+	defer close(l.done)
+
+	ctx := context.Background()
+	for queued := range l.queue {
+		l.write(ctx, queued)
+	}
+}
+
+// write appends a queued event, retrying with backoff until it succeeds or
+// the Log is closing.
+func (l *Log) write(ctx context.Context, queued queuedEvent) {
+	delay := l.retryDelay
+
+	for {
+		err := l.append(ctx, queued)
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-l.closing:
+			l.lost(ctx, queued, err)
+			return
+		default:
+		}
+
+		l.logger.WarnContext(ctx, "Failed to record audit entry, retrying",
+			slog.String("action", queued.event.Action),
+			slog.String("outcome", queued.event.Outcome),
+			slog.Duration("retryIn", delay),
+			slog.Any("error", err))
+
+		select {
+		case <-time.After(delay):
+		case <-l.closing:
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// lost logs an event that won't be appended.
+func (l *Log) lost(ctx context.Context, queued queuedEvent, err error) {
+	l.logger.ErrorContext(ctx, "Failed to record audit entry",
+		slog.Time("occurredAt", queued.time),
+		slog.Any("event", queued.event),
+		slog.Any("error", err))
+}
+
+func (l *Log) append(ctx context.Context, queued queuedEvent) error {
+	for range maxAppendAttempts {
+		if !l.loaded {
+			last, err := l.store.Last(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to read last entry: %w", err)
+			}
+
+			l.last, l.loaded = last, true
+		}
+
+		entry := Entry{
+			Sequence: 1,
+			Time:     queued.time,
+			Event:    queued.event,
+		}
+		if l.last != nil {
+			entry.Sequence = l.last.Sequence + 1
+			entry.PreviousHash = l.last.Hash
+		}
+
+		hash, err := entry.computeHash()
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+
+		if err := l.store.Append(ctx, entry); err != nil {
+			if errors.Is(err, ErrConflict) {
+				// Another instance appended first, so start from its entry.
+				l.loaded = false
+				continue
+			}
+
+			return err
+		}
+
+		l.last = &entry
+		return nil
+	}
+
+	return errors.New("too many concurrent appends")
+}
+
+// VerifyError describes where the chain is broken.
+type VerifyError struct {
+	Sequence int64
+	Reason   string
+}
+
+func (e VerifyError) Error() string {
+	return fmt.Sprintf("entry %d: %s", e.Sequence, e.Reason)
+}
+
+// Verify reads every entry in a store and checks that the chain is intact,
+// returning the last entry. Entries removed from the end can't be detected
+// this way, so the last entry's hash should be kept somewhere else to compare
+// against later.
+func Verify(ctx context.Context, store Store) (*Entry, error) {
+	var previous *Entry
+
+	err := store.Entries(ctx, func(entry Entry) error {
+		expectedSequence, expectedPrevious := int64(1), ""
+		if previous != nil {
+			expectedSequence, expectedPrevious = previous.Sequence+1, previous.Hash
+		}
+
+		if entry.Sequence != expectedSequence {
+			return VerifyError{Sequence: expectedSequence, Reason: fmt.Sprintf("missing, found entry %d instead", entry.Sequence)}
+		}
+
+		if entry.PreviousHash != expectedPrevious {
+			return VerifyError{Sequence: entry.Sequence, Reason: "previous hash does not match the entry before it"}
+		}
+
+		hash, err := entry.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return VerifyError{Sequence: entry.Sequence, Reason: "hash does not match its content"}
+		}
+
+		previous = &entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,234 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var expectedError = errors.New("err")
+
+func newTestLog(store Store) *Log {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	log := NewLog(store, slog.New(slog.DiscardHandler))
+	log.retryDelay = time.Millisecond
+	log.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	return log
+}
+
+func TestLogRecord(t *testing.T) {
+	store := &fileStore{path: filepath.Join(t.TempDir(), "audit.jsonl")}
+	log := newTestLog(store)
+
+	log.Record(context.Background(), Event{Action: ActionLogin, Principal: "cop@example.com", Outcome: OutcomeSuccess})
+	log.Record(context.Background(), Event{
+		Action:    ActionSubmitSet,
+		Principal: "cop@example.com",
+		SetID:     "SET_1.xml",
+		CaseUID:   "7000-0000-0000",
+		Hashes:    map[string]string{"set": ContentHash([]byte("<Set/>"))},
+		Outcome:   OutcomeSuccess,
+	})
+	assert.NoError(t, log.Close(context.Background()))
+
+	var entries []Entry
+	assert.NoError(t, store.Entries(context.Background(), func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, int64(1), entries[0].Sequence)
+		assert.Equal(t, "", entries[0].PreviousHash)
+		assert.Equal(t, ActionLogin, entries[0].Action)
+		assert.Equal(t, time.Date(2026, time.March, 4, 12, 0, 1, 0, time.UTC), entries[0].Time)
+
+		assert.Equal(t, int64(2), entries[1].Sequence)
+		assert.Equal(t, entries[0].Hash, entries[1].PreviousHash)
+		assert.Equal(t, "SET_1.xml", entries[1].SetID)
+		assert.Equal(t, "7000-0000-0000", entries[1].CaseUID)
+	}
+
+	last, err := Verify(context.Background(), store)
+	assert.NoError(t, err)
+	assert.Equal(t, &entries[1], last)
+}
+
+func TestLogRecord_ContinuesChain(t *testing.T) {
+	store := &fileStore{path: filepath.Join(t.TempDir(), "audit.jsonl")}
+
+	for _, action := range []string{ActionLogin, ActionLogout} {
+		log := newTestLog(store)
+		log.Record(context.Background(), Event{Action: action, Outcome: OutcomeSuccess})
+		assert.NoError(t, log.Close(context.Background()))
+	}
+
+	last, err := Verify(context.Background(), store)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), last.Sequence)
+}
+
+func TestLogRecord_RetriesOnConflict(t *testing.T) {
+	first := Entry{Sequence: 1, Hash: "first"}
+	second := Entry{Sequence: 2, Hash: "second"}
+
+	store := newMockStore(t)
+	store.EXPECT().
+		Last(mock.Anything).
+		Return(&first, nil).
+		Once()
+	store.EXPECT().
+		Append(mock.Anything, mock.MatchedBy(func(entry Entry) bool { return entry.Sequence == 2 })).
+		Return(ErrConflict).
+		Once()
+	store.EXPECT().
+		Last(mock.Anything).
+		Return(&second, nil).
+		Once()
+	store.EXPECT().
+		Append(mock.Anything, mock.MatchedBy(func(entry Entry) bool {
+			return entry.Sequence == 3 && entry.PreviousHash == "second"
+		})).
+		Return(nil).
+		Once()
+
+	log := newTestLog(store)
+	log.Record(context.Background(), Event{Action: ActionLogin, Outcome: OutcomeSuccess})
+	assert.NoError(t, log.Close(context.Background()))
+}
+
+func TestLogRecord_RetriesWhenStoreFails(t *testing.T) {
+	store := newMockStore(t)
+	store.EXPECT().
+		Last(mock.Anything).
+		Return(nil, nil).
+		Once()
+	store.EXPECT().
+		Append(mock.Anything, mock.Anything).
+		Return(expectedError).
+		Twice()
+	store.EXPECT().
+		Append(mock.Anything, mock.MatchedBy(func(entry Entry) bool { return entry.Sequence == 1 })).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		Append(mock.Anything, mock.MatchedBy(func(entry Entry) bool { return entry.Sequence == 2 })).
+		Return(nil).
+		Once()
+
+	log := newTestLog(store)
+	log.Record(context.Background(), Event{Action: ActionLogin})
+	log.Record(context.Background(), Event{Action: ActionLogout})
+	assert.NoError(t, log.Close(context.Background()))
+}
+
+func TestLogClose_StoreFails(t *testing.T) {
+	store := newMockStore(t)
+	store.EXPECT().
+		Last(mock.Anything).
+		Return(nil, nil)
+	store.EXPECT().
+		Append(mock.Anything, mock.Anything).
+		Return(expectedError)
+
+	var buf bytes.Buffer
+	log := newTestLog(store)
+	log.logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	log.Record(context.Background(), Event{Action: ActionLogin, Principal: "cop@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, log.Close(ctx), context.DeadlineExceeded)
+	assert.Contains(t, buf.String(), `"msg":"Failed to record audit entry","occurredAt":"2026-03-04T12:00:01Z","event":{"action":"login","principal":"cop@example.com"`)
+
+	assert.NotPanics(t, func() {
+		log.Record(context.Background(), Event{Action: ActionLogout})
+		_ = log.Close(context.Background())
+	})
+}
+
+func TestLogRecord_Nil(t *testing.T) {
+	var log *Log
+
+	assert.NotPanics(t, func() {
+		log.Record(context.Background(), Event{Action: ActionLogin})
+	})
+}
+
+func TestVerify(t *testing.T) {
+	store := &fileStore{path: filepath.Join(t.TempDir(), "audit.jsonl")}
+	log := newTestLog(store)
+
+	for range 3 {
+		log.Record(context.Background(), Event{Action: ActionLogin, Principal: "cop@example.com", Outcome: OutcomeSuccess})
+	}
+	assert.NoError(t, log.Close(context.Background()))
+
+	var entries []Entry
+	_ = store.Entries(context.Background(), func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+
+	testCases := map[string]struct {
+		tamper func([]Entry) []Entry
+		error  string
+	}{
+		"changed": {
+			tamper: func(entries []Entry) []Entry {
+				entries[1].Principal = "someone@example.com"
+				return entries
+			},
+			error: "entry 2: hash does not match its content",
+		},
+		"changed and rehashed": {
+			tamper: func(entries []Entry) []Entry {
+				entries[1].Outcome = OutcomeFailure
+				entries[1].Hash, _ = entries[1].computeHash()
+				return entries
+			},
+			error: "entry 3: previous hash does not match the entry before it",
+		},
+		"removed": {
+			tamper: func(entries []Entry) []Entry {
+				return append(entries[:1], entries[2])
+			},
+			error: "entry 2: missing, found entry 3 instead",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tampered := &fileStore{path: filepath.Join(t.TempDir(), "audit.jsonl")}
+			for _, entry := range tc.tamper(append([]Entry{}, entries...)) {
+				assert.NoError(t, tampered.Append(context.Background(), entry))
+			}
+
+			_, err := Verify(context.Background(), tampered)
+			assert.EqualError(t, err, tc.error)
+
+			var verifyError VerifyError
+			assert.ErrorAs(t, err, &verifyError)
+		})
+	}
+}
+
+func TestVerify_Empty(t *testing.T) {
+	last, err := Verify(context.Background(), &fileStore{path: filepath.Join(t.TempDir(), "audit.jsonl")})
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+}
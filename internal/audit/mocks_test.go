@@ -0,0 +1,705 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package audit
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// newMockStore creates a new instance of mockStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockStore {
+	mock := &mockStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockStore is an autogenerated mock type for the Store type
+type mockStore struct {
+	mock.Mock
+}
+
+type mockStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockStore) EXPECT() *mockStore_Expecter {
+	return &mockStore_Expecter{mock: &_m.Mock}
+}
+
+// Append provides a mock function for the type mockStore
+func (_mock *mockStore) Append(ctx context.Context, entry Entry) error {
+	ret := _mock.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Entry) error); ok {
+		r0 = returnFunc(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStore_Append_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Append'
+type mockStore_Append_Call struct {
+	*mock.Call
+}
+
+// Append is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry Entry
+func (_e *mockStore_Expecter) Append(ctx interface{}, entry interface{}) *mockStore_Append_Call {
+	return &mockStore_Append_Call{Call: _e.mock.On("Append", ctx, entry)}
+}
+
+func (_c *mockStore_Append_Call) Run(run func(ctx context.Context, entry Entry)) *mockStore_Append_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 Entry
+		if args[1] != nil {
+			arg1 = args[1].(Entry)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStore_Append_Call) Return(err error) *mockStore_Append_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStore_Append_Call) RunAndReturn(run func(ctx context.Context, entry Entry) error) *mockStore_Append_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Entries provides a mock function for the type mockStore
+func (_mock *mockStore) Entries(ctx context.Context, fn func(Entry) error) error {
+	ret := _mock.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Entries")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(Entry) error) error); ok {
+		r0 = returnFunc(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStore_Entries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Entries'
+type mockStore_Entries_Call struct {
+	*mock.Call
+}
+
+// Entries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(Entry) error
+func (_e *mockStore_Expecter) Entries(ctx interface{}, fn interface{}) *mockStore_Entries_Call {
+	return &mockStore_Entries_Call{Call: _e.mock.On("Entries", ctx, fn)}
+}
+
+func (_c *mockStore_Entries_Call) Run(run func(ctx context.Context, fn func(Entry) error)) *mockStore_Entries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 func(Entry) error
+		if args[1] != nil {
+			arg1 = args[1].(func(Entry) error)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStore_Entries_Call) Return(err error) *mockStore_Entries_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStore_Entries_Call) RunAndReturn(run func(ctx context.Context, fn func(Entry) error) error) *mockStore_Entries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Last provides a mock function for the type mockStore
+func (_mock *mockStore) Last(ctx context.Context) (*Entry, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Last")
+	}
+
+	var r0 *Entry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*Entry, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *Entry); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Entry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockStore_Last_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Last'
+type mockStore_Last_Call struct {
+	*mock.Call
+}
+
+// Last is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockStore_Expecter) Last(ctx interface{}) *mockStore_Last_Call {
+	return &mockStore_Last_Call{Call: _e.mock.On("Last", ctx)}
+}
+
+func (_c *mockStore_Last_Call) Run(run func(ctx context.Context)) *mockStore_Last_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStore_Last_Call) Return(entry *Entry, err error) *mockStore_Last_Call {
+	_c.Call.Return(entry, err)
+	return _c
+}
+
+func (_c *mockStore_Last_Call) RunAndReturn(run func(ctx context.Context) (*Entry, error)) *mockStore_Last_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDynamoClient creates a new instance of mockDynamoClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDynamoClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDynamoClient {
+	mock := &mockDynamoClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockDynamoClient is an autogenerated mock type for the dynamoClient type
+type mockDynamoClient struct {
+	mock.Mock
+}
+
+type mockDynamoClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDynamoClient) EXPECT() *mockDynamoClient_Expecter {
+	return &mockDynamoClient_Expecter{mock: &_m.Mock}
+}
+
+// PutItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutItem")
+	}
+
+	var r0 *dynamodb.PutItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) *dynamodb.PutItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.PutItemOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockDynamoClient_PutItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutItem'
+type mockDynamoClient_PutItem_Call struct {
+	*mock.Call
+}
+
+// PutItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.PutItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) PutItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_PutItem_Call {
+	return &mockDynamoClient_PutItem_Call{Call: _e.mock.On("PutItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockDynamoClient_PutItem_Call) Run(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_PutItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.PutItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.PutItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockDynamoClient_PutItem_Call) Return(putItemOutput *dynamodb.PutItemOutput, err error) *mockDynamoClient_PutItem_Call {
+	_c.Call.Return(putItemOutput, err)
+	return _c
+}
+
+func (_c *mockDynamoClient_PutItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)) *mockDynamoClient_PutItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 *dynamodb.QueryOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) *dynamodb.QueryOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.QueryOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockDynamoClient_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type mockDynamoClient_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.QueryInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) Query(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_Query_Call {
+	return &mockDynamoClient_Query_Call{Call: _e.mock.On("Query",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockDynamoClient_Query_Call) Run(run func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.QueryInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.QueryInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockDynamoClient_Query_Call) Return(queryOutput *dynamodb.QueryOutput, err error) *mockDynamoClient_Query_Call {
+	_c.Call.Return(queryOutput, err)
+	return _c
+}
+
+func (_c *mockDynamoClient_Query_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)) *mockDynamoClient_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockS3Client creates a new instance of mockS3Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockS3Client(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockS3Client {
+	mock := &mockS3Client{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockS3Client is an autogenerated mock type for the s3Client type
+type mockS3Client struct {
+	mock.Mock
+}
+
+type mockS3Client_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockS3Client) EXPECT() *mockS3Client_Expecter {
+	return &mockS3Client_Expecter{mock: &_m.Mock}
+}
+
+// GetObject provides a mock function for the type mockS3Client
+func (_mock *mockS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	// func(*s3.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetObject")
+	}
+
+	var r0 *s3.GetObjectOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.GetObjectInput, ...func(*s3.Options)) (*s3.GetObjectOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.GetObjectInput, ...func(*s3.Options)) *s3.GetObjectOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.GetObjectOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *s3.GetObjectInput, ...func(*s3.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockS3Client_GetObject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetObject'
+type mockS3Client_GetObject_Call struct {
+	*mock.Call
+}
+
+// GetObject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *s3.GetObjectInput
+//   - optFns ...func(*s3.Options)
+func (_e *mockS3Client_Expecter) GetObject(ctx interface{}, params interface{}, optFns ...interface{}) *mockS3Client_GetObject_Call {
+	return &mockS3Client_GetObject_Call{Call: _e.mock.On("GetObject",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockS3Client_GetObject_Call) Run(run func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options))) *mockS3Client_GetObject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *s3.GetObjectInput
+		if args[1] != nil {
+			arg1 = args[1].(*s3.GetObjectInput)
+		}
+		var arg2 []func(*s3.Options)
+		variadicArgs := make([]func(*s3.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*s3.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockS3Client_GetObject_Call) Return(getObjectOutput *s3.GetObjectOutput, err error) *mockS3Client_GetObject_Call {
+	_c.Call.Return(getObjectOutput, err)
+	return _c
+}
+
+func (_c *mockS3Client_GetObject_Call) RunAndReturn(run func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)) *mockS3Client_GetObject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListObjectsV2 provides a mock function for the type mockS3Client
+func (_mock *mockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	// func(*s3.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListObjectsV2")
+	}
+
+	var r0 *s3.ListObjectsV2Output
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.ListObjectsV2Input, ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.ListObjectsV2Input, ...func(*s3.Options)) *s3.ListObjectsV2Output); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.ListObjectsV2Output)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *s3.ListObjectsV2Input, ...func(*s3.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockS3Client_ListObjectsV2_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListObjectsV2'
+type mockS3Client_ListObjectsV2_Call struct {
+	*mock.Call
+}
+
+// ListObjectsV2 is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *s3.ListObjectsV2Input
+//   - optFns ...func(*s3.Options)
+func (_e *mockS3Client_Expecter) ListObjectsV2(ctx interface{}, params interface{}, optFns ...interface{}) *mockS3Client_ListObjectsV2_Call {
+	return &mockS3Client_ListObjectsV2_Call{Call: _e.mock.On("ListObjectsV2",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockS3Client_ListObjectsV2_Call) Run(run func(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options))) *mockS3Client_ListObjectsV2_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *s3.ListObjectsV2Input
+		if args[1] != nil {
+			arg1 = args[1].(*s3.ListObjectsV2Input)
+		}
+		var arg2 []func(*s3.Options)
+		variadicArgs := make([]func(*s3.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*s3.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockS3Client_ListObjectsV2_Call) Return(listObjectsV2Output *s3.ListObjectsV2Output, err error) *mockS3Client_ListObjectsV2_Call {
+	_c.Call.Return(listObjectsV2Output, err)
+	return _c
+}
+
+func (_c *mockS3Client_ListObjectsV2_Call) RunAndReturn(run func(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)) *mockS3Client_ListObjectsV2_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutObject provides a mock function for the type mockS3Client
+func (_mock *mockS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	// func(*s3.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutObject")
+	}
+
+	var r0 *s3.PutObjectOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.PutObjectInput, ...func(*s3.Options)) (*s3.PutObjectOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *s3.PutObjectInput, ...func(*s3.Options)) *s3.PutObjectOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.PutObjectOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *s3.PutObjectInput, ...func(*s3.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockS3Client_PutObject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutObject'
+type mockS3Client_PutObject_Call struct {
+	*mock.Call
+}
+
+// PutObject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *s3.PutObjectInput
+//   - optFns ...func(*s3.Options)
+func (_e *mockS3Client_Expecter) PutObject(ctx interface{}, params interface{}, optFns ...interface{}) *mockS3Client_PutObject_Call {
+	return &mockS3Client_PutObject_Call{Call: _e.mock.On("PutObject",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mockS3Client_PutObject_Call) Run(run func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options))) *mockS3Client_PutObject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *s3.PutObjectInput
+		if args[1] != nil {
+			arg1 = args[1].(*s3.PutObjectInput)
+		}
+		var arg2 []func(*s3.Options)
+		variadicArgs := make([]func(*s3.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*s3.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockS3Client_PutObject_Call) Return(putObjectOutput *s3.PutObjectOutput, err error) *mockS3Client_PutObject_Call {
+	_c.Call.Return(putObjectOutput, err)
+	return _c
+}
+
+func (_c *mockS3Client_PutObject_Call) RunAndReturn(run func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)) *mockS3Client_PutObject_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,382 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+)
+
+// ErrConflict is returned by Append when an entry with the same sequence has
+// already been stored.
+var ErrConflict = errors.New("audit entry already exists")
+
+// Store is where entries are kept. Entries are only ever added.
+type Store interface {
+	// Last returns the entry with the highest sequence, or nil if there are
+	// none.
+	Last(ctx context.Context) (*Entry, error)
+	// Append stores an entry, unless one with its sequence already exists.
+	Append(ctx context.Context, entry Entry) error
+	// Entries calls fn with each entry in order of sequence, stopping at the
+	// first error.
+	Entries(ctx context.Context, fn func(Entry) error) error
+}
+
+type dynamoClient interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+type s3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// NewStore returns the store chosen by AUDIT_STORE. The clients are only
+// needed for the stores that use them.
+func NewStore(appConfig *config.Config, dynamo dynamoClient, s3 s3Client) Store {
+	switch appConfig.Audit.Store {
+	case "dynamodb":
+		return &dynamoStore{dynamo: dynamo, tableName: appConfig.Aws.DocumentsTable}
+	case "s3":
+		return &s3Store{s3: s3, bucket: appConfig.Audit.Bucket, kmsKey: appConfig.Audit.BucketKmsKey}
+	default:
+		return &fileStore{path: appConfig.Audit.File}
+	}
+}
+
+// fileStore keeps entries as lines of JSON in a local file. It stands in for
+// the other stores locally, and only supports a single instance appending.
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (s *fileStore) Last(ctx context.Context) (*Entry, error) {
+	var last *Entry
+
+	err := s.Entries(ctx, func(entry Entry) error {
+		last = &entry
+		return nil
+	})
+
+	return last, err
+}
+
+func (s *fileStore) Append(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *fileStore) Entries(ctx context.Context, fn func(Entry) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // only read from
+
+	decoder := json.NewDecoder(f)
+	for {
+		var entry Entry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read audit entry: %w", err)
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// dynamoStore keeps entries in DynamoDB, in a single partition sorted by
+// sequence. Items are written only if their sequence is new, so instances
+// appending at the same time can't fork the chain.
+type dynamoStore struct {
+	dynamo    dynamoClient
+	tableName string
+}
+
+const dynamoPartition = "AUDIT"
+
+func (s *dynamoStore) Last(ctx context.Context) (*Entry, error) {
+	output, err := s.dynamo.Query(ctx, s.query(false, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.Items) == 0 {
+		return nil, nil
+	}
+
+	entry, err := dynamoEntry(output.Items[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (s *dynamoStore) Append(ctx context.Context, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]dynamotypes.AttributeValue{
+			"PK":    &dynamotypes.AttributeValueMemberS{Value: dynamoPartition},
+			"SK":    &dynamotypes.AttributeValueMemberS{Value: entryName(entry.Sequence)},
+			"Entry": &dynamotypes.AttributeValueMemberS{Value: string(data)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+
+	var conditionFailed *dynamotypes.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrConflict
+	}
+
+	return err
+}
+
+func (s *dynamoStore) Entries(ctx context.Context, fn func(Entry) error) error {
+	paginator := dynamodb.NewQueryPaginator(s.dynamo, s.query(true, 0))
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range output.Items {
+			entry, err := dynamoEntry(item)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *dynamoStore) query(forward bool, limit int32) *dynamodb.QueryInput {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :PK"),
+		ExpressionAttributeValues: map[string]dynamotypes.AttributeValue{
+			":PK": &dynamotypes.AttributeValueMemberS{Value: dynamoPartition},
+		},
+		ScanIndexForward: aws.Bool(forward),
+		ConsistentRead:   aws.Bool(true),
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(limit)
+	}
+
+	return input
+}
+
+func dynamoEntry(item map[string]dynamotypes.AttributeValue) (Entry, error) {
+	attr, ok := item["Entry"].(*dynamotypes.AttributeValueMemberS)
+	if !ok {
+		return Entry{}, errors.New("audit item has no entry")
+	}
+
+	var entry Entry
+	if err := json.Unmarshal([]byte(attr.Value), &entry); err != nil {
+		return Entry{}, fmt.Errorf("failed to read audit entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// s3Store keeps each entry as an object named by its sequence. Objects are
+// written only if they don't exist, so instances appending at the same time
+// can't fork the chain. The bucket should use Object Lock so that entries
+// can't be overwritten or deleted.
+type s3Store struct {
+	s3     s3Client
+	bucket string
+	kmsKey string
+
+	mu sync.Mutex
+	// lastKey is the last object known to exist, so that finding the last
+	// entry only lists the objects after it.
+	lastKey string
+}
+
+const s3Prefix = "audit/"
+
+func (s *s3Store) Last(ctx context.Context) (*Entry, error) {
+	s.mu.Lock()
+	startAfter := s.lastKey
+	s.mu.Unlock()
+
+	lastKey := ""
+	err := s.list(ctx, startAfter, func(key string) error {
+		lastKey = key
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if lastKey == "" {
+		lastKey = startAfter
+	}
+	if lastKey == "" {
+		return nil, nil
+	}
+
+	entry, err := s.get(ctx, lastKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setLastKey(lastKey)
+	return &entry, nil
+}
+
+func (s *s3Store) Append(ctx context.Context, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s%020d.json", s3Prefix, entry.Sequence)
+
+	_, err = s.s3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(data),
+		ContentType:          aws.String("application/json"),
+		ServerSideEncryption: s3types.ServerSideEncryptionAwsKms,
+		SSEKMSKeyId:          aws.String(s.kmsKey),
+		IfNoneMatch:          aws.String("*"),
+	})
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "PreconditionFailed" || apiErr.ErrorCode() == "ConditionalRequestConflict") {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	s.setLastKey(key)
+	return nil
+}
+
+func (s *s3Store) Entries(ctx context.Context, fn func(Entry) error) error {
+	return s.list(ctx, "", func(key string) error {
+		entry, err := s.get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		return fn(entry)
+	})
+}
+
+func (s *s3Store) list(ctx context.Context, startAfter string, fn func(string) error) error {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s3Prefix),
+	}
+	if startAfter != "" {
+		input.StartAfter = aws.String(startAfter)
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s.s3, input)
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range output.Contents {
+			if err := fn(aws.ToString(object.Key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *s3Store) get(ctx context.Context, key string) (Entry, error) {
+	output, err := s.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	defer output.Body.Close() //nolint:errcheck // only read from
+
+	var entry Entry
+	if err := json.NewDecoder(output.Body).Decode(&entry); err != nil {
+		return Entry{}, fmt.Errorf("failed to read audit entry %s: %w", key, err)
+	}
+
+	return entry, nil
+}
+
+func (s *s3Store) setLastKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key > s.lastKey {
+		s.lastKey = key
+	}
+}
+
+// entryName is the sort key of an entry, padded so that entries sort in order.
+func entryName(sequence int64) string {
+	return fmt.Sprintf("ENTRY#%020d", sequence)
+}
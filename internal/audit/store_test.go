@@ -0,0 +1,234 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewStore(t *testing.T) {
+	testCases := map[string]Store{
+		"file":     &fileStore{path: "audit.jsonl"},
+		"dynamodb": &dynamoStore{tableName: "documents"},
+		"s3":       &s3Store{bucket: "audit-bucket", kmsKey: "alias/aws/s3"},
+	}
+
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			appConfig := &config.Config{}
+			appConfig.Aws.DocumentsTable = "documents"
+			appConfig.Audit.Store = name
+			appConfig.Audit.File = "audit.jsonl"
+			appConfig.Audit.Bucket = "audit-bucket"
+			appConfig.Audit.BucketKmsKey = "alias/aws/s3"
+
+			assert.Equal(t, expected, NewStore(appConfig, nil, nil))
+		})
+	}
+}
+
+func dynamoItem(t *testing.T, entry Entry) map[string]dynamotypes.AttributeValue {
+	data, err := json.Marshal(entry)
+	assert.NoError(t, err)
+
+	return map[string]dynamotypes.AttributeValue{
+		"PK":    &dynamotypes.AttributeValueMemberS{Value: "AUDIT"},
+		"SK":    &dynamotypes.AttributeValueMemberS{Value: entryName(entry.Sequence)},
+		"Entry": &dynamotypes.AttributeValueMemberS{Value: string(data)},
+	}
+}
+
+func TestDynamoStoreLast(t *testing.T) {
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		Query(mock.Anything, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return !*input.ScanIndexForward && *input.Limit == 1 && *input.ConsistentRead
+		})).
+		Return(&dynamodb.QueryOutput{Items: []map[string]dynamotypes.AttributeValue{dynamoItem(t, Entry{Sequence: 7, Hash: "hash"})}}, nil).
+		Once()
+	dynamo.EXPECT().
+		Query(mock.Anything, mock.Anything).
+		Return(&dynamodb.QueryOutput{}, nil).
+		Once()
+
+	store := &dynamoStore{dynamo: dynamo, tableName: "documents"}
+
+	last, err := store.Last(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Entry{Sequence: 7, Hash: "hash"}, last)
+
+	last, err = store.Last(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+}
+
+func TestDynamoStoreAppend(t *testing.T) {
+	entry := Entry{Sequence: 12, Event: Event{Action: ActionLogin}, Hash: "hash"}
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, &dynamodb.PutItemInput{
+			TableName:           aws.String("documents"),
+			Item:                dynamoItem(t, entry),
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}).
+		Return(&dynamodb.PutItemOutput{}, nil).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, &dynamotypes.ConditionalCheckFailedException{}).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	store := &dynamoStore{dynamo: dynamo, tableName: "documents"}
+
+	assert.NoError(t, store.Append(context.Background(), entry))
+	assert.ErrorIs(t, store.Append(context.Background(), entry), ErrConflict)
+	assert.ErrorIs(t, store.Append(context.Background(), entry), expectedError)
+}
+
+func TestDynamoStoreEntries(t *testing.T) {
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		Query(mock.Anything, mock.MatchedBy(func(input *dynamodb.QueryInput) bool { return input.ExclusiveStartKey == nil }), mock.Anything).
+		Return(&dynamodb.QueryOutput{
+			Items:            []map[string]dynamotypes.AttributeValue{dynamoItem(t, Entry{Sequence: 1})},
+			LastEvaluatedKey: map[string]dynamotypes.AttributeValue{"PK": &dynamotypes.AttributeValueMemberS{Value: "AUDIT"}},
+		}, nil).
+		Once()
+	dynamo.EXPECT().
+		Query(mock.Anything, mock.MatchedBy(func(input *dynamodb.QueryInput) bool { return input.ExclusiveStartKey != nil }), mock.Anything).
+		Return(&dynamodb.QueryOutput{Items: []map[string]dynamotypes.AttributeValue{dynamoItem(t, Entry{Sequence: 2})}}, nil).
+		Once()
+
+	store := &dynamoStore{dynamo: dynamo, tableName: "documents"}
+
+	var sequences []int64
+	assert.NoError(t, store.Entries(context.Background(), func(entry Entry) error {
+		sequences = append(sequences, entry.Sequence)
+		return nil
+	}))
+	assert.Equal(t, []int64{1, 2}, sequences)
+}
+
+func s3Object(t *testing.T, entry Entry) *s3.GetObjectOutput {
+	data, err := json.Marshal(entry)
+	assert.NoError(t, err)
+
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(string(data)))}
+}
+
+func TestS3StoreAppend(t *testing.T) {
+	s3Client := newMockS3Client(t)
+	s3Client.EXPECT().
+		PutObject(mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+			return *input.Bucket == "audit-bucket" &&
+				*input.Key == "audit/00000000000000000003.json" &&
+				*input.IfNoneMatch == "*" &&
+				input.ServerSideEncryption == s3types.ServerSideEncryptionAwsKms &&
+				*input.SSEKMSKeyId == "alias/aws/s3"
+		})).
+		Return(&s3.PutObjectOutput{}, nil).
+		Once()
+	s3Client.EXPECT().
+		PutObject(mock.Anything, mock.Anything).
+		Return(nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}).
+		Once()
+
+	store := &s3Store{s3: s3Client, bucket: "audit-bucket", kmsKey: "alias/aws/s3"}
+
+	assert.NoError(t, store.Append(context.Background(), Entry{Sequence: 3}))
+	assert.Equal(t, "audit/00000000000000000003.json", store.lastKey)
+
+	assert.ErrorIs(t, store.Append(context.Background(), Entry{Sequence: 3}), ErrConflict)
+}
+
+func TestS3StoreLast(t *testing.T) {
+	s3Client := newMockS3Client(t)
+	s3Client.EXPECT().
+		ListObjectsV2(mock.Anything, mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+			return *input.Prefix == "audit/" && input.StartAfter == nil
+		}), mock.Anything).
+		Return(&s3.ListObjectsV2Output{Contents: []s3types.Object{
+			{Key: aws.String("audit/00000000000000000001.json")},
+			{Key: aws.String("audit/00000000000000000002.json")},
+		}}, nil).
+		Once()
+	s3Client.EXPECT().
+		GetObject(mock.Anything, &s3.GetObjectInput{Bucket: aws.String("audit-bucket"), Key: aws.String("audit/00000000000000000002.json")}).
+		Return(s3Object(t, Entry{Sequence: 2}), nil).
+		Once()
+	// only lists what has been added since
+	s3Client.EXPECT().
+		ListObjectsV2(mock.Anything, mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+			return *input.StartAfter == "audit/00000000000000000002.json"
+		}), mock.Anything).
+		Return(&s3.ListObjectsV2Output{}, nil).
+		Once()
+	s3Client.EXPECT().
+		GetObject(mock.Anything, &s3.GetObjectInput{Bucket: aws.String("audit-bucket"), Key: aws.String("audit/00000000000000000002.json")}).
+		Return(s3Object(t, Entry{Sequence: 2}), nil).
+		Once()
+
+	store := &s3Store{s3: s3Client, bucket: "audit-bucket"}
+
+	last, err := store.Last(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), last.Sequence)
+
+	last, err = store.Last(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), last.Sequence)
+}
+
+func TestS3StoreLast_Empty(t *testing.T) {
+	s3Client := newMockS3Client(t)
+	s3Client.EXPECT().
+		ListObjectsV2(mock.Anything, mock.Anything, mock.Anything).
+		Return(&s3.ListObjectsV2Output{}, nil)
+
+	last, err := (&s3Store{s3: s3Client, bucket: "audit-bucket"}).Last(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+}
+
+func TestS3StoreEntries(t *testing.T) {
+	s3Client := newMockS3Client(t)
+	s3Client.EXPECT().
+		ListObjectsV2(mock.Anything, mock.Anything, mock.Anything).
+		Return(&s3.ListObjectsV2Output{Contents: []s3types.Object{
+			{Key: aws.String("audit/00000000000000000001.json")},
+			{Key: aws.String("audit/00000000000000000002.json")},
+		}}, nil)
+	s3Client.EXPECT().
+		GetObject(mock.Anything, &s3.GetObjectInput{Bucket: aws.String("audit-bucket"), Key: aws.String("audit/00000000000000000001.json")}).
+		Return(s3Object(t, Entry{Sequence: 1}), nil)
+	s3Client.EXPECT().
+		GetObject(mock.Anything, &s3.GetObjectInput{Bucket: aws.String("audit-bucket"), Key: aws.String("audit/00000000000000000002.json")}).
+		Return(nil, expectedError)
+
+	store := &s3Store{s3: s3Client, bucket: "audit-bucket"}
+
+	var sequences []int64
+	err := store.Entries(context.Background(), func(entry Entry) error {
+		sequences = append(sequences, entry.Sequence)
+		return nil
+	})
+	assert.ErrorIs(t, err, expectedError)
+	assert.Equal(t, []int64{1}, sequences)
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+)
+
+// attemptWindow is how long failed logins are remembered for after the last
+// failure, or after a lockout ends.
+const attemptWindow = 15 * time.Minute
+
+// maxLockout caps how long repeated failures can lock a caller out for.
+const maxLockout = time.Hour
+
+// maxTrackedAttempts is how many callers are tracked in memory. When that many
+// are still being remembered, logins by other callers are refused, as their
+// failures couldn't be counted.
+const maxTrackedAttempts = 10000
+
+// LockedOutError is returned when a login is refused, without checking the
+// credentials, because of too many failed attempts.
+type LockedOutError struct {
+	RetryAfter time.Duration
+}
+
+func (e LockedOutError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+// loginAttempts is what is remembered about the failed logins for an IP
+// address or an account.
+type loginAttempts struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+func (a loginAttempts) expiresAt() time.Time {
+	if a.LockedUntil.After(a.LastFailure) {
+		return a.LockedUntil.Add(attemptWindow)
+	}
+
+	return a.LastFailure.Add(attemptWindow)
+}
+
+type attemptStore interface {
+	Get(ctx context.Context, key string) (loginAttempts, error)
+	Put(ctx context.Context, key string, attempts loginAttempts) error
+	Delete(ctx context.Context, key string) error
+}
+
+// loginLimiter counts failed logins by IP address and by account. Once either
+// reaches its limit further logins are refused for a lockout that doubles with
+// each failure after that, up to maxLockout. Lockouts apply to accounts that
+// do not exist in the same way, so they don't show which accounts do.
+type loginLimiter struct {
+	store              attemptStore
+	now                func() time.Time
+	maxIPFailures      int
+	maxAccountFailures int
+	lockout            time.Duration
+}
+
+// Check returns a LockedOutError if the IP address or the account is locked
+// out.
+func (l *loginLimiter) Check(ctx context.Context, ip, email string) error {
+	var retryAfter time.Duration
+
+	for _, key := range l.keys(ip, email) {
+		attempts, err := l.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		retryAfter = max(retryAfter, attempts.LockedUntil.Sub(l.now()))
+	}
+
+	if retryAfter > 0 {
+		return LockedOutError{RetryAfter: retryAfter.Round(time.Second)}
+	}
+
+	return nil
+}
+
+// Fail records a failed login, and returns how long the caller is now locked
+// out for.
+func (l *loginLimiter) Fail(ctx context.Context, ip, email string) (time.Duration, error) {
+	var lockout time.Duration
+
+	keys := l.keys(ip, email)
+	limits := []int{l.maxIPFailures, l.maxAccountFailures}
+
+	for i, key := range keys {
+		attempts, err := l.store.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+
+		attempts.Failures++
+		attempts.LastFailure = l.now()
+
+		if over := attempts.Failures - limits[i]; over >= 0 {
+			duration := maxLockout
+			if over < 32 {
+				duration = min(l.lockout<<over, maxLockout)
+			}
+
+			attempts.LockedUntil = attempts.LastFailure.Add(duration)
+			lockout = max(lockout, duration)
+		}
+
+		// Attempts made at the same time from other instances may be lost
+		// here. That only lets a caller make a few more guesses.
+		if err := l.store.Put(ctx, key, attempts); err != nil {
+			return 0, err
+		}
+	}
+
+	return lockout, nil
+}
+
+// Succeed forgets the failed logins for the account. Failures from the IP
+// address are kept, so that guessing passwords for many accounts from one
+// address is limited even if one of them is known.
+func (l *loginLimiter) Succeed(ctx context.Context, email string) error {
+	return l.store.Delete(ctx, accountAttemptsKey(email))
+}
+
+func (l *loginLimiter) keys(ip, email string) []string {
+	return []string{"LOGIN#IP#" + ip, accountAttemptsKey(email)}
+}
+
+func accountAttemptsKey(email string) string {
+	return "LOGIN#ACCOUNT#" + email
+}
+
+type memoryAttemptStore struct {
+	now      func() time.Time
+	attempts *cache.Cache[string, loginAttempts]
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{
+		now:      time.Now,
+		attempts: cache.New[string, loginAttempts](maxTrackedAttempts),
+	}
+}
+
+func (s *memoryAttemptStore) Get(ctx context.Context, key string) (loginAttempts, error) {
+	now := s.now()
+
+	attempts, ok := s.attempts.Get(key, now)
+	if !ok && s.attempts.Full(now) {
+		return loginAttempts{}, fmt.Errorf("too many callers to track: %w", cache.ErrFull)
+	}
+
+	return attempts, nil
+}
+
+func (s *memoryAttemptStore) Put(ctx context.Context, key string, attempts loginAttempts) error {
+	return s.attempts.Put(key, attempts, attempts.expiresAt(), s.now())
+}
+
+func (s *memoryAttemptStore) Delete(ctx context.Context, key string) error {
+	s.attempts.Delete(key)
+	return nil
+}
+
+// dynamoAttemptStore keeps login attempts in DynamoDB, so that every instance
+// shares them. Items expire using the table's TTL.
+type dynamoAttemptStore struct {
+	dynamo    dynamoClient
+	tableName string
+	now       func() time.Time
+}
+
+func newDynamoAttemptStore(dynamo dynamoClient, tableName string) *dynamoAttemptStore {
+	return &dynamoAttemptStore{
+		dynamo:    dynamo,
+		tableName: tableName,
+		now:       time.Now,
+	}
+}
+
+func (s *dynamoAttemptStore) Get(ctx context.Context, key string) (loginAttempts, error) {
+	output, err := s.dynamo.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            itemKey(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return loginAttempts{}, err
+	}
+
+	if len(output.Item) == 0 {
+		return loginAttempts{}, nil
+	}
+
+	failures, err := numberAttribute(output.Item, "Failures")
+	if err != nil {
+		return loginAttempts{}, err
+	}
+	lastFailure, err := numberAttribute(output.Item, "LastFailure")
+	if err != nil {
+		return loginAttempts{}, err
+	}
+	lockedUntil, err := numberAttribute(output.Item, "LockedUntil")
+	if err != nil {
+		return loginAttempts{}, err
+	}
+
+	attempts := loginAttempts{
+		Failures:    int(failures),
+		LastFailure: time.Unix(lastFailure, 0),
+	}
+	if lockedUntil > 0 {
+		attempts.LockedUntil = time.Unix(lockedUntil, 0)
+	}
+
+	// The TTL can take a while to remove expired items.
+	if !s.now().Before(attempts.expiresAt()) {
+		return loginAttempts{}, nil
+	}
+
+	return attempts, nil
+}
+
+func (s *dynamoAttemptStore) Put(ctx context.Context, key string, attempts loginAttempts) error {
+	var lockedUntil int64
+	if !attempts.LockedUntil.IsZero() {
+		lockedUntil = attempts.LockedUntil.Unix()
+	}
+
+	item := itemKey(key)
+	item["Failures"] = &types.AttributeValueMemberN{Value: strconv.Itoa(attempts.Failures)}
+	item["LastFailure"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(attempts.LastFailure.Unix(), 10)}
+	item["LockedUntil"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(lockedUntil, 10)}
+	item["ExpiresAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(attempts.expiresAt().Unix(), 10)}
+
+	_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	return err
+}
+
+func (s *dynamoAttemptStore) Delete(ctx context.Context, key string) error {
+	_, err := s.dynamo.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       itemKey(key),
+	})
+	return err
+}
+
+func itemKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: key},
+		"SK": &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+func numberAttribute(item map[string]types.AttributeValue, name string) (int64, error) {
+	attr, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(attr.Value, 10, 64)
+}
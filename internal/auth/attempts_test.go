@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMemoryAttemptStoreFull(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	store := newMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+	store.attempts = cache.New[string, loginAttempts](2)
+
+	limiter := &loginLimiter{
+		store:              store,
+		now:                func() time.Time { return now },
+		maxIPFailures:      20,
+		maxAccountFailures: 1,
+		lockout:            time.Minute,
+	}
+	ctx := context.Background()
+
+	lockout, err := limiter.Fail(ctx, "192.0.2.1", "cop@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, lockout)
+
+	// new callers are refused rather than evict the lockout
+	assert.ErrorIs(t, limiter.Check(ctx, "198.51.100.1", "other@example.com"), cache.ErrFull)
+	_, err = limiter.Fail(ctx, "198.51.100.1", "other@example.com")
+	assert.ErrorIs(t, err, cache.ErrFull)
+
+	assert.Equal(t, LockedOutError{RetryAfter: time.Minute}, limiter.Check(ctx, "192.0.2.1", "cop@example.com"))
+
+	// and let in once the failures are forgotten
+	now = now.Add(time.Minute + attemptWindow)
+	assert.NoError(t, limiter.Check(ctx, "198.51.100.1", "other@example.com"))
+}
+
+func TestLoginLimiterLockoutDoubles(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	store := newMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+
+	limiter := &loginLimiter{
+		store:              store,
+		now:                func() time.Time { return now },
+		maxIPFailures:      20,
+		maxAccountFailures: 3,
+		lockout:            time.Minute,
+	}
+	ctx := context.Background()
+
+	for range 2 {
+		lockout, err := limiter.Fail(ctx, "192.0.2.1", "cop@example.com")
+		assert.NoError(t, err)
+		assert.Zero(t, lockout)
+	}
+
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		lockout, err := limiter.Fail(ctx, "192.0.2.1", "cop@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, lockout)
+
+		assert.Equal(t, LockedOutError{RetryAfter: expected}, limiter.Check(ctx, "192.0.2.1", "cop@example.com"))
+
+		// another account from another address is not affected
+		assert.NoError(t, limiter.Check(ctx, "198.51.100.1", "other@example.com"))
+
+		// the lockout follows the account to other addresses
+		assert.Equal(t, LockedOutError{RetryAfter: expected}, limiter.Check(ctx, "198.51.100.1", "cop@example.com"))
+
+		now = now.Add(expected)
+		assert.NoError(t, limiter.Check(ctx, "192.0.2.1", "cop@example.com"))
+	}
+
+	// failures are forgotten once the window has passed
+	now = now.Add(attemptWindow)
+	lockout, err := limiter.Fail(ctx, "192.0.2.1", "cop@example.com")
+	assert.NoError(t, err)
+	assert.Zero(t, lockout)
+}
+
+func TestLoginLimiterLockoutIsCapped(t *testing.T) {
+	limiter := &loginLimiter{
+		store:              newMemoryAttemptStore(),
+		now:                time.Now,
+		maxIPFailures:      1,
+		maxAccountFailures: 1,
+		lockout:            time.Minute,
+	}
+
+	var lockout time.Duration
+	for range 100 {
+		lockout, _ = limiter.Fail(context.Background(), "192.0.2.1", "cop@example.com")
+	}
+
+	assert.Equal(t, maxLockout, lockout)
+}
+
+func TestLoginLimiterLocksOutIP(t *testing.T) {
+	limiter := &loginLimiter{
+		store:              newMemoryAttemptStore(),
+		now:                time.Now,
+		maxIPFailures:      3,
+		maxAccountFailures: 5,
+		lockout:            time.Minute,
+	}
+	ctx := context.Background()
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		_, _ = limiter.Fail(ctx, "192.0.2.1", email)
+	}
+
+	assert.Error(t, limiter.Check(ctx, "192.0.2.1", "d@example.com"))
+	assert.NoError(t, limiter.Check(ctx, "198.51.100.1", "a@example.com"))
+
+	// logging in to one account does not clear the address
+	assert.NoError(t, limiter.Succeed(ctx, "a@example.com"))
+	assert.Error(t, limiter.Check(ctx, "192.0.2.1", "a@example.com"))
+}
+
+func TestDynamoAttemptStore(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	attempts := loginAttempts{
+		Failures:    5,
+		LastFailure: now,
+		LockedUntil: now.Add(time.Minute),
+	}
+	item := map[string]types.AttributeValue{
+		"PK":          &types.AttributeValueMemberS{Value: "LOGIN#ACCOUNT#cop@example.com"},
+		"SK":          &types.AttributeValueMemberS{Value: "LOGIN#ACCOUNT#cop@example.com"},
+		"Failures":    &types.AttributeValueMemberN{Value: "5"},
+		"LastFailure": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		"LockedUntil": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+		"ExpiresAt":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(time.Minute+attemptWindow).Unix(), 10)},
+	}
+	key := map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: "LOGIN#ACCOUNT#cop@example.com"},
+		"SK": &types.AttributeValueMemberS{Value: "LOGIN#ACCOUNT#cop@example.com"},
+	}
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, &dynamodb.PutItemInput{
+			TableName: aws.String("table"),
+			Item:      item,
+		}).
+		Return(&dynamodb.PutItemOutput{}, nil)
+	dynamo.EXPECT().
+		GetItem(mock.Anything, &dynamodb.GetItemInput{
+			TableName:      aws.String("table"),
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		}).
+		Return(&dynamodb.GetItemOutput{Item: item}, nil)
+	dynamo.EXPECT().
+		DeleteItem(mock.Anything, &dynamodb.DeleteItemInput{
+			TableName: aws.String("table"),
+			Key:       key,
+		}).
+		Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	store := newDynamoAttemptStore(dynamo, "table")
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "LOGIN#ACCOUNT#cop@example.com", attempts))
+
+	got, err := store.Get(ctx, "LOGIN#ACCOUNT#cop@example.com")
+	assert.NoError(t, err)
+	assert.True(t, attempts.LastFailure.Equal(got.LastFailure))
+	assert.True(t, attempts.LockedUntil.Equal(got.LockedUntil))
+	assert.Equal(t, 5, got.Failures)
+
+	assert.NoError(t, store.Delete(ctx, "LOGIN#ACCOUNT#cop@example.com"))
+}
+
+func TestDynamoAttemptStoreIgnoresExpiredItems(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		GetItem(mock.Anything, mock.Anything).
+		Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+			"Failures":    &types.AttributeValueMemberN{Value: "3"},
+			"LastFailure": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(-attemptWindow).Unix(), 10)},
+			"LockedUntil": &types.AttributeValueMemberN{Value: "0"},
+		}}, nil)
+
+	store := newDynamoAttemptStore(dynamo, "table")
+	store.now = func() time.Time { return now }
+
+	attempts, err := store.Get(context.Background(), "LOGIN#IP#192.0.2.1")
+	assert.NoError(t, err)
+	assert.Zero(t, attempts)
+}
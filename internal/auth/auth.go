@@ -3,45 +3,104 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/ministryofjustice/opg-scanning/internal/aws"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/logger"
+	"github.com/ministryofjustice/opg-scanning/internal/problem"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const cookieName = "membrane"
+const (
+	cookieName = "membrane"
+	realm      = "opg-scanning"
+)
 
 type tokens interface {
-	Generate() (string, time.Time, error)
-	Validate(string) error
+	Generate(Principal) (string, time.Time, error)
+	Validate(context.Context, string) (Principal, error)
+	Revoke(context.Context, string) (Principal, error)
+	RevokePrincipal(context.Context, string) error
+	KeySet(context.Context) (KeySet, error)
 }
 
-type credentialsClient interface {
-	FetchCredentials(ctx context.Context) (map[string]string, error)
+type awsClient interface {
+	secretsClient
+	ssmClient
 }
 
-func New(appConfig *config.Config, logger *slog.Logger, awsClient aws.AwsClientInterface) *Auth {
+// ErrInvalidCredentials is returned for every login that fails because of the
+// credentials given, so that it doesn't show which accounts exist.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// unknownAccountHash is compared against when there is no account for an
+// email, so that those logins take as long as any other.
+var unknownAccountHash, _ = bcrypt.GenerateFromPassword([]byte("unknown account"), bcrypt.DefaultCost)
+
+func New(appConfig *config.Config, logger *slog.Logger, awsClient awsClient, dynamoClient dynamoClient, auditLog *audit.Log) *Auth {
+	var attempts attemptStore = newMemoryAttemptStore()
+	if appConfig.Auth.LoginAttemptsStore == "dynamodb" {
+		attempts = newDynamoAttemptStore(dynamoClient, appConfig.Aws.DocumentsTable)
+	}
+
+	var signatures *requestVerifier
+	if appConfig.Auth.RequestSigningKeysARN != "" {
+		var nonces nonceStore = newMemoryNonceStore()
+		if appConfig.Auth.NonceStore == "dynamodb" {
+			nonces = &dynamoNonceStore{dynamo: dynamoClient, tableName: appConfig.Aws.DocumentsTable}
+		}
+
+		signatures = &requestVerifier{
+			secrets:   awsClient,
+			secretARN: appConfig.Auth.RequestSigningKeysARN,
+			skew:      appConfig.Auth.RequestSigningSkew,
+			nonces:    nonces,
+			now:       time.Now,
+		}
+	}
+
 	return &Auth{
 		tokens: &tokenHelper{
 			awsClient: awsClient,
 			config:    appConfig,
+			denylist:  NewDenylist(dynamoClient, appConfig.Aws.DocumentsTable),
+		},
+		logins: &loginLimiter{
+			store:              attempts,
+			now:                time.Now,
+			maxIPFailures:      appConfig.Auth.MaxLoginAttemptsPerIP,
+			maxAccountFailures: appConfig.Auth.MaxLoginAttemptsPerAccount,
+			lockout:            appConfig.Auth.LoginLockout,
 		},
-		logger:       logger,
-		credentials:  awsClient,
-		secureCookie: appConfig.App.Environment != "local",
+		signatures:         signatures,
+		audit:              auditLog,
+		logger:             logger,
+		credentials:        newCredentialStore(appConfig, logger, awsClient),
+		clientScopes:       appConfig.Auth.ClientScopes,
+		clientCertificates: appConfig.Auth.ClientCertificates,
+		secureCookie:       appConfig.App.Environment != "local",
 	}
 }
 
 type Auth struct {
-	tokens       tokens
-	credentials  credentialsClient
-	logger       *slog.Logger
-	secureCookie bool
+	tokens             tokens
+	logins             *loginLimiter
+	signatures         *requestVerifier
+	audit              *audit.Log
+	credentials        credentialStore
+	clientScopes       map[string][]string
+	clientCertificates map[string]string
+	logger             *slog.Logger
+	secureCookie       bool
 }
 
 func (a *Auth) Authenticate(w http.ResponseWriter, r *http.Request) (AuthenticatedUser, error) {
@@ -51,11 +110,25 @@ func (a *Auth) Authenticate(w http.ResponseWriter, r *http.Request) (Authenticat
 	}
 
 	// Validate credentials first
-	if err := a.validateCredentials(r.Context(), creds.User); err != nil {
+	if err := a.login(r.Context(), clientIP(r), creds.User); err != nil {
+		a.audit.Record(r.Context(), audit.Event{
+			Action:    audit.ActionLogin,
+			Principal: creds.User.Email,
+			Outcome:   audit.OutcomeFailure,
+			Detail:    err.Error(),
+		})
 		return AuthenticatedUser{}, err
 	}
 
-	token, expiry, err := a.tokens.Generate()
+	a.audit.Record(r.Context(), audit.Event{
+		Action:    audit.ActionLogin,
+		Principal: creds.User.Email,
+		Outcome:   audit.OutcomeSuccess,
+	})
+
+	principal := Principal{ID: creds.User.Email, Scopes: a.scopesFor(creds.User.Email)}
+
+	token, expiry, err := a.tokens.Generate(principal)
 	if err != nil {
 		return AuthenticatedUser{}, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -76,29 +149,262 @@ func (a *Auth) Authenticate(w http.ResponseWriter, r *http.Request) (Authenticat
 	}, nil
 }
 
-func (a *Auth) Check(next http.Handler) http.HandlerFunc {
+// Logout revokes the token the request was made with, and clears the membrane
+// cookie.
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) error {
+	token, err := tokenFromRequest(r)
+	if err != nil {
+		return fmt.Errorf("missing token: %w", err)
+	}
+
+	principal, err := a.tokens.Revoke(r.Context(), token)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	a.audit.Record(r.Context(), audit.Event{
+		Action:    audit.ActionLogout,
+		Principal: principal.ID,
+		Outcome:   audit.OutcomeSuccess,
+	})
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    "",
+		MaxAge:   -1,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   a.secureCookie,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return nil
+}
+
+// RevokePrincipal revokes every token issued so far to a principal.
+func (a *Auth) RevokePrincipal(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("missing principal")
+	}
+
+	event := audit.Event{
+		Action:  audit.ActionRevokeTokens,
+		Outcome: audit.OutcomeSuccess,
+		Detail:  "revoked tokens issued to " + id,
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		event.Principal = principal.ID
+	}
+
+	if err := a.tokens.RevokePrincipal(ctx, id); err != nil {
+		event.Outcome = audit.OutcomeFailure
+		a.audit.Record(ctx, event)
+		return err
+	}
+
+	a.audit.Record(ctx, event)
+	return nil
+}
+
+// KeySet returns the public keys other services can verify tokens with.
+func (a *Auth) KeySet(ctx context.Context) (KeySet, error) {
+	return a.tokens.KeySet(ctx)
+}
+
+// Check only passes on requests with a valid token, issued to a principal that
+// has been granted every one of the scopes. A token sent as a bearer token in
+// the Authorization header is used in preference to the membrane cookie, which
+// is ignored whenever a bearer token is sent. Requests without a token can
+// instead be made with a verified client certificate that is mapped to a
+// principal. Signed requests are refused; routes that take them use
+// CheckSigned.
+func (a *Auth) Check(next http.Handler, scopes ...string) http.HandlerFunc {
+	return a.check(next, false, scopes)
+}
+
+// CheckSigned is Check for routes that also accept signed requests, which are
+// authenticated by their signature alone.
+func (a *Auth) CheckSigned(next http.Handler, scopes ...string) http.HandlerFunc {
+	return a.check(next, true, scopes)
+}
+
+func (a *Auth) check(next http.Handler, acceptSigned bool, scopes []string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie(cookieName)
-		if err != nil {
-			a.respondWithError(r.Context(), w, http.StatusUnauthorized, "Unauthorized: Missing token", err)
+		var principal Principal
+
+		token, err := tokenFromRequest(r)
+		switch {
+		case isSignedRequest(r) && !acceptSigned:
+			a.respondWithError(w, r, problem.Unauthorized, "Unauthorized: Signed requests are not accepted", fmt.Errorf("%s does not accept signed requests", r.URL.Path))
 			return
-		}
 
-		token := cookie.Value
+		case isSignedRequest(r):
+			principal, err = a.signedRequestPrincipal(w, r)
+			if err != nil {
+				a.respondWithError(w, r, problem.Unauthorized, "Unauthorized: Invalid signature", err)
+				return
+			}
+
+		case err == nil:
+			principal, err = a.tokens.Validate(r.Context(), token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", bearerChallenge("invalid_token", ""))
+				a.respondWithError(w, r, problem.Unauthorized, "Unauthorized: Invalid token", err)
+				return
+			}
+
+		case hasClientCertificate(r):
+			principal, err = a.certificatePrincipal(r)
+			if err != nil {
+				a.respondWithError(w, r, problem.Unauthorized, "Unauthorized: Unknown client certificate", err)
+				return
+			}
 
-		if err := a.tokens.Validate(token); err != nil {
-			a.respondWithError(r.Context(), w, http.StatusUnauthorized, "Unauthorized: Invalid token", err)
+		default:
+			w.Header().Set("WWW-Authenticate", bearerChallenge("", ""))
+			a.respondWithError(w, r, problem.Unauthorized, "Unauthorized: Missing token", err)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), constants.TokenContextKey, token)
+		for _, scope := range scopes {
+			if !principal.Can(scope) {
+				w.Header().Set("WWW-Authenticate", bearerChallenge("insufficient_scope", scope))
+				a.respondWithError(w, r, problem.Forbidden, "Forbidden: Missing scope "+scope, fmt.Errorf("%s has not been granted %s", principal.ID, scope))
+				return
+			}
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			semconv.EnduserIDKey.String(principal.ID),
+			semconv.EnduserScopeKey.String(principal.scopeClaim()),
+		)
+
+		ctx := ContextWithPrincipal(r.Context(), principal)
+		ctx = logger.ContextWithAttrs(ctx, slog.String("principal", principal.ID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
 
+// signedRequestPrincipal returns the principal that signed a request.
+func (a *Auth) signedRequestPrincipal(w http.ResponseWriter, r *http.Request) (Principal, error) {
+	if a.signatures == nil {
+		return Principal{}, errors.New("signed requests are not enabled")
+	}
+
+	id, err := a.signatures.Verify(w, r)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{ID: id, Scopes: a.scopesFor(id)}, nil
+}
+
+// tokenFromRequest returns the bearer token from the Authorization header, or
+// if there isn't one the token from the membrane cookie. Authorization headers
+// using other schemes are ignored.
+func tokenFromRequest(r *http.Request) (string, error) {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		if token = strings.TrimSpace(token); token == "" {
+			return "", errors.New("empty bearer token")
+		}
+
+		return token, nil
+	}
+
+	cookie, err := r.Cookie(cookieName)
+	if err != nil {
+		return "", err
+	}
+
+	return cookie.Value, nil
+}
+
+// clientIP is the address a request came from. Behind the load balancer that
+// is the last address in X-Forwarded-For, as the ones before it are supplied
+// by the caller and can't be trusted.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+		if ip := strings.TrimSpace(addresses[len(addresses)-1]); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// bearerChallenge is the WWW-Authenticate header for a request that was
+// refused, as described by RFC 6750. The error code is left out when the
+// request had no token, and the scope is only given for insufficient_scope.
+func bearerChallenge(errorCode, scope string) string {
+	challenge := `Bearer realm="` + realm + `"`
+	if errorCode != "" {
+		challenge += `, error="` + errorCode + `"`
+	}
+	if scope != "" {
+		challenge += `, scope="` + scope + `"`
+	}
+
+	return challenge
+}
+
+// scopesFor returns the scopes granted to a client.
+func (a *Auth) scopesFor(email string) []string {
+	if scopes, ok := a.clientScopes[email]; ok {
+		return scopes
+	}
+
+	return DefaultScopes
+}
+
+// login checks the credentials, unless the caller has been locked out by too
+// many failed attempts.
+func (a *Auth) login(ctx context.Context, ip string, user loginUser) error {
+	if err := a.logins.Check(ctx, ip, user.Email); err != nil {
+		var lockedOut LockedOutError
+		if errors.As(err, &lockedOut) {
+			a.logger.WarnContext(ctx, "Login refused while locked out",
+				slog.String("ip", ip),
+				slog.String("email", user.Email),
+				slog.Duration("retry_after", lockedOut.RetryAfter))
+			return err
+		}
+
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+
+	err := a.validateCredentials(ctx, user)
+	if !errors.Is(err, ErrInvalidCredentials) {
+		if err == nil {
+			if err := a.logins.Succeed(ctx, user.Email); err != nil {
+				a.logger.ErrorContext(ctx, fmt.Sprintf("Failed to clear login attempts: %v", err))
+			}
+		}
+
+		return err
+	}
+
+	lockout, failErr := a.logins.Fail(ctx, ip, user.Email)
+	if failErr != nil {
+		a.logger.ErrorContext(ctx, fmt.Sprintf("Failed to record login attempt: %v", failErr))
+	} else if lockout > 0 {
+		a.logger.WarnContext(ctx, "Login locked out",
+			slog.String("ip", ip),
+			slog.String("email", user.Email),
+			slog.Duration("lockout", lockout))
+	}
+
+	return err
+}
+
 func (a *Auth) validateCredentials(ctx context.Context, user loginUser) error {
 	if user.Email == "" || user.Password == "" {
-		return fmt.Errorf("missing email or password")
+		return ErrInvalidCredentials
 	}
 
 	storedCredentials, err := a.credentials.FetchCredentials(ctx)
@@ -108,17 +414,32 @@ func (a *Auth) validateCredentials(ctx context.Context, user loginUser) error {
 
 	storedHash, ok := storedCredentials[user.Email]
 	if !ok {
-		return fmt.Errorf("unknown email: %s", user.Email)
+		_ = bcrypt.CompareHashAndPassword(unknownAccountHash, []byte(user.Password))
+		return ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(user.Password)); err != nil {
-		return fmt.Errorf("invalid credentials")
+		return ErrInvalidCredentials
 	}
 
 	return nil
 }
 
-func (a *Auth) respondWithError(ctx context.Context, w http.ResponseWriter, statusCode int, message string, err error) {
-	a.logger.ErrorContext(ctx, fmt.Sprintf("%s: %v", message, err))
-	http.Error(w, message, statusCode)
+func (a *Auth) respondWithError(w http.ResponseWriter, r *http.Request, problemType problem.Type, message string, err error) {
+	// Refusing a client is routine, so only failures of the service are
+	// logged as errors.
+	if problemType.Status >= 500 {
+		a.logger.ErrorContext(r.Context(), fmt.Sprintf("%s: %v", message, err))
+	} else {
+		a.logger.InfoContext(r.Context(), fmt.Sprintf("%s: %v", message, err))
+	}
+
+	if problem.Wanted(r) {
+		if err := problem.Write(w, problem.New(problemType, r, message)); err != nil {
+			a.logger.ErrorContext(r.Context(), fmt.Sprintf("Failed to encode response: %v", err))
+		}
+		return
+	}
+
+	http.Error(w, message, problemType.Status)
 }
@@ -8,18 +8,34 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var expectedError = errors.New("problem")
 
+func newTestLoginLimiter() *loginLimiter {
+	return &loginLimiter{
+		store:              newMemoryAttemptStore(),
+		now:                time.Now,
+		maxIPFailures:      20,
+		maxAccountFailures: 5,
+		lockout:            time.Minute,
+	}
+}
+
 func TestAuthAuthenticate(t *testing.T) {
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"not-a-password"}}`))
@@ -27,18 +43,19 @@ func TestAuthAuthenticate(t *testing.T) {
 	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
 	now := time.Now().UTC()
 
-	credentials := newMockCredentialsClient(t)
+	credentials := newMockCredentialStore(t)
 	credentials.EXPECT().
 		FetchCredentials(r.Context()).
 		Return(map[string]string{"john.doe@example.com": string(hash)}, nil)
 
 	tokens := newMockTokens(t)
 	tokens.EXPECT().
-		Generate().
+		Generate(Principal{ID: "john.doe@example.com", Scopes: DefaultScopes}).
 		Return("a-token", now, nil)
 
 	auth := &Auth{
 		credentials: credentials,
+		logins:      newTestLoginLimiter(),
 		tokens:      tokens,
 	}
 
@@ -50,24 +67,53 @@ func TestAuthAuthenticate(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("membrane=a-token; Path=/; Expires=%s; HttpOnly; SameSite=Strict", now.Format(http.TimeFormat)), resp.Header.Get("Set-Cookie"))
 }
 
+func TestAuthAuthenticate_ClientScopes(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"cop@example.com","password":"not-a-password"}}`))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
+	scopes := []string{ScopeSubmit, DocumentScope("COPORD")}
+
+	credentials := newMockCredentialStore(t)
+	credentials.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(map[string]string{"cop@example.com": string(hash)}, nil)
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Generate(Principal{ID: "cop@example.com", Scopes: scopes}).
+		Return("a-token", time.Now(), nil)
+
+	auth := &Auth{
+		credentials:  credentials,
+		logins:       newTestLoginLimiter(),
+		tokens:       tokens,
+		clientScopes: map[string][]string{"cop@example.com": scopes},
+	}
+
+	_, err := auth.Authenticate(w, r)
+	assert.NoError(t, err)
+}
+
 func TestAuthAuthenticate_TokenCannotBeGenerated(t *testing.T) {
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"not-a-password"}}`))
 
 	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
 
-	credentials := newMockCredentialsClient(t)
+	credentials := newMockCredentialStore(t)
 	credentials.EXPECT().
 		FetchCredentials(mock.Anything).
 		Return(map[string]string{"john.doe@example.com": string(hash)}, nil)
 
 	tokens := newMockTokens(t)
 	tokens.EXPECT().
-		Generate().
+		Generate(mock.Anything).
 		Return("", time.Time{}, expectedError)
 
 	auth := &Auth{
 		credentials: credentials,
+		logins:      newTestLoginLimiter(),
 		tokens:      tokens,
 	}
 
@@ -79,13 +125,14 @@ func TestAuthAuthenticate_CredentialsCannotBeFetched(t *testing.T) {
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"not-a-password"}}`))
 
-	credentials := newMockCredentialsClient(t)
+	credentials := newMockCredentialStore(t)
 	credentials.EXPECT().
 		FetchCredentials(mock.Anything).
 		Return(nil, expectedError)
 
 	auth := &Auth{
 		credentials: credentials,
+		logins:      newTestLoginLimiter(),
 	}
 
 	_, err := auth.Authenticate(w, r)
@@ -94,24 +141,19 @@ func TestAuthAuthenticate_CredentialsCannotBeFetched(t *testing.T) {
 
 func TestAuthAuthenticate_InvalidCredentials(t *testing.T) {
 	testcases := map[string]struct {
-		body  string
-		error string
+		body string
 	}{
 		"missing email": {
-			body:  `{"user":{"password":"not-a-password"}}`,
-			error: "missing email or password",
+			body: `{"user":{"password":"not-a-password"}}`,
 		},
 		"missing password": {
-			body:  `{"user":{"email":"john.doe@example.com"}}`,
-			error: "missing email or password",
+			body: `{"user":{"email":"john.doe@example.com"}}`,
 		},
 		"incorrect email": {
-			body:  `{"user":{"email":"who@example.com","password":"not-a-password"}}`,
-			error: "unknown email: who@example.com",
+			body: `{"user":{"email":"who@example.com","password":"not-a-password"}}`,
 		},
 		"incorrect password": {
-			body:  `{"user":{"email":"john.doe@example.com","password":"wrong"}}`,
-			error: "invalid credentials",
+			body: `{"user":{"email":"john.doe@example.com","password":"wrong"}}`,
 		},
 	}
 
@@ -122,7 +164,7 @@ func TestAuthAuthenticate_InvalidCredentials(t *testing.T) {
 
 			hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
 
-			credentials := newMockCredentialsClient(t)
+			credentials := newMockCredentialStore(t)
 			credentials.EXPECT().
 				FetchCredentials(mock.Anything).
 				Return(map[string]string{"john.doe@example.com": string(hash)}, nil).
@@ -130,39 +172,415 @@ func TestAuthAuthenticate_InvalidCredentials(t *testing.T) {
 
 			auth := &Auth{
 				credentials: credentials,
+				logins:      newTestLoginLimiter(),
 			}
 
 			_, err := auth.Authenticate(w, r)
-			assert.ErrorContains(t, err, tc.error)
+			assert.Equal(t, ErrInvalidCredentials, err)
+		})
+	}
+}
+
+func TestAuthAuthenticate_RecordsAudit(t *testing.T) {
+	appConfig := &config.Config{}
+	appConfig.Audit.File = filepath.Join(t.TempDir(), "audit.jsonl")
+	store := audit.NewStore(appConfig, nil, nil)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
+
+	credentials := newMockCredentialStore(t)
+	credentials.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(map[string]string{"john.doe@example.com": string(hash)}, nil)
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Generate(mock.Anything).
+		Return("a-token", time.Now(), nil)
+
+	auth := &Auth{
+		credentials: credentials,
+		logins:      newTestLoginLimiter(),
+		tokens:      tokens,
+		audit:       audit.NewLog(store, slog.New(slog.DiscardHandler)),
+	}
+
+	for _, password := range []string{"wrong", "not-a-password"} {
+		r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"`+password+`"}}`))
+		_, _ = auth.Authenticate(httptest.NewRecorder(), r)
+	}
+	assert.NoError(t, auth.audit.Close(context.Background()))
+
+	var events []audit.Event
+	assert.NoError(t, store.Entries(context.Background(), func(entry audit.Entry) error {
+		events = append(events, entry.Event)
+		return nil
+	}))
+	assert.Equal(t, []audit.Event{
+		{Action: audit.ActionLogin, Principal: "john.doe@example.com", Outcome: audit.OutcomeFailure, Detail: ErrInvalidCredentials.Error()},
+		{Action: audit.ActionLogin, Principal: "john.doe@example.com", Outcome: audit.OutcomeSuccess},
+	}, events)
+}
+
+func TestAuthAuthenticate_LocksOut(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
+
+	credentials := newMockCredentialStore(t)
+	credentials.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(map[string]string{"john.doe@example.com": string(hash)}, nil).
+		Times(5)
+
+	var buf bytes.Buffer
+	auth := &Auth{
+		credentials: credentials,
+		logins:      newTestLoginLimiter(),
+		logger:      slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+
+	login := func(password string) error {
+		r, _ := http.NewRequest(http.MethodPost, "/auth/sessions", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"`+password+`"}}`))
+		r.RemoteAddr = "192.0.2.1:1234"
+
+		_, err := auth.Authenticate(httptest.NewRecorder(), r)
+		return err
+	}
+
+	for range 5 {
+		assert.Equal(t, ErrInvalidCredentials, login("wrong"))
+	}
+	assert.Contains(t, buf.String(), `"msg":"Login locked out","ip":"192.0.2.1","email":"john.doe@example.com","lockout":60000000000`)
+
+	// the correct password is not checked while locked out
+	err := login("not-a-password")
+	var lockedOut LockedOutError
+	assert.ErrorAs(t, err, &lockedOut)
+	assert.Equal(t, time.Minute, lockedOut.RetryAfter)
+	assert.Contains(t, buf.String(), `"msg":"Login refused while locked out","ip":"192.0.2.1","email":"john.doe@example.com"`)
+}
+
+func TestAuthAuthenticate_ClearsFailuresOnSuccess(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-password"), 0)
+
+	credentials := newMockCredentialStore(t)
+	credentials.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(map[string]string{"john.doe@example.com": string(hash)}, nil)
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Generate(mock.Anything).
+		Return("a-token", time.Now(), nil)
+
+	auth := &Auth{
+		credentials: credentials,
+		tokens:      tokens,
+		logins:      newTestLoginLimiter(),
+	}
+
+	login := func(password string) error {
+		r, _ := http.NewRequest(http.MethodPost, "/auth/sessions", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"`+password+`"}}`))
+
+		_, err := auth.Authenticate(httptest.NewRecorder(), r)
+		return err
+	}
+
+	for range 4 {
+		assert.Error(t, login("wrong"))
+	}
+	assert.NoError(t, login("not-a-password"))
+
+	// the account starts counting from zero again
+	for range 4 {
+		assert.Equal(t, ErrInvalidCredentials, login("wrong"))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	testcases := map[string]struct {
+		remoteAddr string
+		forwarded  []string
+		ip         string
+	}{
+		"remote address": {
+			remoteAddr: "192.0.2.1:1234",
+			ip:         "192.0.2.1",
+		},
+		"forwarded": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"192.0.2.1"},
+			ip:         "192.0.2.1",
+		},
+		"forwarded through proxies": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"198.51.100.1, 192.0.2.1"},
+			ip:         "192.0.2.1",
+		},
+		"forwarded in several headers": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"198.51.100.1", "192.0.2.1"},
+			ip:         "192.0.2.1",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "/auth/sessions", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for _, forwarded := range tc.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			assert.Equal(t, tc.ip, clientIP(r))
 		})
 	}
 }
 
+func TestAuthLogout(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodDelete, "/auth/sessions", nil)
+	r.AddCookie(&http.Cookie{Name: cookieName, Value: "a-token"})
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Revoke(mock.Anything, "a-token").
+		Return(Principal{ID: "cop@example.com"}, nil)
+
+	auth := &Auth{
+		tokens: tokens,
+	}
+
+	assert.NoError(t, auth.Logout(w, r))
+	assert.Equal(t, "membrane=; Path=/; Max-Age=0; HttpOnly; SameSite=Strict", w.Result().Header.Get("Set-Cookie"))
+}
+
+func TestAuthLogout_Errors(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodDelete, "/auth/sessions", nil)
+
+	assert.Error(t, (&Auth{}).Logout(w, r))
+
+	r.Header.Set("Authorization", "Bearer a-token")
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Revoke(mock.Anything, "a-token").
+		Return(Principal{}, expectedError)
+
+	err := (&Auth{tokens: tokens}).Logout(w, r)
+	assert.ErrorIs(t, err, expectedError)
+	assert.Empty(t, w.Result().Header.Get("Set-Cookie"))
+}
+
+func TestAuthRevokePrincipal(t *testing.T) {
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		RevokePrincipal(mock.Anything, "cop@example.com").
+		Return(nil)
+
+	auth := &Auth{
+		tokens: tokens,
+	}
+
+	assert.NoError(t, auth.RevokePrincipal(context.Background(), "cop@example.com"))
+	assert.Error(t, auth.RevokePrincipal(context.Background(), ""))
+}
+
 func TestAuthCheck(t *testing.T) {
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"not-a-password"}}`))
 	r.AddCookie(&http.Cookie{Name: cookieName, Value: "a-token"})
 
+	principal := Principal{ID: "john.doe@example.com", Scopes: []string{ScopeSubmit}}
+
 	tokens := newMockTokens(t)
 	tokens.EXPECT().
-		Validate("a-token").
-		Return(nil)
+		Validate(mock.Anything, "a-token").
+		Return(principal, nil)
 
 	auth := &Auth{
 		tokens: tokens,
 	}
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, context.WithValue(context.Background(), constants.TokenContextKey, "a-token"), r.Context())
+		ctx := ContextWithPrincipal(context.Background(), principal)
+		assert.Equal(t, logger.ContextWithAttrs(ctx, slog.String("principal", "john.doe@example.com")), r.Context())
 
 		w.WriteHeader(http.StatusTeapot)
 	})
-	auth.Check(handler)(w, r)
+	auth.Check(handler, ScopeSubmit)(w, r)
 
 	resp := w.Result()
 	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
 }
 
+func TestAuthCheck_TokenSources(t *testing.T) {
+	testcases := map[string]struct {
+		authorization string
+		cookie        string
+		token         string
+	}{
+		"bearer token": {
+			authorization: "Bearer a-token",
+			token:         "a-token",
+		},
+		"bearer scheme is case insensitive": {
+			authorization: "bearer a-token",
+			token:         "a-token",
+		},
+		"bearer token is preferred to cookie": {
+			authorization: "Bearer a-token",
+			cookie:        "cookie-token",
+			token:         "a-token",
+		},
+		"other schemes are ignored": {
+			authorization: "Basic dXNlcjpwYXNz",
+			cookie:        "cookie-token",
+			token:         "cookie-token",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/ddc", nil)
+			r.Header.Set("Authorization", tc.authorization)
+			if tc.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: cookieName, Value: tc.cookie})
+			}
+
+			tokens := newMockTokens(t)
+			tokens.EXPECT().
+				Validate(mock.Anything, tc.token).
+				Return(Principal{ID: "john.doe@example.com"}, nil)
+
+			auth := &Auth{
+				tokens: tokens,
+			}
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})
+			auth.Check(handler)(w, r)
+
+			assert.Equal(t, http.StatusTeapot, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestAuthCheck_Challenges(t *testing.T) {
+	testcases := map[string]struct {
+		authorization string
+		validateError error
+		scopes        []string
+		status        int
+		challenge     string
+	}{
+		"missing token": {
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="opg-scanning"`,
+		},
+		"empty bearer token": {
+			authorization: "Bearer ",
+			status:        http.StatusUnauthorized,
+			challenge:     `Bearer realm="opg-scanning"`,
+		},
+		"invalid token": {
+			authorization: "Bearer a-token",
+			validateError: expectedError,
+			status:        http.StatusUnauthorized,
+			challenge:     `Bearer realm="opg-scanning", error="invalid_token"`,
+		},
+		"missing scope": {
+			authorization: "Bearer a-token",
+			scopes:        []string{ScopeAdmin},
+			status:        http.StatusForbidden,
+			challenge:     `Bearer realm="opg-scanning", error="insufficient_scope", scope="admin"`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/ddc", nil)
+			r.Header.Set("Authorization", tc.authorization)
+
+			tokens := newMockTokens(t)
+			tokens.EXPECT().
+				Validate(mock.Anything, "a-token").
+				Return(Principal{ID: "john.doe@example.com", Scopes: DefaultScopes}, tc.validateError).
+				Maybe()
+
+			auth := &Auth{
+				tokens: tokens,
+				logger: slog.New(slog.DiscardHandler),
+			}
+
+			auth.Check(http.NotFoundHandler(), tc.scopes...)(w, r)
+
+			resp := w.Result()
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.Equal(t, tc.challenge, resp.Header.Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestAuthCheck_RecordsPrincipalOnSpan(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodPost, "/api/ddc", nil)
+	r.AddCookie(&http.Cookie{Name: cookieName, Value: "a-token"})
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(r.Context(), "request")
+	r = r.WithContext(ctx)
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Validate(mock.Anything, "a-token").
+		Return(Principal{ID: "john.doe@example.com", Scopes: []string{ScopeSubmit}}, nil)
+
+	auth := &Auth{
+		tokens: tokens,
+	}
+
+	auth.Check(http.NotFoundHandler())(w, r)
+	span.End()
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Contains(t, spans[0].Attributes(), attribute.String("enduser.id", "john.doe@example.com"))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("enduser.scope", "submit"))
+	}
+}
+
+func TestAuthCheck_MissingScope(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodPost, "/api/ddc", nil)
+	r.Header.Set("Accept", "application/problem+json")
+	r.AddCookie(&http.Cookie{Name: cookieName, Value: "a-token"})
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Validate(mock.Anything, "a-token").
+		Return(Principal{ID: "john.doe@example.com", Scopes: []string{DocumentScope("COPORD")}}, nil)
+
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
+
+	auth := &Auth{
+		tokens: tokens,
+		logger: logger,
+	}
+
+	auth.Check(http.NotFoundHandler(), ScopeSubmit)(w, r)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.JSONEq(t, `{"type":"urn:opg-scanning:problem:forbidden","title":"Forbidden","status":403,"detail":"Forbidden: Missing scope submit","instance":"/api/ddc","code":"forbidden"}`, w.Body.String())
+
+	assert.Contains(t, logBuffer.String(), `msg="Forbidden: Missing scope submit: john.doe@example.com has not been granted submit"`)
+}
+
 func TestAuthCheck_InvalidToken(t *testing.T) {
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest(http.MethodGet, "", strings.NewReader(`{"user":{"email":"john.doe@example.com","password":"not-a-password"}}`))
@@ -170,8 +588,8 @@ func TestAuthCheck_InvalidToken(t *testing.T) {
 
 	tokens := newMockTokens(t)
 	tokens.EXPECT().
-		Validate("a-token").
-		Return(expectedError)
+		Validate(mock.Anything, "a-token").
+		Return(Principal{}, expectedError)
 
 	var logBuffer bytes.Buffer
 	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
@@ -182,8 +600,6 @@ func TestAuthCheck_InvalidToken(t *testing.T) {
 	}
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, context.WithValue(context.Background(), constants.TokenContextKey, "a-token"), r.Context())
-
 		w.WriteHeader(http.StatusTeapot)
 	})
 	auth.Check(handler)(w, r)
@@ -191,7 +607,7 @@ func TestAuthCheck_InvalidToken(t *testing.T) {
 	resp := w.Result()
 	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 
-	assert.Regexp(t, `^time=[0-9TZ\-:.+]+ level=ERROR msg="Unauthorized: Invalid token: problem"
+	assert.Regexp(t, `^time=[0-9TZ\-:.+]+ level=INFO msg="Unauthorized: Invalid token: problem"
 $`, logBuffer.String())
 }
 
@@ -207,8 +623,6 @@ func TestAuthCheck_MissingCookie(t *testing.T) {
 	}
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, context.WithValue(context.Background(), constants.TokenContextKey, "a-token"), r.Context())
-
 		w.WriteHeader(http.StatusTeapot)
 	})
 	auth.Check(handler)(w, r)
@@ -216,6 +630,23 @@ func TestAuthCheck_MissingCookie(t *testing.T) {
 	resp := w.Result()
 	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 
-	assert.Regexp(t, `^time=[0-9TZ\-:.+]+ level=ERROR msg="Unauthorized: Missing token: http: named cookie not present"
+	assert.Regexp(t, `^time=[0-9TZ\-:.+]+ level=INFO msg="Unauthorized: Missing token: http: named cookie not present"
 $`, logBuffer.String())
 }
+
+func TestAuthCheck_MissingCookieProblemDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/api/ddc", nil)
+	r.Header.Set("Accept", "application/problem+json")
+
+	auth := &Auth{
+		logger: slog.New(slog.DiscardHandler),
+	}
+
+	auth.Check(http.NotFoundHandler())(w, r)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"urn:opg-scanning:problem:unauthorized","title":"Unauthorized","status":401,"detail":"Unauthorized: Missing token","instance":"/api/ddc","code":"unauthorized"}`, w.Body.String())
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+)
+
+// hasClientCertificate reports whether the request was made with a client
+// certificate that the server verified.
+func hasClientCertificate(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0
+}
+
+// certificatePrincipal returns the principal a verified client certificate is
+// mapped to. Its subject alternative names are tried before its common name.
+func (a *Auth) certificatePrincipal(r *http.Request) (Principal, error) {
+	if !hasClientCertificate(r) {
+		return Principal{}, errors.New("no verified client certificate")
+	}
+
+	for _, identity := range certificateIdentities(r.TLS.VerifiedChains[0][0]) {
+		if id, ok := a.clientCertificates[identity]; ok {
+			return Principal{ID: id, Scopes: a.scopesFor(id)}, nil
+		}
+	}
+
+	return Principal{}, errors.New("client certificate is not mapped to a principal")
+}
+
+// certificateIdentities lists the names a certificate was issued for, in the
+// form used as keys in CLIENT_CERTIFICATES.
+func certificateIdentities(cert *x509.Certificate) []string {
+	var identities []string
+
+	for _, name := range cert.DNSNames {
+		identities = append(identities, "DNS:"+name)
+	}
+	for _, email := range cert.EmailAddresses {
+		identities = append(identities, "EMAIL:"+email)
+	}
+	for _, uri := range cert.URIs {
+		identities = append(identities, "URI:"+uri.String())
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, "CN="+cert.Subject.CommonName)
+	}
+
+	return identities
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestClientCertificate(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template.SerialNumber = big.NewInt(1)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return cert
+}
+
+func withClientCertificate(r *http.Request, cert *x509.Certificate) *http.Request {
+	r.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+
+	return r
+}
+
+func TestCertificateIdentities(t *testing.T) {
+	uri, _ := url.Parse("spiffe://opg/scanner")
+
+	cert := newTestClientCertificate(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "scanner"},
+		DNSNames:       []string{"scanner.supplier.example"},
+		EmailAddresses: []string{"scanning@supplier.example"},
+		URIs:           []*url.URL{uri},
+	})
+
+	assert.Equal(t, []string{
+		"DNS:scanner.supplier.example",
+		"EMAIL:scanning@supplier.example",
+		"URI:spiffe://opg/scanner",
+		"CN=scanner",
+	}, certificateIdentities(cert))
+}
+
+func TestAuthCheck_ClientCertificate(t *testing.T) {
+	testCases := map[string]struct {
+		cert      *x509.Certificate
+		principal Principal
+	}{
+		"by subject alternative name": {
+			cert:      newTestClientCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "other"}, DNSNames: []string{"scanner.supplier.example"}}),
+			principal: Principal{ID: "cop@example.com", Scopes: []string{ScopeSubmit, DocumentScope("COPORD")}},
+		},
+		"by common name": {
+			cert:      newTestClientCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "scanner"}}),
+			principal: Principal{ID: "scanner@example.com", Scopes: DefaultScopes},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := withClientCertificate(httptest.NewRequest(http.MethodPost, "/api/ddc", nil), tc.cert)
+
+			auth := &Auth{
+				clientScopes: map[string][]string{"cop@example.com": {ScopeSubmit, DocumentScope("COPORD")}},
+				clientCertificates: map[string]string{
+					"DNS:scanner.supplier.example": "cop@example.com",
+					"CN=scanner":                   "scanner@example.com",
+				},
+			}
+
+			var ctxPrincipal Principal
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxPrincipal, _ = PrincipalFromContext(r.Context())
+			})
+
+			auth.Check(handler, ScopeSubmit)(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+			assert.Equal(t, tc.principal, ctxPrincipal)
+		})
+	}
+}
+
+func TestAuthCheck_ClientCertificateNotMapped(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := withClientCertificate(httptest.NewRequest(http.MethodPost, "/api/ddc", nil),
+		newTestClientCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "stranger"}}))
+
+	auth := &Auth{
+		logger:             slog.New(slog.DiscardHandler),
+		clientCertificates: map[string]string{"CN=scanner": "scanner@example.com"},
+	}
+
+	auth.Check(http.NotFoundHandler())(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	assert.Equal(t, "Unauthorized: Unknown client certificate\n", w.Body.String())
+}
+
+func TestAuthCheck_TokenPreferredToClientCertificate(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := withClientCertificate(httptest.NewRequest(http.MethodPost, "/api/ddc", nil),
+		newTestClientCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "scanner"}}))
+	r.Header.Set("Authorization", "Bearer a-token")
+
+	tokens := newMockTokens(t)
+	tokens.EXPECT().
+		Validate(mock.Anything, "a-token").
+		Return(Principal{ID: "cop@example.com"}, nil)
+
+	auth := &Auth{
+		tokens:             tokens,
+		clientCertificates: map[string]string{"CN=scanner": "scanner@example.com"},
+	}
+
+	var ctxPrincipal Principal
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxPrincipal, _ = PrincipalFromContext(r.Context())
+	})
+
+	auth.Check(handler)(w, r)
+
+	assert.Equal(t, "cop@example.com", ctxPrincipal.ID)
+}
+
+func TestAuthCheck_UnverifiedClientCertificate(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc", nil)
+	r.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{newTestClientCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "scanner"}})},
+	}
+
+	auth := &Auth{
+		logger:             slog.New(slog.DiscardHandler),
+		clientCertificates: map[string]string{"CN=scanner": "scanner@example.com"},
+	}
+
+	auth.Check(http.NotFoundHandler())(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	assert.Equal(t, "Unauthorized: Missing token\n", w.Body.String())
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"golang.org/x/sync/singleflight"
+)
+
+// credentialStore provides the bcrypt hashed passwords that logins are checked
+// against, by email.
+type credentialStore interface {
+	FetchCredentials(ctx context.Context) (map[string]string, error)
+}
+
+type ssmClient interface {
+	GetSsmValue(ctx context.Context, name string) (string, error)
+}
+
+// newCredentialStore returns the store chosen by CREDENTIALS_STORE, cached so
+// that logins don't fetch the credentials each time.
+func newCredentialStore(appConfig *config.Config, logger *slog.Logger, awsClient awsClient) credentialStore {
+	var store credentialStore
+
+	switch appConfig.Auth.CredentialsStore {
+	case "secretsmanager":
+		store = &secretCredentialStore{secrets: awsClient, secretARN: appConfig.Auth.CredentialsARN}
+	case "file":
+		store = &fileCredentialStore{path: appConfig.Auth.CredentialsFile}
+	case "env":
+		store = &envCredentialStore{value: appConfig.Auth.Credentials}
+	default:
+		store = &ssmCredentialStore{ssm: awsClient, name: appConfig.Auth.CredentialsARN}
+	}
+
+	return &cachedCredentialStore{
+		store:        store,
+		ttl:          appConfig.Auth.CredentialsTTL,
+		maxStaleness: appConfig.Auth.CredentialsMaxStaleness,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// parseCredentials reads a JSON object of bcrypt hashes by email.
+func parseCredentials(value string) (map[string]string, error) {
+	value = strings.TrimPrefix(value, "kms:alias/aws/ssm:")
+
+	var credentials map[string]string
+	if err := json.Unmarshal([]byte(value), &credentials); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+
+	if len(credentials) == 0 {
+		return nil, errors.New("no credentials found")
+	}
+
+	return credentials, nil
+}
+
+// ssmCredentialStore reads credentials from an SSM parameter.
+type ssmCredentialStore struct {
+	ssm  ssmClient
+	name string
+}
+
+func (s *ssmCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	value, err := s.ssm.GetSsmValue(ctx, s.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials from SSM: %w", err)
+	}
+
+	return parseCredentials(value)
+}
+
+// secretCredentialStore reads credentials from a Secrets Manager secret.
+type secretCredentialStore struct {
+	secrets   secretsClient
+	secretARN string
+}
+
+func (s *secretCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	value, err := s.secrets.GetSecretValue(ctx, s.secretARN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials from Secrets Manager: %w", err)
+	}
+
+	return parseCredentials(value)
+}
+
+// fileCredentialStore reads credentials from a local JSON file, which is read
+// again each time so that it can be changed while running.
+type fileCredentialStore struct {
+	path string
+}
+
+func (s *fileCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	value, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	return parseCredentials(string(value))
+}
+
+// envCredentialStore reads credentials from the LOGIN_CREDENTIALS environment
+// variable.
+type envCredentialStore struct {
+	value string
+}
+
+func (s *envCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	if s.value == "" {
+		return nil, errors.New("LOGIN_CREDENTIALS is not set")
+	}
+
+	return parseCredentials(s.value)
+}
+
+// cachedCredentialStore keeps the credentials for ttl. After that they are
+// still used while they are refreshed in the background, and if refreshing
+// fails, until they are maxStaleness old. Only then do logins wait for, and
+// fail with, a fetch.
+type cachedCredentialStore struct {
+	store        credentialStore
+	ttl          time.Duration
+	maxStaleness time.Duration
+	logger       *slog.Logger
+	now          func() time.Time
+
+	fetches     singleflight.Group
+	mu          sync.RWMutex
+	credentials map[string]string
+	fetchedAt   time.Time
+}
+
+func (s *cachedCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	credentials, fetchedAt := s.credentials, s.fetchedAt
+	s.mu.RUnlock()
+
+	if credentials != nil {
+		age := s.now().Sub(fetchedAt)
+
+		if age < s.ttl {
+			return credentials, nil
+		}
+
+		if age < s.maxStaleness {
+			// Nothing waits for the result, so failures are only logged.
+			s.fetches.DoChan("credentials", func() (any, error) {
+				credentials, err := s.refresh(context.WithoutCancel(ctx))
+				if err != nil {
+					s.logger.WarnContext(ctx, "Failed to refresh credentials, using the last fetched", slog.Any("error", err))
+				}
+
+				return credentials, err
+			})
+
+			return credentials, nil
+		}
+	}
+
+	result, err, _ := s.fetches.Do("credentials", func() (any, error) {
+		return s.refresh(context.WithoutCancel(ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string]string), nil
+}
+
+func (s *cachedCredentialStore) refresh(ctx context.Context) (map[string]string, error) {
+	credentials, err := s.store.FetchCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.credentials = credentials
+	s.fetchedAt = s.now()
+	s.mu.Unlock()
+
+	return credentials, nil
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testCredentials = `{"cop@example.com":"$2a$10$hash"}`
+
+func TestParseCredentials(t *testing.T) {
+	credentials, err := parseCredentials("kms:alias/aws/ssm:" + testCredentials)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cop@example.com": "$2a$10$hash"}, credentials)
+
+	_, err = parseCredentials(`{`)
+	assert.ErrorContains(t, err, "failed to unmarshal credentials")
+
+	_, err = parseCredentials(`{}`)
+	assert.EqualError(t, err, "no credentials found")
+}
+
+func TestSsmCredentialStore(t *testing.T) {
+	ssm := newMockSsmClient(t)
+	ssm.EXPECT().
+		GetSsmValue(mock.Anything, "/local/local-credentials").
+		Return(testCredentials, nil).
+		Once()
+	ssm.EXPECT().
+		GetSsmValue(mock.Anything, "/local/local-credentials").
+		Return("", expectedError).
+		Once()
+
+	store := &ssmCredentialStore{ssm: ssm, name: "/local/local-credentials"}
+
+	credentials, err := store.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", credentials["cop@example.com"])
+
+	_, err = store.FetchCredentials(context.Background())
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func TestSecretCredentialStore(t *testing.T) {
+	secrets := newMockSecretsClient(t)
+	secrets.EXPECT().
+		GetSecretValue(mock.Anything, "aws::credentials").
+		Return(testCredentials, nil).
+		Once()
+	secrets.EXPECT().
+		GetSecretValue(mock.Anything, "aws::credentials").
+		Return("", expectedError).
+		Once()
+
+	store := &secretCredentialStore{secrets: secrets, secretARN: "aws::credentials"}
+
+	credentials, err := store.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", credentials["cop@example.com"])
+
+	_, err = store.FetchCredentials(context.Background())
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func TestFileCredentialStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	store := &fileCredentialStore{path: path}
+
+	_, err := store.FetchCredentials(context.Background())
+	assert.ErrorContains(t, err, "failed to read credentials file")
+
+	assert.NoError(t, os.WriteFile(path, []byte(testCredentials), 0o600))
+
+	credentials, err := store.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", credentials["cop@example.com"])
+}
+
+func TestEnvCredentialStore(t *testing.T) {
+	credentials, err := (&envCredentialStore{value: testCredentials}).FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", credentials["cop@example.com"])
+
+	_, err = (&envCredentialStore{}).FetchCredentials(context.Background())
+	assert.EqualError(t, err, "LOGIN_CREDENTIALS is not set")
+}
+
+func TestNewCredentialStore(t *testing.T) {
+	testCases := map[string]credentialStore{
+		"ssm":            &ssmCredentialStore{name: "aws::credentials"},
+		"secretsmanager": &secretCredentialStore{secretARN: "aws::credentials"},
+		"file":           &fileCredentialStore{path: "credentials.json"},
+		"env":            &envCredentialStore{value: testCredentials},
+	}
+
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := newCredentialStore(&config.Config{
+				Auth: config.Auth{
+					CredentialsStore:        name,
+					CredentialsARN:          "aws::credentials",
+					CredentialsFile:         "credentials.json",
+					Credentials:             testCredentials,
+					CredentialsTTL:          time.Minute,
+					CredentialsMaxStaleness: time.Hour,
+				},
+			}, slog.New(slog.DiscardHandler), nil)
+
+			cached := store.(*cachedCredentialStore)
+			assert.Equal(t, expected, cached.store)
+			assert.Equal(t, time.Minute, cached.ttl)
+			assert.Equal(t, time.Hour, cached.maxStaleness)
+		})
+	}
+}
+
+func TestCachedCredentialStore(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	first := map[string]string{"cop@example.com": "first"}
+	second := map[string]string{"cop@example.com": "second"}
+
+	refreshed := make(chan struct{})
+
+	store := newMockCredentialStore(t)
+	store.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(first, nil).
+		Once()
+	store.EXPECT().
+		FetchCredentials(mock.Anything).
+		RunAndReturn(func(context.Context) (map[string]string, error) {
+			defer close(refreshed)
+			return second, nil
+		}).
+		Once()
+
+	cached := &cachedCredentialStore{
+		store:        store,
+		ttl:          time.Minute,
+		maxStaleness: time.Hour,
+		logger:       slog.New(slog.DiscardHandler),
+		now:          func() time.Time { return now },
+	}
+
+	credentials, err := cached.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, first, credentials)
+
+	// used from the cache
+	now = now.Add(59 * time.Second)
+	credentials, _ = cached.FetchCredentials(context.Background())
+	assert.Equal(t, first, credentials)
+
+	// used while refreshing in the background
+	now = now.Add(time.Second)
+	credentials, _ = cached.FetchCredentials(context.Background())
+	assert.Equal(t, first, credentials)
+
+	<-refreshed
+	assert.Eventually(t, func() bool {
+		credentials, _ := cached.FetchCredentials(context.Background())
+		return credentials["cop@example.com"] == "second"
+	}, time.Second, time.Millisecond)
+}
+
+func TestCachedCredentialStore_RefreshFails(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	credentials := map[string]string{"cop@example.com": "hash"}
+
+	refreshed := make(chan struct{}, 1)
+
+	store := newMockCredentialStore(t)
+	store.EXPECT().
+		FetchCredentials(mock.Anything).
+		Return(credentials, nil).
+		Once()
+	store.EXPECT().
+		FetchCredentials(mock.Anything).
+		RunAndReturn(func(context.Context) (map[string]string, error) {
+			refreshed <- struct{}{}
+			return nil, expectedError
+		})
+
+	cached := &cachedCredentialStore{
+		store:        store,
+		ttl:          time.Minute,
+		maxStaleness: time.Hour,
+		logger:       slog.New(slog.DiscardHandler),
+		now:          func() time.Time { return now },
+	}
+
+	_, err := cached.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+
+	// the last good copy is used while it is within the max staleness
+	now = now.Add(59 * time.Minute)
+	result, err := cached.FetchCredentials(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, credentials, result)
+	<-refreshed
+
+	// then logins wait for a fetch
+	now = now.Add(time.Minute)
+	_, err = cached.FetchCredentials(context.Background())
+	assert.ErrorIs(t, err, expectedError)
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+)
+
+// revocationCacheTTL is how long a lookup in the denylist is reused for, so a
+// token revoked by another instance can still be used for this long.
+const revocationCacheTTL = 30 * time.Second
+
+// maxCachedRevocations is how many lookups are cached. When there are more,
+// the least recently used are dropped. Every revocation is kept in DynamoDB,
+// so a dropped lookup is only made again, and no revocation is forgotten.
+const maxCachedRevocations = 10000
+
+type dynamoClient interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// Denylist records revoked tokens in DynamoDB. A single token is revoked by
+// its ID, and every token issued to a principal by the time they were
+// revoked. Entries expire, using the table's TTL, once the tokens they revoke
+// would have expired anyway.
+type Denylist struct {
+	dynamo    dynamoClient
+	tableName string
+	now       func() time.Time
+
+	// cache holds when each key was revoked, or the zero time when it has no
+	// entry.
+	cache *cache.Cache[string, time.Time]
+}
+
+func NewDenylist(dynamo dynamoClient, tableName string) *Denylist {
+	return &Denylist{
+		dynamo:    dynamo,
+		tableName: tableName,
+		now:       time.Now,
+		cache:     cache.New[string, time.Time](maxCachedRevocations),
+	}
+}
+
+// RevokeToken revokes a token by its ID until it expires.
+func (d *Denylist) RevokeToken(ctx context.Context, id string, expiresAt time.Time) error {
+	return d.put(ctx, "REVOKED#TOKEN#"+id, expiresAt)
+}
+
+// RevokePrincipal revokes every token issued to a principal so far. Tokens
+// issued afterwards are not affected.
+func (d *Denylist) RevokePrincipal(ctx context.Context, id string, expiresAt time.Time) error {
+	return d.put(ctx, "REVOKED#PRINCIPAL#"+id, expiresAt)
+}
+
+// Revoked reports whether a token has been revoked, either by its ID or along
+// with the other tokens issued to its principal. Tokens issued before IDs were
+// added can only be revoked by principal.
+func (d *Denylist) Revoked(ctx context.Context, id, principalID string, issuedAt time.Time) (bool, error) {
+	if id != "" {
+		revokedAt, err := d.lookup(ctx, "REVOKED#TOKEN#"+id)
+		if err != nil {
+			return false, err
+		}
+		if !revokedAt.IsZero() {
+			return true, nil
+		}
+	}
+
+	revokedAt, err := d.lookup(ctx, "REVOKED#PRINCIPAL#"+principalID)
+	if err != nil {
+		return false, err
+	}
+
+	// Token times are in whole seconds, so a token issued in the same second
+	// as the revocation is treated as revoked.
+	return !revokedAt.IsZero() && !issuedAt.After(revokedAt), nil
+}
+
+func (d *Denylist) put(ctx context.Context, key string, expiresAt time.Time) error {
+	revokedAt := d.now().Truncate(time.Second)
+
+	_, err := d.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.tableName),
+		Item: map[string]types.AttributeValue{
+			"PK":        &types.AttributeValueMemberS{Value: key},
+			"SK":        &types.AttributeValueMemberS{Value: key},
+			"RevokedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(revokedAt.Unix(), 10)},
+			"ExpiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	d.store(key, revokedAt)
+	return nil
+}
+
+func (d *Denylist) lookup(ctx context.Context, key string) (time.Time, error) {
+	if revokedAt, ok := d.cache.Get(key, d.now()); ok {
+		return revokedAt, nil
+	}
+
+	output, err := d.dynamo.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: key},
+			"SK": &types.AttributeValueMemberS{Value: key},
+		},
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var revokedAt time.Time
+	if attr, ok := output.Item["RevokedAt"].(*types.AttributeValueMemberN); ok {
+		seconds, err := strconv.ParseInt(attr.Value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		revokedAt = time.Unix(seconds, 0)
+	}
+
+	d.store(key, revokedAt)
+	return revokedAt, nil
+}
+
+func (d *Denylist) store(key string, revokedAt time.Time) {
+	d.cache.Set(key, revokedAt, d.now().Add(revocationCacheTTL))
+}
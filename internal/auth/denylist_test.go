@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func keyIs(key string) any {
+	return mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+		return input.Key["PK"].(*types.AttributeValueMemberS).Value == key
+	})
+}
+
+func revokedItem(revokedAt time.Time) *dynamodb.GetItemOutput {
+	return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+		"RevokedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(revokedAt.Unix(), 10)},
+	}}
+}
+
+func TestDenylistRevoked(t *testing.T) {
+	revokedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		token     *dynamodb.GetItemOutput
+		principal *dynamodb.GetItemOutput
+		issuedAt  time.Time
+		revoked   bool
+	}{
+		"not revoked": {
+			token:     &dynamodb.GetItemOutput{},
+			principal: &dynamodb.GetItemOutput{},
+			issuedAt:  revokedAt,
+		},
+		"token revoked": {
+			token:    revokedItem(revokedAt),
+			issuedAt: revokedAt.Add(-time.Minute),
+			revoked:  true,
+		},
+		"issued before principal revoked": {
+			token:     &dynamodb.GetItemOutput{},
+			principal: revokedItem(revokedAt),
+			issuedAt:  revokedAt.Add(-time.Minute),
+			revoked:   true,
+		},
+		"issued as principal revoked": {
+			token:     &dynamodb.GetItemOutput{},
+			principal: revokedItem(revokedAt),
+			issuedAt:  revokedAt,
+			revoked:   true,
+		},
+		"issued after principal revoked": {
+			token:     &dynamodb.GetItemOutput{},
+			principal: revokedItem(revokedAt),
+			issuedAt:  revokedAt.Add(time.Second),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dynamo := newMockDynamoClient(t)
+			dynamo.EXPECT().
+				GetItem(mock.Anything, keyIs("REVOKED#TOKEN#token-id")).
+				Return(tc.token, nil).
+				Once()
+			if tc.principal != nil {
+				dynamo.EXPECT().
+					GetItem(mock.Anything, keyIs("REVOKED#PRINCIPAL#cop@example.com")).
+					Return(tc.principal, nil).
+					Once()
+			}
+
+			denylist := NewDenylist(dynamo, "table")
+
+			// the second check is answered from the cache
+			for range 2 {
+				revoked, err := denylist.Revoked(context.Background(), "token-id", "cop@example.com", tc.issuedAt)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.revoked, revoked)
+			}
+		})
+	}
+}
+
+func TestDenylistRevokedCacheExpires(t *testing.T) {
+	now := time.Now()
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		GetItem(mock.Anything, keyIs("REVOKED#PRINCIPAL#cop@example.com")).
+		Return(&dynamodb.GetItemOutput{}, nil).
+		Twice()
+
+	denylist := NewDenylist(dynamo, "table")
+	denylist.now = func() time.Time { return now }
+
+	_, _ = denylist.Revoked(context.Background(), "", "cop@example.com", now)
+
+	now = now.Add(revocationCacheTTL)
+	_, _ = denylist.Revoked(context.Background(), "", "cop@example.com", now)
+}
+
+func TestDenylistRevokedAfterEviction(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		GetItem(mock.Anything, keyIs("REVOKED#PRINCIPAL#cop@example.com")).
+		Return(revokedItem(revokedAt), nil).
+		Twice()
+	dynamo.EXPECT().
+		GetItem(mock.Anything, keyIs("REVOKED#PRINCIPAL#other@example.com")).
+		Return(&dynamodb.GetItemOutput{}, nil).
+		Once()
+
+	denylist := NewDenylist(dynamo, "table")
+	denylist.cache = cache.New[string, time.Time](1)
+
+	revoked, _ := denylist.Revoked(context.Background(), "", "cop@example.com", revokedAt)
+	assert.True(t, revoked)
+
+	// evicts the lookup for cop@example.com, which is made again
+	_, _ = denylist.Revoked(context.Background(), "", "other@example.com", revokedAt)
+
+	revoked, _ = denylist.Revoked(context.Background(), "", "cop@example.com", revokedAt)
+	assert.True(t, revoked)
+}
+
+func TestDenylistRevokeToken(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, &dynamodb.PutItemInput{
+			TableName: aws.String("table"),
+			Item: map[string]types.AttributeValue{
+				"PK":        &types.AttributeValueMemberS{Value: "REVOKED#TOKEN#token-id"},
+				"SK":        &types.AttributeValueMemberS{Value: "REVOKED#TOKEN#token-id"},
+				"RevokedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+				"ExpiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+			},
+		}).
+		Return(&dynamodb.PutItemOutput{}, nil)
+
+	denylist := NewDenylist(dynamo, "table")
+	denylist.now = func() time.Time { return now }
+
+	assert.NoError(t, denylist.RevokeToken(context.Background(), "token-id", expiresAt))
+
+	// revoking is seen straight away, without looking it up
+	revoked, err := denylist.Revoked(context.Background(), "token-id", "cop@example.com", now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestDenylistRevokePrincipal(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return input.Item["PK"].(*types.AttributeValueMemberS).Value == "REVOKED#PRINCIPAL#cop@example.com"
+		})).
+		Return(&dynamodb.PutItemOutput{}, nil)
+
+	denylist := NewDenylist(dynamo, "table")
+	denylist.now = func() time.Time { return now }
+
+	assert.NoError(t, denylist.RevokePrincipal(context.Background(), "cop@example.com", now.Add(time.Hour)))
+
+	revoked, err := denylist.Revoked(context.Background(), "", "cop@example.com", now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = denylist.Revoked(context.Background(), "", "cop@example.com", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var errUnknownSigningKey = errors.New("unknown signing key")
+
+// signingKey is a key that tokens can be signed with. A key with no ID is the
+// legacy secret, used for tokens without a kid header.
+type signingKey struct {
+	ID string `json:"kid"`
+	// Algorithm is HS256, the default, RS256 or ES256. HS256 keys are a
+	// Secret, and the others a PEM encoded RSA or P-256 PrivateKey.
+	Algorithm  string `json:"alg,omitempty"`
+	Secret     string `json:"secret,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	// ActiveFrom is when new tokens can start being signed with the key. Set
+	// it far enough ahead for every instance to have fetched the key, so that
+	// they can all validate tokens signed with it.
+	ActiveFrom time.Time `json:"activeFrom,omitzero"`
+	// ExpiresAt is when tokens signed with the key stop being accepted. Set
+	// it at least JWT_EXPIRATION after the next key becomes active.
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+
+	// privateKey is PrivateKey once parsed.
+	privateKey crypto.Signer
+}
+
+func (k signingKey) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		return jwt.SigningMethodRS256
+	case jwt.SigningMethodES256.Alg():
+		return jwt.SigningMethodES256
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// signingMaterial is what the key's signing method signs with.
+func (k signingKey) signingMaterial() any {
+	if k.privateKey != nil {
+		return k.privateKey
+	}
+
+	return []byte(k.Secret)
+}
+
+// verificationMaterial is what the key's signing method verifies with.
+func (k signingKey) verificationMaterial() any {
+	if k.privateKey != nil {
+		return k.privateKey.Public()
+	}
+
+	return []byte(k.Secret)
+}
+
+// parse checks the key has what its algorithm needs.
+func (k *signingKey) parse() error {
+	switch k.Algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		if k.Secret == "" {
+			return fmt.Errorf("signing key %q has no secret", k.ID)
+		}
+
+		return nil
+
+	case jwt.SigningMethodRS256.Alg():
+		key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(k.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("signing key %q has an invalid private key: %w", k.ID, err)
+		}
+
+		k.privateKey = key
+
+	case jwt.SigningMethodES256.Alg():
+		key, err := jwt.ParseECPrivateKeyFromPEM([]byte(k.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("signing key %q has an invalid private key: %w", k.ID, err)
+		}
+		if key.Curve != elliptic.P256() {
+			return fmt.Errorf("signing key %q must use the P-256 curve for ES256", k.ID)
+		}
+
+		k.privateKey = key
+
+	default:
+		return fmt.Errorf("signing key %q has unsupported algorithm %q", k.ID, k.Algorithm)
+	}
+
+	// Public keys are published by their ID, so it can't be left out.
+	if k.ID == "" {
+		return fmt.Errorf("signing keys using %s must have a kid", k.Algorithm)
+	}
+
+	return nil
+}
+
+func (k signingKey) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// keyring is the set of keys tokens are signed and validated with.
+type keyring struct {
+	keys []signingKey
+}
+
+// parseKeyring reads the signing secret, which is either a JSON object listing
+// the keys:
+//
+//	{"keys": [{"kid": "2026-03", "alg": "ES256", "privateKey": "...", "activeFrom": "2026-03-04T12:00:00Z"}]}
+//
+// or, as before keys were rotated, a plain string used as the only key.
+func parseKeyring(secret string) (*keyring, error) {
+	if !strings.HasPrefix(strings.TrimSpace(secret), "{") {
+		if secret == "" {
+			return nil, errors.New("signing secret is empty")
+		}
+
+		return &keyring{keys: []signingKey{{Secret: secret}}}, nil
+	}
+
+	var v struct {
+		Keys []signingKey `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(secret), &v); err != nil {
+		return nil, fmt.Errorf("signing secret is not a valid keyring: %w", err)
+	}
+
+	if len(v.Keys) == 0 {
+		return nil, errors.New("signing secret has no keys")
+	}
+
+	seen := map[string]bool{}
+	for i, key := range v.Keys {
+		if err := v.Keys[i].parse(); err != nil {
+			return nil, err
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("signing key %q is listed more than once", key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	return &keyring{keys: v.Keys}, nil
+}
+
+// signingKey returns the key new tokens are signed with, which is the most
+// recently activated key that hasn't expired.
+func (k *keyring) signingKey(now time.Time) (signingKey, error) {
+	var (
+		current signingKey
+		found   bool
+	)
+
+	for _, key := range k.keys {
+		if key.expired(now) || key.ActiveFrom.After(now) {
+			continue
+		}
+
+		if !found || !key.ActiveFrom.Before(current.ActiveFrom) {
+			current, found = key, true
+		}
+	}
+
+	if !found {
+		return signingKey{}, errors.New("no active signing key")
+	}
+
+	return current, nil
+}
+
+// verificationKey returns the key with an ID, unless it has expired. Keys that
+// are not active yet are accepted, in case another instance's clock is ahead.
+func (k *keyring) verificationKey(id string, now time.Time) (signingKey, error) {
+	for _, key := range k.keys {
+		if key.ID != id {
+			continue
+		}
+
+		if key.expired(now) {
+			return signingKey{}, fmt.Errorf("signing key %q has expired", id)
+		}
+
+		return key, nil
+	}
+
+	return signingKey{}, errUnknownSigningKey
+}
+
+// keySet returns the public keys of the asymmetric keys that haven't expired,
+// including those not active yet so that they are known before they are used.
+func (k *keyring) keySet(now time.Time) KeySet {
+	keySet := KeySet{Keys: []JSONWebKey{}}
+
+	for _, key := range k.keys {
+		if key.privateKey == nil || key.expired(now) {
+			continue
+		}
+
+		keySet.Keys = append(keySet.Keys, newJSONWebKey(key.ID, key.Algorithm, key.privateKey.Public()))
+	}
+
+	return keySet
+}
+
+// KeySet is a JWK Set, as described by RFC 7517, of the public keys tokens
+// can be verified with.
+type KeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a public RSA or EC key, as described by RFC 7517 and RFC 7518.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+func newJSONWebKey(id, algorithm string, publicKey crypto.PublicKey) JSONWebKey {
+	jwk := JSONWebKey{
+		ID:        id,
+		Algorithm: algorithm,
+		Use:       "sig",
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64URL(key.N.Bytes())
+		jwk.E = base64URL(big.NewInt(int64(key.E)).Bytes())
+
+	case *ecdsa.PublicKey:
+		// Coordinates are padded to the size of the curve, as RFC 7518
+		// requires.
+		encoded, _ := key.Bytes()
+		size := (len(encoded) - 1) / 2
+
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = base64URL(encoded[1 : 1+size])
+		jwk.Y = base64URL(encoded[1+size:])
+	}
+
+	return jwk
+}
+
+func base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyring(t *testing.T) {
+	ring, err := parseKeyring("my-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, []signingKey{{Secret: "my-secret"}}, ring.keys)
+
+	ring, err = parseKeyring(`{"keys":[{"kid":"2026-02","secret":"old","expiresAt":"2026-03-04T13:00:00Z"},{"kid":"2026-03","secret":"new","activeFrom":"2026-03-04T12:00:00Z"}]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []signingKey{
+		{ID: "2026-02", Secret: "old", ExpiresAt: time.Date(2026, time.March, 4, 13, 0, 0, 0, time.UTC)},
+		{ID: "2026-03", Secret: "new", ActiveFrom: time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)},
+	}, ring.keys)
+}
+
+func TestParseKeyring_Invalid(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	testCases := map[string]string{
+		"empty":                  "",
+		"invalid JSON":           `{"keys":`,
+		"no keys":                `{"keys":[]}`,
+		"missing secret":         `{"keys":[{"kid":"a"}]}`,
+		"duplicate kid":          `{"keys":[{"kid":"a","secret":"x"},{"kid":"a","secret":"y"}]}`,
+		"unsupported":            `{"keys":[{"kid":"a","alg":"HS512","secret":"x"}]}`,
+		"invalid pem":            `{"keys":[{"kid":"a","alg":"RS256","privateKey":"not a key"}]}`,
+		"wrong key type":         `{"keys":[{"kid":"a","alg":"ES256","privateKey":` + strconv.Quote(rsaPEM(rsaKey)) + `}]}`,
+		"wrong curve":            `{"keys":[{"kid":"a","alg":"ES256","privateKey":` + strconv.Quote(ecPEM(p384Key)) + `}]}`,
+		"asymmetric without kid": `{"keys":[{"alg":"RS256","privateKey":` + strconv.Quote(rsaPEM(rsaKey)) + `}]}`,
+	}
+
+	for name, secret := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseKeyring(secret)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyringSigningKey(t *testing.T) {
+	rotatedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	ring := &keyring{keys: []signingKey{
+		{ID: "old", Secret: "old", ExpiresAt: rotatedAt.Add(time.Hour)},
+		{ID: "new", Secret: "new", ActiveFrom: rotatedAt},
+	}}
+
+	key, err := ring.signingKey(rotatedAt.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "old", key.ID)
+
+	key, err = ring.signingKey(rotatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", key.ID)
+
+	_, err = (&keyring{keys: []signingKey{{ID: "old", Secret: "old", ExpiresAt: rotatedAt}}}).signingKey(rotatedAt)
+	assert.Error(t, err)
+}
+
+func TestKeyringVerificationKey(t *testing.T) {
+	rotatedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	ring := &keyring{keys: []signingKey{
+		{ID: "old", Secret: "old", ExpiresAt: rotatedAt.Add(time.Hour)},
+		{ID: "new", Secret: "new", ActiveFrom: rotatedAt},
+	}}
+
+	key, err := ring.verificationKey("old", rotatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "old", key.Secret)
+
+	key, err = ring.verificationKey("new", rotatedAt.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "new", key.Secret)
+
+	_, err = ring.verificationKey("old", rotatedAt.Add(time.Hour))
+	assert.ErrorContains(t, err, "expired")
+
+	_, err = ring.verificationKey("other", rotatedAt)
+	assert.Equal(t, errUnknownSigningKey, err)
+
+	_, err = ring.verificationKey("", rotatedAt)
+	assert.Equal(t, errUnknownSigningKey, err)
+}
+
+func TestKeyringKeySet(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	ring := &keyring{keys: []signingKey{
+		{Secret: "legacy"},
+		{ID: "expired", Algorithm: "ES256", privateKey: ecKey, ExpiresAt: now},
+		{ID: "next", Algorithm: "ES256", privateKey: ecKey, ActiveFrom: now.Add(time.Hour)},
+	}}
+
+	keySet := ring.keySet(now)
+	assert.Len(t, keySet.Keys, 1)
+
+	jwk := keySet.Keys[0]
+	assert.Equal(t, "EC", jwk.KeyType)
+	assert.Equal(t, "next", jwk.ID)
+	assert.Equal(t, "ES256", jwk.Algorithm)
+	assert.Equal(t, "sig", jwk.Use)
+	assert.Equal(t, "P-256", jwk.Curve)
+	assert.Equal(t, &ecKey.PublicKey, publicKeyFromJWK(t, jwk))
+
+	assert.Equal(t, KeySet{Keys: []JSONWebKey{}}, (&keyring{keys: []signingKey{{Secret: "legacy"}}}).keySet(now))
+}
@@ -8,9 +8,223 @@ import (
 	"context"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	mock "github.com/stretchr/testify/mock"
 )
 
+// newMockAttemptStore creates a new instance of mockAttemptStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockAttemptStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockAttemptStore {
+	mock := &mockAttemptStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockAttemptStore is an autogenerated mock type for the attemptStore type
+type mockAttemptStore struct {
+	mock.Mock
+}
+
+type mockAttemptStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockAttemptStore) EXPECT() *mockAttemptStore_Expecter {
+	return &mockAttemptStore_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type mockAttemptStore
+func (_mock *mockAttemptStore) Delete(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockAttemptStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockAttemptStore_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *mockAttemptStore_Expecter) Delete(ctx interface{}, key interface{}) *mockAttemptStore_Delete_Call {
+	return &mockAttemptStore_Delete_Call{Call: _e.mock.On("Delete", ctx, key)}
+}
+
+func (_c *mockAttemptStore_Delete_Call) Run(run func(ctx context.Context, key string)) *mockAttemptStore_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAttemptStore_Delete_Call) Return(err error) *mockAttemptStore_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockAttemptStore_Delete_Call) RunAndReturn(run func(ctx context.Context, key string) error) *mockAttemptStore_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type mockAttemptStore
+func (_mock *mockAttemptStore) Get(ctx context.Context, key string) (loginAttempts, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 loginAttempts
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (loginAttempts, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) loginAttempts); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Get(0).(loginAttempts)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAttemptStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockAttemptStore_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *mockAttemptStore_Expecter) Get(ctx interface{}, key interface{}) *mockAttemptStore_Get_Call {
+	return &mockAttemptStore_Get_Call{Call: _e.mock.On("Get", ctx, key)}
+}
+
+func (_c *mockAttemptStore_Get_Call) Run(run func(ctx context.Context, key string)) *mockAttemptStore_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAttemptStore_Get_Call) Return(loginAttemptsMoqParam loginAttempts, err error) *mockAttemptStore_Get_Call {
+	_c.Call.Return(loginAttemptsMoqParam, err)
+	return _c
+}
+
+func (_c *mockAttemptStore_Get_Call) RunAndReturn(run func(ctx context.Context, key string) (loginAttempts, error)) *mockAttemptStore_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Put provides a mock function for the type mockAttemptStore
+func (_mock *mockAttemptStore) Put(ctx context.Context, key string, attempts loginAttempts) error {
+	ret := _mock.Called(ctx, key, attempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, loginAttempts) error); ok {
+		r0 = returnFunc(ctx, key, attempts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockAttemptStore_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type mockAttemptStore_Put_Call struct {
+	*mock.Call
+}
+
+// Put is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - attempts loginAttempts
+func (_e *mockAttemptStore_Expecter) Put(ctx interface{}, key interface{}, attempts interface{}) *mockAttemptStore_Put_Call {
+	return &mockAttemptStore_Put_Call{Call: _e.mock.On("Put", ctx, key, attempts)}
+}
+
+func (_c *mockAttemptStore_Put_Call) Run(run func(ctx context.Context, key string, attempts loginAttempts)) *mockAttemptStore_Put_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 loginAttempts
+		if args[2] != nil {
+			arg2 = args[2].(loginAttempts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAttemptStore_Put_Call) Return(err error) *mockAttemptStore_Put_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockAttemptStore_Put_Call) RunAndReturn(run func(ctx context.Context, key string, attempts loginAttempts) error) *mockAttemptStore_Put_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockTokens creates a new instance of mockTokens. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockTokens(t interface {
@@ -22,139 +236,973 @@ func newMockTokens(t interface {
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
-	return mock
+	return mock
+}
+
+// mockTokens is an autogenerated mock type for the tokens type
+type mockTokens struct {
+	mock.Mock
+}
+
+type mockTokens_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockTokens) EXPECT() *mockTokens_Expecter {
+	return &mockTokens_Expecter{mock: &_m.Mock}
+}
+
+// Generate provides a mock function for the type mockTokens
+func (_mock *mockTokens) Generate(principal Principal) (string, time.Time, error) {
+	ret := _mock.Called(principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Generate")
+	}
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(Principal) (string, time.Time, error)); ok {
+		return returnFunc(principal)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Principal) string); ok {
+		r0 = returnFunc(principal)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(Principal) time.Time); ok {
+		r1 = returnFunc(principal)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+	if returnFunc, ok := ret.Get(2).(func(Principal) error); ok {
+		r2 = returnFunc(principal)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockTokens_Generate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generate'
+type mockTokens_Generate_Call struct {
+	*mock.Call
+}
+
+// Generate is a helper method to define mock.On call
+//   - principal Principal
+func (_e *mockTokens_Expecter) Generate(principal interface{}) *mockTokens_Generate_Call {
+	return &mockTokens_Generate_Call{Call: _e.mock.On("Generate", principal)}
+}
+
+func (_c *mockTokens_Generate_Call) Run(run func(principal Principal)) *mockTokens_Generate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Principal
+		if args[0] != nil {
+			arg0 = args[0].(Principal)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokens_Generate_Call) Return(s string, time1 time.Time, err error) *mockTokens_Generate_Call {
+	_c.Call.Return(s, time1, err)
+	return _c
+}
+
+func (_c *mockTokens_Generate_Call) RunAndReturn(run func(principal Principal) (string, time.Time, error)) *mockTokens_Generate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// KeySet provides a mock function for the type mockTokens
+func (_mock *mockTokens) KeySet(context1 context.Context) (KeySet, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KeySet")
+	}
+
+	var r0 KeySet
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (KeySet, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) KeySet); ok {
+		r0 = returnFunc(context1)
+	} else {
+		r0 = ret.Get(0).(KeySet)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockTokens_KeySet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KeySet'
+type mockTokens_KeySet_Call struct {
+	*mock.Call
+}
+
+// KeySet is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *mockTokens_Expecter) KeySet(context1 interface{}) *mockTokens_KeySet_Call {
+	return &mockTokens_KeySet_Call{Call: _e.mock.On("KeySet", context1)}
+}
+
+func (_c *mockTokens_KeySet_Call) Run(run func(context1 context.Context)) *mockTokens_KeySet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokens_KeySet_Call) Return(keySet KeySet, err error) *mockTokens_KeySet_Call {
+	_c.Call.Return(keySet, err)
+	return _c
+}
+
+func (_c *mockTokens_KeySet_Call) RunAndReturn(run func(context1 context.Context) (KeySet, error)) *mockTokens_KeySet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function for the type mockTokens
+func (_mock *mockTokens) Revoke(context1 context.Context, s string) (Principal, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 Principal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (Principal, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) Principal); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(Principal)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockTokens_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type mockTokens_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *mockTokens_Expecter) Revoke(context1 interface{}, s interface{}) *mockTokens_Revoke_Call {
+	return &mockTokens_Revoke_Call{Call: _e.mock.On("Revoke", context1, s)}
+}
+
+func (_c *mockTokens_Revoke_Call) Run(run func(context1 context.Context, s string)) *mockTokens_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokens_Revoke_Call) Return(principal Principal, err error) *mockTokens_Revoke_Call {
+	_c.Call.Return(principal, err)
+	return _c
+}
+
+func (_c *mockTokens_Revoke_Call) RunAndReturn(run func(context1 context.Context, s string) (Principal, error)) *mockTokens_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokePrincipal provides a mock function for the type mockTokens
+func (_mock *mockTokens) RevokePrincipal(context1 context.Context, s string) error {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokePrincipal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockTokens_RevokePrincipal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokePrincipal'
+type mockTokens_RevokePrincipal_Call struct {
+	*mock.Call
+}
+
+// RevokePrincipal is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *mockTokens_Expecter) RevokePrincipal(context1 interface{}, s interface{}) *mockTokens_RevokePrincipal_Call {
+	return &mockTokens_RevokePrincipal_Call{Call: _e.mock.On("RevokePrincipal", context1, s)}
+}
+
+func (_c *mockTokens_RevokePrincipal_Call) Run(run func(context1 context.Context, s string)) *mockTokens_RevokePrincipal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokens_RevokePrincipal_Call) Return(err error) *mockTokens_RevokePrincipal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockTokens_RevokePrincipal_Call) RunAndReturn(run func(context1 context.Context, s string) error) *mockTokens_RevokePrincipal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Validate provides a mock function for the type mockTokens
+func (_mock *mockTokens) Validate(context1 context.Context, s string) (Principal, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 Principal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (Principal, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) Principal); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(Principal)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockTokens_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type mockTokens_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *mockTokens_Expecter) Validate(context1 interface{}, s interface{}) *mockTokens_Validate_Call {
+	return &mockTokens_Validate_Call{Call: _e.mock.On("Validate", context1, s)}
+}
+
+func (_c *mockTokens_Validate_Call) Run(run func(context1 context.Context, s string)) *mockTokens_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokens_Validate_Call) Return(principal Principal, err error) *mockTokens_Validate_Call {
+	_c.Call.Return(principal, err)
+	return _c
+}
+
+func (_c *mockTokens_Validate_Call) RunAndReturn(run func(context1 context.Context, s string) (Principal, error)) *mockTokens_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockAwsClient creates a new instance of mockAwsClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockAwsClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockAwsClient {
+	mock := &mockAwsClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockAwsClient is an autogenerated mock type for the awsClient type
+type mockAwsClient struct {
+	mock.Mock
+}
+
+type mockAwsClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockAwsClient) EXPECT() *mockAwsClient_Expecter {
+	return &mockAwsClient_Expecter{mock: &_m.Mock}
+}
+
+// GetSecretValue provides a mock function for the type mockAwsClient
+func (_mock *mockAwsClient) GetSecretValue(ctx context.Context, secretName string) (string, error) {
+	ret := _mock.Called(ctx, secretName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecretValue")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, secretName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, secretName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, secretName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAwsClient_GetSecretValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSecretValue'
+type mockAwsClient_GetSecretValue_Call struct {
+	*mock.Call
+}
+
+// GetSecretValue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - secretName string
+func (_e *mockAwsClient_Expecter) GetSecretValue(ctx interface{}, secretName interface{}) *mockAwsClient_GetSecretValue_Call {
+	return &mockAwsClient_GetSecretValue_Call{Call: _e.mock.On("GetSecretValue", ctx, secretName)}
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) Run(run func(ctx context.Context, secretName string)) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) Return(s string, err error) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) RunAndReturn(run func(ctx context.Context, secretName string) (string, error)) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSsmValue provides a mock function for the type mockAwsClient
+func (_mock *mockAwsClient) GetSsmValue(ctx context.Context, name string) (string, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSsmValue")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAwsClient_GetSsmValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSsmValue'
+type mockAwsClient_GetSsmValue_Call struct {
+	*mock.Call
+}
+
+// GetSsmValue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *mockAwsClient_Expecter) GetSsmValue(ctx interface{}, name interface{}) *mockAwsClient_GetSsmValue_Call {
+	return &mockAwsClient_GetSsmValue_Call{Call: _e.mock.On("GetSsmValue", ctx, name)}
+}
+
+func (_c *mockAwsClient_GetSsmValue_Call) Run(run func(ctx context.Context, name string)) *mockAwsClient_GetSsmValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAwsClient_GetSsmValue_Call) Return(s string, err error) *mockAwsClient_GetSsmValue_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockAwsClient_GetSsmValue_Call) RunAndReturn(run func(ctx context.Context, name string) (string, error)) *mockAwsClient_GetSsmValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockCredentialStore creates a new instance of mockCredentialStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockCredentialStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockCredentialStore {
+	mock := &mockCredentialStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockCredentialStore is an autogenerated mock type for the credentialStore type
+type mockCredentialStore struct {
+	mock.Mock
+}
+
+type mockCredentialStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockCredentialStore) EXPECT() *mockCredentialStore_Expecter {
+	return &mockCredentialStore_Expecter{mock: &_m.Mock}
+}
+
+// FetchCredentials provides a mock function for the type mockCredentialStore
+func (_mock *mockCredentialStore) FetchCredentials(ctx context.Context) (map[string]string, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchCredentials")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]string); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockCredentialStore_FetchCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchCredentials'
+type mockCredentialStore_FetchCredentials_Call struct {
+	*mock.Call
+}
+
+// FetchCredentials is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockCredentialStore_Expecter) FetchCredentials(ctx interface{}) *mockCredentialStore_FetchCredentials_Call {
+	return &mockCredentialStore_FetchCredentials_Call{Call: _e.mock.On("FetchCredentials", ctx)}
+}
+
+func (_c *mockCredentialStore_FetchCredentials_Call) Run(run func(ctx context.Context)) *mockCredentialStore_FetchCredentials_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockCredentialStore_FetchCredentials_Call) Return(stringToString map[string]string, err error) *mockCredentialStore_FetchCredentials_Call {
+	_c.Call.Return(stringToString, err)
+	return _c
+}
+
+func (_c *mockCredentialStore_FetchCredentials_Call) RunAndReturn(run func(ctx context.Context) (map[string]string, error)) *mockCredentialStore_FetchCredentials_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockSsmClient creates a new instance of mockSsmClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockSsmClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockSsmClient {
+	mock := &mockSsmClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockSsmClient is an autogenerated mock type for the ssmClient type
+type mockSsmClient struct {
+	mock.Mock
+}
+
+type mockSsmClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockSsmClient) EXPECT() *mockSsmClient_Expecter {
+	return &mockSsmClient_Expecter{mock: &_m.Mock}
+}
+
+// GetSsmValue provides a mock function for the type mockSsmClient
+func (_mock *mockSsmClient) GetSsmValue(ctx context.Context, name string) (string, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSsmValue")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockSsmClient_GetSsmValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSsmValue'
+type mockSsmClient_GetSsmValue_Call struct {
+	*mock.Call
+}
+
+// GetSsmValue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *mockSsmClient_Expecter) GetSsmValue(ctx interface{}, name interface{}) *mockSsmClient_GetSsmValue_Call {
+	return &mockSsmClient_GetSsmValue_Call{Call: _e.mock.On("GetSsmValue", ctx, name)}
+}
+
+func (_c *mockSsmClient_GetSsmValue_Call) Run(run func(ctx context.Context, name string)) *mockSsmClient_GetSsmValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockSsmClient_GetSsmValue_Call) Return(s string, err error) *mockSsmClient_GetSsmValue_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockSsmClient_GetSsmValue_Call) RunAndReturn(run func(ctx context.Context, name string) (string, error)) *mockSsmClient_GetSsmValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDynamoClient creates a new instance of mockDynamoClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDynamoClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDynamoClient {
+	mock := &mockDynamoClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockDynamoClient is an autogenerated mock type for the dynamoClient type
+type mockDynamoClient struct {
+	mock.Mock
+}
+
+type mockDynamoClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDynamoClient) EXPECT() *mockDynamoClient_Expecter {
+	return &mockDynamoClient_Expecter{mock: &_m.Mock}
+}
+
+// DeleteItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 *dynamodb.DeleteItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) *dynamodb.DeleteItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DeleteItemOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockDynamoClient_DeleteItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteItem'
+type mockDynamoClient_DeleteItem_Call struct {
+	*mock.Call
+}
+
+// DeleteItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DeleteItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) DeleteItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_DeleteItem_Call {
+	return &mockDynamoClient_DeleteItem_Call{Call: _e.mock.On("DeleteItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-// mockTokens is an autogenerated mock type for the tokens type
-type mockTokens struct {
-	mock.Mock
+func (_c *mockDynamoClient_DeleteItem_Call) Run(run func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.DeleteItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.DeleteItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
 }
 
-type mockTokens_Expecter struct {
-	mock *mock.Mock
+func (_c *mockDynamoClient_DeleteItem_Call) Return(deleteItemOutput *dynamodb.DeleteItemOutput, err error) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Return(deleteItemOutput, err)
+	return _c
 }
 
-func (_m *mockTokens) EXPECT() *mockTokens_Expecter {
-	return &mockTokens_Expecter{mock: &_m.Mock}
+func (_c *mockDynamoClient_DeleteItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)) *mockDynamoClient_DeleteItem_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// Generate provides a mock function for the type mockTokens
-func (_mock *mockTokens) Generate() (string, time.Time, error) {
-	ret := _mock.Called()
+// GetItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Generate")
+		panic("no return value specified for GetItem")
 	}
 
-	var r0 string
-	var r1 time.Time
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func() (string, time.Time, error)); ok {
-		return returnFunc()
-	}
-	if returnFunc, ok := ret.Get(0).(func() string); ok {
-		r0 = returnFunc()
-	} else {
-		r0 = ret.Get(0).(string)
+	var r0 *dynamodb.GetItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
 	}
-	if returnFunc, ok := ret.Get(1).(func() time.Time); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) *dynamodb.GetItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
 	} else {
-		r1 = ret.Get(1).(time.Time)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.GetItemOutput)
+		}
 	}
-	if returnFunc, ok := ret.Get(2).(func() error); ok {
-		r2 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// mockTokens_Generate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generate'
-type mockTokens_Generate_Call struct {
+// mockDynamoClient_GetItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetItem'
+type mockDynamoClient_GetItem_Call struct {
 	*mock.Call
 }
 
-// Generate is a helper method to define mock.On call
-func (_e *mockTokens_Expecter) Generate() *mockTokens_Generate_Call {
-	return &mockTokens_Generate_Call{Call: _e.mock.On("Generate")}
+// GetItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.GetItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) GetItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_GetItem_Call {
+	return &mockDynamoClient_GetItem_Call{Call: _e.mock.On("GetItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *mockTokens_Generate_Call) Run(run func()) *mockTokens_Generate_Call {
+func (_c *mockDynamoClient_GetItem_Call) Run(run func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_GetItem_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.GetItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.GetItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
 	})
 	return _c
 }
 
-func (_c *mockTokens_Generate_Call) Return(s string, time1 time.Time, err error) *mockTokens_Generate_Call {
-	_c.Call.Return(s, time1, err)
+func (_c *mockDynamoClient_GetItem_Call) Return(getItemOutput *dynamodb.GetItemOutput, err error) *mockDynamoClient_GetItem_Call {
+	_c.Call.Return(getItemOutput, err)
 	return _c
 }
 
-func (_c *mockTokens_Generate_Call) RunAndReturn(run func() (string, time.Time, error)) *mockTokens_Generate_Call {
+func (_c *mockDynamoClient_GetItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)) *mockDynamoClient_GetItem_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Validate provides a mock function for the type mockTokens
-func (_mock *mockTokens) Validate(s string) error {
-	ret := _mock.Called(s)
+// PutItem provides a mock function for the type mockDynamoClient
+func (_mock *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Validate")
+		panic("no return value specified for PutItem")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
-		r0 = returnFunc(s)
+	var r0 *dynamodb.PutItemOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) *dynamodb.PutItemOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.PutItemOutput)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// mockTokens_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
-type mockTokens_Validate_Call struct {
+// mockDynamoClient_PutItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutItem'
+type mockDynamoClient_PutItem_Call struct {
 	*mock.Call
 }
 
-// Validate is a helper method to define mock.On call
-//   - s string
-func (_e *mockTokens_Expecter) Validate(s interface{}) *mockTokens_Validate_Call {
-	return &mockTokens_Validate_Call{Call: _e.mock.On("Validate", s)}
+// PutItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.PutItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *mockDynamoClient_Expecter) PutItem(ctx interface{}, params interface{}, optFns ...interface{}) *mockDynamoClient_PutItem_Call {
+	return &mockDynamoClient_PutItem_Call{Call: _e.mock.On("PutItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *mockTokens_Validate_Call) Run(run func(s string)) *mockTokens_Validate_Call {
+func (_c *mockDynamoClient_PutItem_Call) Run(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options))) *mockDynamoClient_PutItem_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.PutItemInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.PutItemInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
 		}
+		arg2 = variadicArgs
 		run(
 			arg0,
+			arg1,
+			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *mockTokens_Validate_Call) Return(err error) *mockTokens_Validate_Call {
-	_c.Call.Return(err)
+func (_c *mockDynamoClient_PutItem_Call) Return(putItemOutput *dynamodb.PutItemOutput, err error) *mockDynamoClient_PutItem_Call {
+	_c.Call.Return(putItemOutput, err)
 	return _c
 }
 
-func (_c *mockTokens_Validate_Call) RunAndReturn(run func(s string) error) *mockTokens_Validate_Call {
+func (_c *mockDynamoClient_PutItem_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)) *mockDynamoClient_PutItem_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMockCredentialsClient creates a new instance of mockCredentialsClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// newMockNonceStore creates a new instance of mockNonceStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMockCredentialsClient(t interface {
+func newMockNonceStore(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mockCredentialsClient {
-	mock := &mockCredentialsClient{}
+}) *mockNonceStore {
+	mock := &mockNonceStore{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -162,77 +1210,87 @@ func newMockCredentialsClient(t interface {
 	return mock
 }
 
-// mockCredentialsClient is an autogenerated mock type for the credentialsClient type
-type mockCredentialsClient struct {
+// mockNonceStore is an autogenerated mock type for the nonceStore type
+type mockNonceStore struct {
 	mock.Mock
 }
 
-type mockCredentialsClient_Expecter struct {
+type mockNonceStore_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mockCredentialsClient) EXPECT() *mockCredentialsClient_Expecter {
-	return &mockCredentialsClient_Expecter{mock: &_m.Mock}
+func (_m *mockNonceStore) EXPECT() *mockNonceStore_Expecter {
+	return &mockNonceStore_Expecter{mock: &_m.Mock}
 }
 
-// FetchCredentials provides a mock function for the type mockCredentialsClient
-func (_mock *mockCredentialsClient) FetchCredentials(ctx context.Context) (map[string]string, error) {
-	ret := _mock.Called(ctx)
+// Use provides a mock function for the type mockNonceStore
+func (_mock *mockNonceStore) Use(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	ret := _mock.Called(ctx, key, expiresAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FetchCredentials")
+		panic("no return value specified for Use")
 	}
 
-	var r0 map[string]string
+	var r0 bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]string, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return returnFunc(ctx, key, expiresAt)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]string); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = returnFunc(ctx, key, expiresAt)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]string)
-		}
+		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, key, expiresAt)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockCredentialsClient_FetchCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchCredentials'
-type mockCredentialsClient_FetchCredentials_Call struct {
+// mockNonceStore_Use_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Use'
+type mockNonceStore_Use_Call struct {
 	*mock.Call
 }
 
-// FetchCredentials is a helper method to define mock.On call
+// Use is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *mockCredentialsClient_Expecter) FetchCredentials(ctx interface{}) *mockCredentialsClient_FetchCredentials_Call {
-	return &mockCredentialsClient_FetchCredentials_Call{Call: _e.mock.On("FetchCredentials", ctx)}
+//   - key string
+//   - expiresAt time.Time
+func (_e *mockNonceStore_Expecter) Use(ctx interface{}, key interface{}, expiresAt interface{}) *mockNonceStore_Use_Call {
+	return &mockNonceStore_Use_Call{Call: _e.mock.On("Use", ctx, key, expiresAt)}
 }
 
-func (_c *mockCredentialsClient_FetchCredentials_Call) Run(run func(ctx context.Context)) *mockCredentialsClient_FetchCredentials_Call {
+func (_c *mockNonceStore_Use_Call) Run(run func(ctx context.Context, key string, expiresAt time.Time)) *mockNonceStore_Use_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *mockCredentialsClient_FetchCredentials_Call) Return(stringToString map[string]string, err error) *mockCredentialsClient_FetchCredentials_Call {
-	_c.Call.Return(stringToString, err)
+func (_c *mockNonceStore_Use_Call) Return(b bool, err error) *mockNonceStore_Use_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *mockCredentialsClient_FetchCredentials_Call) RunAndReturn(run func(ctx context.Context) (map[string]string, error)) *mockCredentialsClient_FetchCredentials_Call {
+func (_c *mockNonceStore_Use_Call) RunAndReturn(run func(ctx context.Context, key string, expiresAt time.Time) (bool, error)) *mockNonceStore_Use_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -329,3 +1387,234 @@ func (_c *mockSecretsClient_GetSecretValue_Call) RunAndReturn(run func(ctx conte
 	_c.Call.Return(run)
 	return _c
 }
+
+// newMockRevocations creates a new instance of mockRevocations. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockRevocations(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockRevocations {
+	mock := &mockRevocations{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockRevocations is an autogenerated mock type for the revocations type
+type mockRevocations struct {
+	mock.Mock
+}
+
+type mockRevocations_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockRevocations) EXPECT() *mockRevocations_Expecter {
+	return &mockRevocations_Expecter{mock: &_m.Mock}
+}
+
+// RevokePrincipal provides a mock function for the type mockRevocations
+func (_mock *mockRevocations) RevokePrincipal(ctx context.Context, id string, expiresAt time.Time) error {
+	ret := _mock.Called(ctx, id, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokePrincipal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockRevocations_RevokePrincipal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokePrincipal'
+type mockRevocations_RevokePrincipal_Call struct {
+	*mock.Call
+}
+
+// RevokePrincipal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - expiresAt time.Time
+func (_e *mockRevocations_Expecter) RevokePrincipal(ctx interface{}, id interface{}, expiresAt interface{}) *mockRevocations_RevokePrincipal_Call {
+	return &mockRevocations_RevokePrincipal_Call{Call: _e.mock.On("RevokePrincipal", ctx, id, expiresAt)}
+}
+
+func (_c *mockRevocations_RevokePrincipal_Call) Run(run func(ctx context.Context, id string, expiresAt time.Time)) *mockRevocations_RevokePrincipal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockRevocations_RevokePrincipal_Call) Return(err error) *mockRevocations_RevokePrincipal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockRevocations_RevokePrincipal_Call) RunAndReturn(run func(ctx context.Context, id string, expiresAt time.Time) error) *mockRevocations_RevokePrincipal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function for the type mockRevocations
+func (_mock *mockRevocations) RevokeToken(ctx context.Context, id string, expiresAt time.Time) error {
+	ret := _mock.Called(ctx, id, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockRevocations_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type mockRevocations_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - expiresAt time.Time
+func (_e *mockRevocations_Expecter) RevokeToken(ctx interface{}, id interface{}, expiresAt interface{}) *mockRevocations_RevokeToken_Call {
+	return &mockRevocations_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, id, expiresAt)}
+}
+
+func (_c *mockRevocations_RevokeToken_Call) Run(run func(ctx context.Context, id string, expiresAt time.Time)) *mockRevocations_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockRevocations_RevokeToken_Call) Return(err error) *mockRevocations_RevokeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockRevocations_RevokeToken_Call) RunAndReturn(run func(ctx context.Context, id string, expiresAt time.Time) error) *mockRevocations_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoked provides a mock function for the type mockRevocations
+func (_mock *mockRevocations) Revoked(ctx context.Context, id string, principalID string, issuedAt time.Time) (bool, error) {
+	ret := _mock.Called(ctx, id, principalID, issuedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return returnFunc(ctx, id, principalID, issuedAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = returnFunc(ctx, id, principalID, issuedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, id, principalID, issuedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockRevocations_Revoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoked'
+type mockRevocations_Revoked_Call struct {
+	*mock.Call
+}
+
+// Revoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - principalID string
+//   - issuedAt time.Time
+func (_e *mockRevocations_Expecter) Revoked(ctx interface{}, id interface{}, principalID interface{}, issuedAt interface{}) *mockRevocations_Revoked_Call {
+	return &mockRevocations_Revoked_Call{Call: _e.mock.On("Revoked", ctx, id, principalID, issuedAt)}
+}
+
+func (_c *mockRevocations_Revoked_Call) Run(run func(ctx context.Context, id string, principalID string, issuedAt time.Time)) *mockRevocations_Revoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockRevocations_Revoked_Call) Return(b bool, err error) *mockRevocations_Revoked_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *mockRevocations_Revoked_Call) RunAndReturn(run func(ctx context.Context, id string, principalID string, issuedAt time.Time) (bool, error)) *mockRevocations_Revoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+)
+
+// Scopes that can be granted to a client.
+const (
+	// ScopeSubmit allows a client to submit sets to /api/ddc.
+	ScopeSubmit = "submit"
+	// ScopeAdmin allows a client to use admin endpoints.
+	ScopeAdmin = "admin"
+	// ScopeAllDocuments allows a client to submit documents of any type.
+	ScopeAllDocuments = documentScopePrefix + "*"
+
+	documentScopePrefix = "document:"
+)
+
+// DefaultScopes are granted to clients without scopes of their own, which is
+// everything a scanning supplier needed before scopes were introduced.
+var DefaultScopes = []string{ScopeSubmit, ScopeAllDocuments}
+
+// DocumentScope is the scope that allows a client to submit documents of a
+// type, such as "document:COPORD".
+func DocumentScope(documentType string) string {
+	return documentScopePrefix + documentType
+}
+
+// Principal is the client a request was authenticated as.
+type Principal struct {
+	ID     string
+	Scopes []string
+}
+
+// Can reports whether the principal has been granted a scope.
+func (p Principal) Can(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// CanSubmit reports whether the principal may submit documents of a type.
+func (p Principal) CanSubmit(documentType string) bool {
+	return p.Can(ScopeAllDocuments) || p.Can(DocumentScope(documentType))
+}
+
+func (p Principal) scopeClaim() string {
+	return strings.Join(p.Scopes, " ")
+}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal.
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, constants.PrincipalContextKey, principal)
+}
+
+// PrincipalFromContext returns the principal a request was authenticated as,
+// if it was authenticated.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(constants.PrincipalContextKey).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalCanSubmit(t *testing.T) {
+	supplier := Principal{ID: "supplier@example.com", Scopes: DefaultScopes}
+	assert.True(t, supplier.CanSubmit("LP1F"))
+	assert.True(t, supplier.CanSubmit("COPORD"))
+	assert.False(t, supplier.Can(ScopeAdmin))
+
+	cop := Principal{ID: "cop@example.com", Scopes: []string{ScopeSubmit, DocumentScope("COPORD"), DocumentScope("DEPREPORTS")}}
+	assert.True(t, cop.CanSubmit("COPORD"))
+	assert.True(t, cop.CanSubmit("DEPREPORTS"))
+	assert.False(t, cop.CanSubmit("LP1F"))
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := Principal{ID: "cop@example.com", Scopes: []string{ScopeSubmit}}
+
+	fromContext, ok := PrincipalFromContext(ContextWithPrincipal(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Equal(t, principal, fromContext)
+}
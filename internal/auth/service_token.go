@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"golang.org/x/sync/singleflight"
+)
+
+// ServiceTokens mints the short-lived tokens this service calls Sirius with.
+// They are signed with a keyring of their own, so Sirius doesn't need to trust
+// the tokens clients log in with, and don't need a request to be minted, so
+// background jobs can use them too.
+type ServiceTokens struct {
+	secrets secretsClient
+	config  *config.Config
+	now     func() time.Time
+
+	fetches   singleflight.Group
+	mu        sync.RWMutex
+	keyring   *keyring
+	lastFetch time.Time
+}
+
+func NewServiceTokens(appConfig *config.Config, secrets secretsClient) *ServiceTokens {
+	return &ServiceTokens{
+		secrets: secrets,
+		config:  appConfig,
+		now:     time.Now,
+	}
+}
+
+// ServiceToken mints a token for a call to Sirius. When the context holds a
+// principal, such as the client that submitted a set, it is named by the
+// submitter claim.
+func (s *ServiceTokens) ServiceToken(ctx context.Context) (string, error) {
+	ring, err := s.signingKeyring(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	key, err := ring.signingKey(now)
+	if err != nil {
+		return "", err
+	}
+
+	// Sirius expects session-data to hold the API user, as it does for the
+	// tokens clients log in with.
+	claims := jwt.MapClaims{
+		"session-data": s.config.Auth.ApiUsername,
+		"sub":          s.config.Auth.JWTIssuer,
+		"iss":          s.config.Auth.JWTIssuer,
+		"aud":          []string{s.config.Auth.SiriusJWTAudience},
+		"jti":          uuid.NewString(),
+		"iat":          now.Unix(),
+		"exp":          now.Add(s.config.Auth.SiriusJWTExpiration).Unix(),
+	}
+
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		claims["submitter"] = principal.ID
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+
+	signedToken, err := token.SignedString(key.signingMaterial())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service token: %w", err)
+	}
+
+	return signedToken, nil
+}
+
+// signingKeyring returns the keyring, fetching it when it is older than
+// secretTTL. Concurrent callers share a single fetch.
+func (s *ServiceTokens) signingKeyring(ctx context.Context) (*keyring, error) {
+	s.mu.RLock()
+	ring, lastFetch := s.keyring, s.lastFetch
+	s.mu.RUnlock()
+
+	if ring != nil && s.now().Sub(lastFetch) < secretTTL {
+		return ring, nil
+	}
+
+	v, err, _ := s.fetches.Do("keyring", func() (any, error) {
+		secret, err := s.secrets.GetSecretValue(context.WithoutCancel(ctx), s.config.Auth.SiriusJWTSecretARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch service token signing secret: %w", err)
+		}
+
+		ring, err := parseKeyring(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.keyring = ring
+		s.lastFetch = s.now()
+		s.mu.Unlock()
+
+		return ring, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*keyring), nil
+}
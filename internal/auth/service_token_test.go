@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServiceToken(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::sirius-secret-arn").
+		Return(`{"keys":[{"kid":"sirius-1","secret":"sirius-secret"}]}`, nil).
+		Once()
+
+	tokens := NewServiceTokens(&config.Config{
+		Auth: config.Auth{
+			ApiUsername:         "user@host.example",
+			JWTIssuer:           "opg-scanning",
+			SiriusJWTSecretARN:  "aws::sirius-secret-arn",
+			SiriusJWTAudience:   "sirius",
+			SiriusJWTExpiration: 5 * time.Minute,
+		},
+	}, secretsClient)
+	tokens.now = func() time.Time { return now }
+
+	testcases := map[string]struct {
+		ctx       context.Context
+		submitter any
+	}{
+		"with principal": {
+			ctx:       ContextWithPrincipal(context.Background(), Principal{ID: "cop@example.com"}),
+			submitter: "cop@example.com",
+		},
+		"without principal": {
+			ctx: context.Background(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			tokenString, err := tokens.ServiceToken(tc.ctx)
+			assert.Nil(t, err)
+
+			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+				return []byte("sirius-secret"), nil
+			}, jwt.WithTimeFunc(func() time.Time { return now }))
+			assert.Nil(t, err)
+
+			claims := token.Claims.(jwt.MapClaims)
+			assert.Equal(t, "sirius-1", token.Header["kid"])
+			assert.Equal(t, "user@host.example", claims["session-data"])
+			assert.Equal(t, "opg-scanning", claims["sub"])
+			assert.Equal(t, "opg-scanning", claims["iss"])
+			assert.Equal(t, []any{"sirius"}, claims["aud"])
+			assert.NotEmpty(t, claims["jti"])
+			assert.Equal(t, float64(now.Add(5*time.Minute).Unix()), claims["exp"])
+			assert.Equal(t, tc.submitter, claims["submitter"])
+			assert.NotContains(t, claims, "scope")
+		})
+	}
+}
+
+func TestServiceToken_SecretError(t *testing.T) {
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::sirius-secret-arn").
+		Return("", expectedError)
+
+	tokens := NewServiceTokens(&config.Config{Auth: config.Auth{SiriusJWTSecretARN: "aws::sirius-secret-arn"}}, secretsClient)
+
+	_, err := tokens.ServiceToken(context.Background())
+	assert.ErrorIs(t, err, expectedError)
+}
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"golang.org/x/sync/singleflight"
+)
+
+// Headers sent with a signed request.
+const (
+	SignatureClientHeader    = "X-Signature-Client"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+	SignatureHeader          = "X-Signature"
+)
+
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+// maxTrackedNonces is how many nonces are remembered in memory. Forgetting one
+// before its timestamp is too old would let the request be replayed, so when
+// that many are in use, further signed requests are refused until some
+// expire.
+const maxTrackedNonces = 100000
+
+// SignRequest signs a request for a client, as a client sending signed
+// requests would. The body must be the request's body.
+func SignRequest(r *http.Request, clientID string, key []byte, body []byte, timestamp time.Time, nonce string) {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	r.Header.Set(SignatureClientHeader, clientID)
+	r.Header.Set(SignatureTimestampHeader, ts)
+	r.Header.Set(SignatureNonceHeader, nonce)
+	r.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(requestSignature(key, r.Method, r.URL.EscapedPath(), ts, nonce, body)))
+}
+
+// requestSignature is the HMAC-SHA256, under the client's key, of the method,
+// path, timestamp, nonce and hex encoded SHA-256 of the body, each on its own
+// line.
+func requestSignature(key []byte, method, path, timestamp, nonce string, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = io.WriteString(mac, method+"\n"+path+"\n"+timestamp+"\n"+nonce+"\n"+hex.EncodeToString(bodyHash[:]))
+
+	return mac.Sum(nil)
+}
+
+// isSignedRequest reports whether a request is trying to authenticate with a
+// signature.
+func isSignedRequest(r *http.Request) bool {
+	return r.Header.Get(SignatureHeader) != ""
+}
+
+type nonceStore interface {
+	// Use records that a nonce has been used, and reports false if it
+	// already had been.
+	Use(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+}
+
+// requestVerifier checks signed requests, using keys for each client read
+// from a secret holding a JSON object of base64 encoded keys by client ID.
+type requestVerifier struct {
+	secrets   secretsClient
+	secretARN string
+	skew      time.Duration
+	nonces    nonceStore
+	now       func() time.Time
+
+	fetches   singleflight.Group
+	mu        sync.RWMutex
+	keys      map[string][]byte
+	lastFetch time.Time
+}
+
+// Verify checks a request's signature and returns the client that signed it.
+// The request's body is read, and replaced so that it can be read again.
+func (v *requestVerifier) Verify(w http.ResponseWriter, r *http.Request) (string, error) {
+	clientID := r.Header.Get(SignatureClientHeader)
+	timestamp := r.Header.Get(SignatureTimestampHeader)
+	nonce := r.Header.Get(SignatureNonceHeader)
+
+	if clientID == "" || timestamp == "" || nonce == "" {
+		return "", errors.New("signed request is missing a header")
+	}
+
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return "", fmt.Errorf("nonce must be between %d and %d characters", minNonceLength, maxNonceLength)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil {
+		return "", fmt.Errorf("signature is not base64: %w", err)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("timestamp is not a number: %w", err)
+	}
+
+	now := v.now()
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-v.skew)) || signedAt.After(now.Add(v.skew)) {
+		return "", fmt.Errorf("timestamp is more than %s from now", v.skew)
+	}
+
+	keys, err := v.clientKeys(r.Context())
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := keys[clientID]
+	if !ok {
+		return "", fmt.Errorf("no signing key for %s", clientID)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, constants.MaxRequestBodySize))
+		if err != nil {
+			return "", fmt.Errorf("failed to read body: %w", err)
+		}
+
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if !hmac.Equal(signature, requestSignature(key, r.Method, r.URL.EscapedPath(), timestamp, nonce, body)) {
+		return "", errors.New("signature does not match")
+	}
+
+	// Only checked once the signature is known to be good, so that nonces
+	// can't be used up by anyone else. Nonces are remembered for as long as
+	// a request with them could be accepted.
+	fresh, err := v.nonces.Use(r.Context(), "NONCE#"+clientID+"#"+nonce, signedAt.Add(v.skew))
+	if err != nil {
+		return "", fmt.Errorf("failed to check nonce: %w", err)
+	}
+	if !fresh {
+		return "", errors.New("nonce has already been used")
+	}
+
+	return clientID, nil
+}
+
+// clientKeys returns the clients' signing keys, fetching them when they are
+// older than secretTTL. Concurrent callers share a single fetch.
+func (v *requestVerifier) clientKeys(ctx context.Context) (map[string][]byte, error) {
+	v.mu.RLock()
+	keys, lastFetch := v.keys, v.lastFetch
+	v.mu.RUnlock()
+
+	if keys != nil && v.now().Sub(lastFetch) < secretTTL {
+		return keys, nil
+	}
+
+	result, err, _ := v.fetches.Do("keys", func() (any, error) {
+		secret, err := v.secrets.GetSecretValue(context.WithoutCancel(ctx), v.secretARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch request signing keys: %w", err)
+		}
+
+		var encoded map[string]string
+		if err := json.Unmarshal([]byte(secret), &encoded); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request signing keys: %w", err)
+		}
+
+		keys := make(map[string][]byte, len(encoded))
+		for clientID, value := range encoded {
+			key, err := base64.StdEncoding.DecodeString(value)
+			if err != nil || len(key) < sha256.Size {
+				return nil, fmt.Errorf("request signing key for %s must be at least %d bytes, base64 encoded", clientID, sha256.Size)
+			}
+
+			keys[clientID] = key
+		}
+
+		v.mu.Lock()
+		v.keys = keys
+		v.lastFetch = v.now()
+		v.mu.Unlock()
+
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string][]byte), nil
+}
+
+type memoryNonceStore struct {
+	now    func() time.Time
+	nonces *cache.Cache[string, struct{}]
+}
+
+func newMemoryNonceStore() *memoryNonceStore {
+	return &memoryNonceStore{
+		now:    time.Now,
+		nonces: cache.New[string, struct{}](maxTrackedNonces),
+	}
+}
+
+func (s *memoryNonceStore) Use(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	_, added, err := s.nonces.Insert(key, struct{}{}, expiresAt, s.now())
+	if err != nil {
+		return false, fmt.Errorf("too many nonces in use: %w", err)
+	}
+
+	return added, nil
+}
+
+// dynamoNonceStore keeps used nonces in DynamoDB, so that a request can't be
+// replayed against another instance. Items expire using the table's TTL.
+type dynamoNonceStore struct {
+	dynamo    dynamoClient
+	tableName string
+}
+
+func (s *dynamoNonceStore) Use(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	item := itemKey(key)
+	item["ExpiresAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+
+	_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSigningKey = bytes.Repeat([]byte("k"), 32)
+
+func newTestRequestVerifier(t *testing.T, now time.Time) *requestVerifier {
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::signing-keys").
+		Return(`{"cop@example.com":"`+base64.StdEncoding.EncodeToString(testSigningKey)+`"}`, nil).
+		Maybe()
+
+	nonces := newMemoryNonceStore()
+	nonces.now = func() time.Time { return now }
+
+	return &requestVerifier{
+		secrets:   secretsClient,
+		secretARN: "aws::signing-keys",
+		skew:      5 * time.Minute,
+		nonces:    nonces,
+		now:       func() time.Time { return now },
+	}
+}
+
+func TestRequestVerifierVerify(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	body := []byte("<Set/>")
+
+	testCases := map[string]struct {
+		sign   func(r *http.Request)
+		tamper func(r *http.Request)
+		error  string
+	}{
+		"valid": {},
+		"within skew": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "cop@example.com", testSigningKey, body, now.Add(-5*time.Minute), "0123456789abcdef")
+			},
+		},
+		"timestamp too old": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "cop@example.com", testSigningKey, body, now.Add(-5*time.Minute-time.Second), "0123456789abcdef")
+			},
+			error: "timestamp is more than 5m0s from now",
+		},
+		"timestamp in the future": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "cop@example.com", testSigningKey, body, now.Add(5*time.Minute+time.Second), "0123456789abcdef")
+			},
+			error: "timestamp is more than 5m0s from now",
+		},
+		"body changed": {
+			tamper: func(r *http.Request) {
+				r.Body = io.NopCloser(strings.NewReader("<Set></Set>"))
+			},
+			error: "signature does not match",
+		},
+		"path changed": {
+			tamper: func(r *http.Request) {
+				r.URL.Path = "/api/ddc/validate"
+			},
+			error: "signature does not match",
+		},
+		"method changed": {
+			tamper: func(r *http.Request) {
+				r.Method = http.MethodPut
+			},
+			error: "signature does not match",
+		},
+		"signed with another key": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "cop@example.com", bytes.Repeat([]byte("x"), 32), body, now, "0123456789abcdef")
+			},
+			error: "signature does not match",
+		},
+		"unknown client": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "who@example.com", testSigningKey, body, now, "0123456789abcdef")
+			},
+			error: "no signing key for who@example.com",
+		},
+		"short nonce": {
+			sign: func(r *http.Request) {
+				SignRequest(r, "cop@example.com", testSigningKey, body, now, "abc")
+			},
+			error: "nonce must be between 16 and 128 characters",
+		},
+		"missing timestamp": {
+			tamper: func(r *http.Request) {
+				r.Header.Del(SignatureTimestampHeader)
+			},
+			error: "signed request is missing a header",
+		},
+		"signature not base64": {
+			tamper: func(r *http.Request) {
+				r.Header.Set(SignatureHeader, "not base64!")
+			},
+			error: "signature is not base64",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/ddc", bytes.NewReader(body))
+			if tc.sign != nil {
+				tc.sign(r)
+			} else {
+				SignRequest(r, "cop@example.com", testSigningKey, body, now, "0123456789abcdef")
+			}
+			if tc.tamper != nil {
+				tc.tamper(r)
+			}
+
+			clientID, err := newTestRequestVerifier(t, now).Verify(httptest.NewRecorder(), r)
+			if tc.error == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "cop@example.com", clientID)
+
+				// the body can still be read by the handler
+				read, _ := io.ReadAll(r.Body)
+				assert.Equal(t, body, read)
+			} else {
+				assert.ErrorContains(t, err, tc.error)
+			}
+		})
+	}
+}
+
+func TestRequestVerifierVerify_Replay(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	verifier := newTestRequestVerifier(t, now)
+
+	newRequest := func(nonce string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/ddc", strings.NewReader("<Set/>"))
+		SignRequest(r, "cop@example.com", testSigningKey, []byte("<Set/>"), now, nonce)
+		return r
+	}
+
+	_, err := verifier.Verify(httptest.NewRecorder(), newRequest("0123456789abcdef"))
+	assert.NoError(t, err)
+
+	_, err = verifier.Verify(httptest.NewRecorder(), newRequest("0123456789abcdef"))
+	assert.ErrorContains(t, err, "nonce has already been used")
+
+	_, err = verifier.Verify(httptest.NewRecorder(), newRequest("fedcba9876543210"))
+	assert.NoError(t, err)
+}
+
+func TestRequestVerifierClientKeys_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"invalid JSON":  `{`,
+		"not base64":    `{"cop@example.com":"not base64!"}`,
+		"key too short": `{"cop@example.com":"` + base64.StdEncoding.EncodeToString([]byte("short")) + `"}`,
+	}
+
+	for name, secret := range testCases {
+		t.Run(name, func(t *testing.T) {
+			secretsClient := newMockSecretsClient(t)
+			secretsClient.EXPECT().
+				GetSecretValue(mock.Anything, "aws::signing-keys").
+				Return(secret, nil)
+
+			verifier := &requestVerifier{secrets: secretsClient, secretARN: "aws::signing-keys", now: time.Now}
+
+			_, err := verifier.clientKeys(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	store := newMemoryNonceStore()
+	store.now = func() time.Time { return now }
+
+	fresh, _ := store.Use(context.Background(), "a", now.Add(time.Minute))
+	assert.True(t, fresh)
+
+	fresh, _ = store.Use(context.Background(), "a", now.Add(time.Minute))
+	assert.False(t, fresh)
+
+	now = now.Add(time.Minute)
+	fresh, _ = store.Use(context.Background(), "a", now.Add(time.Minute))
+	assert.True(t, fresh)
+}
+
+func TestMemoryNonceStoreFull(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	store := newMemoryNonceStore()
+	store.now = func() time.Time { return now }
+	store.nonces = cache.New[string, struct{}](1)
+
+	fresh, err := store.Use(context.Background(), "a", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+
+	// a is not forgotten to make room, so it still can't be replayed
+	_, err = store.Use(context.Background(), "b", now.Add(time.Minute))
+	assert.ErrorIs(t, err, cache.ErrFull)
+
+	fresh, err = store.Use(context.Background(), "a", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, fresh)
+}
+
+func TestDynamoNonceStore(t *testing.T) {
+	expiresAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	dynamo := newMockDynamoClient(t)
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.ConditionExpression == "attribute_not_exists(PK)" &&
+				input.Item["PK"].(*types.AttributeValueMemberS).Value == "NONCE#cop@example.com#used" &&
+				input.Item["ExpiresAt"].(*types.AttributeValueMemberN).Value == "1772625600"
+		})).
+		Return(nil, &types.ConditionalCheckFailedException{}).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(&dynamodb.PutItemOutput{}, nil).
+		Once()
+	dynamo.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	store := &dynamoNonceStore{dynamo: dynamo, tableName: "table"}
+
+	fresh, err := store.Use(context.Background(), "NONCE#cop@example.com#used", expiresAt)
+	assert.NoError(t, err)
+	assert.False(t, fresh)
+
+	fresh, err = store.Use(context.Background(), "NONCE#cop@example.com#new", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+
+	_, err = store.Use(context.Background(), "NONCE#cop@example.com#other", expiresAt)
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func TestAuthCheck_SignedRequest(t *testing.T) {
+	now := time.Now()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc", strings.NewReader("<Set/>"))
+	SignRequest(r, "cop@example.com", testSigningKey, []byte("<Set/>"), now, "0123456789abcdef")
+	// ignored, as the request is signed
+	r.AddCookie(&http.Cookie{Name: cookieName, Value: "a-token"})
+
+	auth := &Auth{
+		signatures: newTestRequestVerifier(t, now),
+	}
+
+	var ctxPrincipal Principal
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxPrincipal, _ = PrincipalFromContext(r.Context())
+	})
+
+	auth.CheckSigned(handler, ScopeSubmit)(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, Principal{ID: "cop@example.com", Scopes: DefaultScopes}, ctxPrincipal)
+}
+
+func TestAuthCheck_SignedRequestNotAccepted(t *testing.T) {
+	now := time.Now()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/auth/revocations", strings.NewReader("{}"))
+	SignRequest(r, "cop@example.com", testSigningKey, []byte("{}"), now, "0123456789abcdef")
+
+	auth := &Auth{
+		logger:     slog.New(slog.DiscardHandler),
+		signatures: newTestRequestVerifier(t, now),
+	}
+
+	auth.Check(http.NotFoundHandler(), ScopeAdmin)(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	assert.Equal(t, "Unauthorized: Signed requests are not accepted\n", w.Body.String())
+}
+
+func TestAuthCheck_SignedRequestRefused(t *testing.T) {
+	now := time.Now()
+
+	testCases := map[string]*requestVerifier{
+		"signing not enabled": nil,
+		"invalid signature":   newTestRequestVerifier(t, now),
+	}
+
+	for name, verifier := range testCases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/ddc", strings.NewReader("<Set/>"))
+			SignRequest(r, "cop@example.com", testSigningKey, []byte("<Set>tampered</Set>"), now, "0123456789abcdef")
+
+			auth := &Auth{
+				logger:     slog.New(slog.DiscardHandler),
+				signatures: verifier,
+			}
+
+			auth.CheckSigned(http.NotFoundHandler())(w, r)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+			assert.Equal(t, "Unauthorized: Invalid signature\n", w.Body.String())
+		})
+	}
+}
@@ -4,46 +4,79 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"golang.org/x/sync/singleflight"
 )
 
 // Refresh token after 10 minutes
 const secretTTL = 10 * time.Minute
 
+// keyringRefetchInterval limits how often tokens signed with a key that isn't
+// in the keyring can cause it to be fetched again early.
+const keyringRefetchInterval = 30 * time.Second
+
 type secretsClient interface {
 	GetSecretValue(ctx context.Context, secretName string) (string, error)
 }
 
+type revocations interface {
+	RevokeToken(ctx context.Context, id string, expiresAt time.Time) error
+	RevokePrincipal(ctx context.Context, id string, expiresAt time.Time) error
+	Revoked(ctx context.Context, id, principalID string, issuedAt time.Time) (bool, error)
+}
+
 type tokenHelper struct {
 	awsClient secretsClient
 	config    *config.Config
+	denylist  revocations
 
-	// TODO: should prevent these being updated at the same time
-	signingSecret   string
+	fetches         singleflight.Group
+	mu              sync.RWMutex
+	keyring         *keyring
 	lastSecretFetch time.Time
 }
 
-// Generate creates a new JWT token and also returns how many seconds until the
-// token expires.
-func (tg *tokenHelper) Generate() (string, time.Time, error) {
-	if err := tg.fetchSigningSecret(); err != nil {
+// Generate creates a new JWT token for a principal and also returns how many
+// seconds until the token expires.
+func (tg *tokenHelper) Generate(principal Principal) (string, time.Time, error) {
+	ring, err := tg.signingKeyring(context.Background(), false)
+	if err != nil {
 		return "", time.Time{}, err
 	}
 
 	now := time.Now()
+	key, err := ring.signingKey(now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
 	expiry := now.Add(tg.config.Auth.JWTExpiration)
 
+	// Sirius expects session-data to hold the API user, whichever client
+	// logged in, so the client is identified by sub instead.
 	jwtClaims := jwt.MapClaims{
 		"session-data": tg.config.Auth.ApiUsername,
+		"sub":          principal.ID,
+		"scope":        principal.scopeClaim(),
+		"iss":          tg.config.Auth.JWTIssuer,
+		"aud":          append([]string{tg.config.Auth.JWTIssuer}, tg.config.Auth.JWTAudiences...),
+		"jti":          uuid.NewString(),
 		"iat":          now.Unix(),
 		"exp":          expiry.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	signedToken, err := token.SignedString([]byte(tg.signingSecret))
+	token := jwt.NewWithClaims(key.method(), jwtClaims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+
+	signedToken, err := token.SignedString(key.signingMaterial())
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -51,40 +84,174 @@ func (tg *tokenHelper) Generate() (string, time.Time, error) {
 	return signedToken, expiry.Truncate(time.Second), nil
 }
 
-func (tg *tokenHelper) Validate(tokenString string) error {
-	if err := tg.fetchSigningSecret(); err != nil {
-		return err
+// Validate checks a JWT token and returns the principal it was issued to.
+func (tg *tokenHelper) Validate(ctx context.Context, tokenString string) (Principal, error) {
+	claims, principal, err := tg.parse(ctx, tokenString)
+	if err != nil {
+		return Principal{}, err
 	}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return []byte(tg.signingSecret), nil
-	}, jwt.WithIssuedAt(), jwt.WithExpirationRequired())
+	if tg.denylist != nil {
+		id, _ := claims["jti"].(string)
+
+		// A token without an issue time is treated as older than any
+		// revocation.
+		var issuedAt time.Time
+		if iat, _ := claims.GetIssuedAt(); iat != nil {
+			issuedAt = iat.Time
+		}
 
+		revoked, err := tg.denylist.Revoked(ctx, id, principal.ID, issuedAt)
+		if err != nil {
+			return Principal{}, fmt.Errorf("failed to check for revocation: %w", err)
+		}
+		if revoked {
+			return Principal{}, errors.New("token has been revoked")
+		}
+	}
+
+	return principal, nil
+}
+
+// Revoke stops a token from being used again, and returns the principal it
+// was issued to.
+func (tg *tokenHelper) Revoke(ctx context.Context, tokenString string) (Principal, error) {
+	claims, principal, err := tg.parse(ctx, tokenString)
 	if err != nil {
-		return err
+		return Principal{}, err
 	}
 
-	sessionData := token.Claims.(jwt.MapClaims)["session-data"]
-	if sessionData == nil || sessionData == "" {
-		return errors.New("session-data claim is required")
+	id, _ := claims["jti"].(string)
+	if id == "" {
+		return Principal{}, errors.New("token has no ID, so cannot be revoked")
 	}
 
-	return nil
+	expiry, _ := claims.GetExpirationTime()
+
+	return principal, tg.denylist.RevokeToken(ctx, id, expiry.Time)
+}
+
+// RevokePrincipal stops every token issued so far to a principal from being
+// used again.
+func (tg *tokenHelper) RevokePrincipal(ctx context.Context, id string) error {
+	return tg.denylist.RevokePrincipal(ctx, id, time.Now().Add(tg.config.Auth.JWTExpiration))
 }
 
-func (tg *tokenHelper) fetchSigningSecret() error {
-	shouldFetch := time.Since(tg.lastSecretFetch) >= secretTTL || tg.signingSecret == ""
-	if !shouldFetch {
-		return nil
+// KeySet returns the public keys that tokens signed with RS256 or ES256 can be
+// verified with.
+func (tg *tokenHelper) KeySet(ctx context.Context) (KeySet, error) {
+	ring, err := tg.signingKeyring(ctx, false)
+	if err != nil {
+		return KeySet{}, err
+	}
+
+	return ring.keySet(time.Now()), nil
+}
+
+// parse checks the signature and times of a JWT token, and returns its claims
+// and the principal it was issued to. A token signed with a key that isn't in
+// the keyring causes it to be fetched again, in case the key has just been
+// added.
+func (tg *tokenHelper) parse(ctx context.Context, tokenString string) (jwt.MapClaims, Principal, error) {
+	ring, err := tg.signingKeyring(ctx, false)
+	if err != nil {
+		return nil, Principal{}, err
 	}
 
-	secret, err := tg.awsClient.GetSecretValue(context.Background(), tg.config.Auth.JWTSecretARN)
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		id, _ := token.Header["kid"].(string)
+
+		key, err := ring.verificationKey(id, time.Now())
+		if errors.Is(err, errUnknownSigningKey) {
+			if ring, err = tg.signingKeyring(ctx, true); err != nil {
+				return nil, err
+			}
+
+			key, err = ring.verificationKey(id, time.Now())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Otherwise a public key could be used as an HS256 secret.
+		if token.Method.Alg() != key.method().Alg() {
+			return nil, fmt.Errorf("signing key %q is not used with %s", id, token.Method.Alg())
+		}
+
+		return key.verificationMaterial(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(tg.config.Auth.JWTIssuer),
+		jwt.WithAudience(tg.config.Auth.JWTIssuer),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+
 	if err != nil {
-		return fmt.Errorf("failed to fetch signing secret: %w", err)
+		return nil, Principal{}, err
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	if sessionData, _ := claims["session-data"].(string); sessionData == "" {
+		return nil, Principal{}, errors.New("session-data claim is required")
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, Principal{}, errors.New("sub claim is required")
+	}
+
+	scope, ok := claims["scope"].(string)
+	if !ok {
+		return nil, Principal{}, errors.New("scope claim must be a string")
 	}
 
-	tg.signingSecret = secret
-	tg.lastSecretFetch = time.Now()
+	principal := Principal{ID: sub, Scopes: strings.Fields(scope)}
+
+	return claims, principal, nil
+}
+
+// signingKeyring returns the keyring, fetching it when it is older than
+// secretTTL. When refetch is set it is fetched again if it is older than
+// keyringRefetchInterval. Concurrent callers share a single fetch.
+func (tg *tokenHelper) signingKeyring(ctx context.Context, refetch bool) (*keyring, error) {
+	maxAge := secretTTL
+	if refetch {
+		maxAge = keyringRefetchInterval
+	}
+
+	tg.mu.RLock()
+	ring, lastFetch := tg.keyring, tg.lastSecretFetch
+	tg.mu.RUnlock()
+
+	if ring != nil && time.Since(lastFetch) < maxAge {
+		return ring, nil
+	}
+
+	v, err, _ := tg.fetches.Do("keyring", func() (any, error) {
+		// A caller being cancelled shouldn't fail the others waiting on the
+		// same fetch.
+		secret, err := tg.awsClient.GetSecretValue(context.WithoutCancel(ctx), tg.config.Auth.JWTSecretARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch signing secret: %w", err)
+		}
+
+		ring, err := parseKeyring(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		tg.mu.Lock()
+		tg.keyring = ring
+		tg.lastSecretFetch = time.Now()
+		tg.mu.Unlock()
+
+		return ring, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return v.(*keyring), nil
 }
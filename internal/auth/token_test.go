@@ -1,6 +1,17 @@
 package auth
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,17 +32,18 @@ func TestGenerateToken(t *testing.T) {
 			Auth: config.Auth{
 				ApiUsername:   "user@host.example",
 				JWTSecretARN:  "aws::my-secret-arn",
+				JWTIssuer:     "opg-scanning",
 				JWTExpiration: 5 * time.Second,
 			},
 		},
 		awsClient: secretsClient,
 	}
 
-	tokenString, expiry, err := tg.Generate()
+	tokenString, expiry, err := tg.Generate(Principal{ID: "cop@example.com", Scopes: []string{ScopeSubmit, DocumentScope("COPORD")}})
 	assert.Nil(t, err)
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return []byte(tg.signingSecret), nil
+		return []byte("my-secret"), nil
 	})
 	assert.Nil(t, err)
 
@@ -39,20 +51,78 @@ func TestGenerateToken(t *testing.T) {
 	assert.Equal(t, tokenExpiry.Time, expiry)
 
 	assert.Equal(t, "user@host.example", token.Claims.(jwt.MapClaims)["session-data"])
+	assert.Equal(t, "cop@example.com", token.Claims.(jwt.MapClaims)["sub"])
+	assert.Equal(t, "submit document:COPORD", token.Claims.(jwt.MapClaims)["scope"])
+	assert.NotEmpty(t, token.Claims.(jwt.MapClaims)["jti"])
+	assert.Equal(t, "opg-scanning", token.Claims.(jwt.MapClaims)["iss"])
+	assert.Equal(t, []any{"opg-scanning"}, token.Claims.(jwt.MapClaims)["aud"])
 }
 
 func TestValidateToken(t *testing.T) {
 	testCases := map[string]struct {
-		claims jwt.MapClaims
-		ok     bool
+		claims    jwt.MapClaims
+		ok        bool
+		principal Principal
 	}{
 		"ok": {
 			claims: jwt.MapClaims{
 				"session-data": "test",
+				"sub":          "cop@example.com",
+				"scope":        "submit document:COPORD",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok:        true,
+			principal: Principal{ID: "cop@example.com", Scopes: []string{"submit", "document:COPORD"}},
+		},
+		"no-scopes": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"sub":          "cop@example.com",
+				"scope":        "",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
 				"iat":          time.Now().Add(-5 * time.Second).Unix(),
 				"exp":          time.Now().Add(5 * time.Second).Unix(),
 			},
-			ok: true,
+			ok:        true,
+			principal: Principal{ID: "cop@example.com", Scopes: []string{}},
+		},
+		"missing-sub": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"scope":        "submit document:*",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
+		},
+		"missing-scope": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"sub":          "cop@example.com",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
+		},
+		"invalid-scope": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"sub":          "cop@example.com",
+				"scope":        []string{"submit"},
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
 		},
 		"empty": {
 			claims: jwt.MapClaims{},
@@ -61,6 +131,8 @@ func TestValidateToken(t *testing.T) {
 		"expired": {
 			claims: jwt.MapClaims{
 				"session-data": "test",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
 				"iat":          time.Now().Add(-5 * time.Second).Unix(),
 				"exp":          time.Now().Add(-3 * time.Second).Unix(),
 			},
@@ -69,13 +141,45 @@ func TestValidateToken(t *testing.T) {
 		"not-yet-issued": {
 			claims: jwt.MapClaims{
 				"session-data": "test",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
 				"iat":          time.Now().Add(3 * time.Second).Unix(),
 				"exp":          time.Now().Add(5 * time.Second).Unix(),
 			},
 			ok: false,
 		},
+		"wrong-issuer": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"iss":          "somewhere-else",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
+		},
+		"wrong-audience": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"iss":          "opg-scanning",
+				"aud":          []string{"somewhere-else"},
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
+		},
+		"missing-issuer-and-audience": {
+			claims: jwt.MapClaims{
+				"session-data": "test",
+				"iat":          time.Now().Add(-5 * time.Second).Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			},
+			ok: false,
+		},
 		"missing-session-data": {
 			claims: jwt.MapClaims{
+				"iss": "opg-scanning",
+				"aud": "opg-scanning",
 				"iat": time.Now().Add(-5 * time.Second).Unix(),
 				"exp": time.Now().Add(5 * time.Second).Unix(),
 			},
@@ -94,6 +198,7 @@ func TestValidateToken(t *testing.T) {
 				config: &config.Config{
 					Auth: config.Auth{
 						JWTSecretARN: "aws::my-secret-arn",
+						JWTIssuer:    "opg-scanning",
 					},
 				},
 				awsClient: secretsClient,
@@ -102,8 +207,212 @@ func TestValidateToken(t *testing.T) {
 			token := jwt.NewWithClaims(jwt.SigningMethodHS256, tc.claims)
 			tokenString, _ := token.SignedString([]byte("my-secret"))
 
-			err := tg.Validate(tokenString)
+			principal, err := tg.Validate(context.Background(), tokenString)
+
+			if tc.ok {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.principal, principal)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateToken_Revoked(t *testing.T) {
+	issuedAt := time.Now().Add(-5 * time.Second).Truncate(time.Second)
+
+	testCases := map[string]struct {
+		revoked bool
+		err     error
+	}{
+		"not revoked": {},
+		"revoked":     {revoked: true},
+		"denylist unavailable": {
+			err: expectedError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			secretsClient := newMockSecretsClient(t)
+			secretsClient.EXPECT().
+				GetSecretValue(mock.Anything, "aws::my-secret-arn").
+				Return("my-secret", nil)
+
+			denylist := newMockRevocations(t)
+			denylist.EXPECT().
+				Revoked(mock.Anything, "token-id", "cop@example.com", issuedAt).
+				Return(tc.revoked, tc.err)
+
+			tg := tokenHelper{
+				config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+				awsClient: secretsClient,
+				denylist:  denylist,
+			}
+
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"session-data": "test",
+				"sub":          "cop@example.com",
+				"scope":        "submit",
+				"jti":          "token-id",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          issuedAt.Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			}).SignedString([]byte("my-secret"))
+
+			_, err := tg.Validate(context.Background(), tokenString)
+			if tc.revoked || tc.err != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::my-secret-arn").
+		Return("my-secret", nil)
+
+	expiry := time.Now().Add(5 * time.Second).Truncate(time.Second)
+
+	denylist := newMockRevocations(t)
+	denylist.EXPECT().
+		RevokeToken(mock.Anything, "token-id", expiry).
+		Return(nil)
+
+	tg := tokenHelper{
+		config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+		awsClient: secretsClient,
+		denylist:  denylist,
+	}
+
+	claims := jwt.MapClaims{
+		"session-data": "test",
+		"sub":          "test",
+		"scope":        "submit",
+		"iss":          "opg-scanning",
+		"aud":          "opg-scanning",
+		"iat":          time.Now().Unix(),
+		"exp":          expiry.Unix(),
+	}
+
+	withoutID, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("my-secret"))
+	_, err := tg.Revoke(context.Background(), withoutID)
+	assert.Error(t, err)
+
+	claims["jti"] = "token-id"
+	claims["sub"] = "cop@example.com"
+	withID, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("my-secret"))
+	principal, err := tg.Revoke(context.Background(), withID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cop@example.com", principal.ID)
+}
+
+func TestGenerateToken_Keyring(t *testing.T) {
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::my-secret-arn").
+		Return(`{"keys":[{"kid":"2026-02","secret":"old-secret"},{"kid":"2026-03","secret":"new-secret","activeFrom":"2026-03-04T12:00:00Z"}]}`, nil)
+
+	tg := tokenHelper{
+		config: &config.Config{
+			Auth: config.Auth{
+				ApiUsername:   "user@host.example",
+				JWTSecretARN:  "aws::my-secret-arn",
+				JWTIssuer:     "opg-scanning",
+				JWTExpiration: 5 * time.Second,
+			},
+		},
+		awsClient: secretsClient,
+	}
+
+	tokenString, _, err := tg.Generate(Principal{ID: "cop@example.com"})
+	assert.Nil(t, err)
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		return []byte("new-secret"), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "2026-03", token.Header["kid"])
+
+	_, err = tg.Validate(context.Background(), tokenString)
+	assert.Nil(t, err)
+}
+
+func TestValidateToken_Keyring(t *testing.T) {
+	keyring := `{"keys":[
+		{"secret":"legacy-secret"},
+		{"kid":"expired","secret":"expired-secret","expiresAt":"2026-01-01T00:00:00Z"},
+		{"kid":"current","secret":"current-secret"}
+	]}`
+
+	testCases := map[string]struct {
+		kid    string
+		secret string
+		method jwt.SigningMethod
+		ok     bool
+	}{
+		"current key": {
+			kid:    "current",
+			secret: "current-secret",
+			method: jwt.SigningMethodHS256,
+			ok:     true,
+		},
+		"legacy key without kid": {
+			secret: "legacy-secret",
+			method: jwt.SigningMethodHS256,
+			ok:     true,
+		},
+		"expired key": {
+			kid:    "expired",
+			secret: "expired-secret",
+			method: jwt.SigningMethodHS256,
+		},
+		"wrong secret for kid": {
+			kid:    "current",
+			secret: "legacy-secret",
+			method: jwt.SigningMethodHS256,
+		},
+		"other algorithm": {
+			kid:    "current",
+			secret: "current-secret",
+			method: jwt.SigningMethodHS512,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			secretsClient := newMockSecretsClient(t)
+			secretsClient.EXPECT().
+				GetSecretValue(mock.Anything, "aws::my-secret-arn").
+				Return(keyring, nil).
+				Once()
 
+			tg := tokenHelper{
+				config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+				awsClient: secretsClient,
+			}
+
+			token := jwt.NewWithClaims(tc.method, jwt.MapClaims{
+				"session-data": "test",
+				"sub":          "test",
+				"scope":        "submit",
+				"iss":          "opg-scanning",
+				"aud":          "opg-scanning",
+				"iat":          time.Now().Unix(),
+				"exp":          time.Now().Add(5 * time.Second).Unix(),
+			})
+			if tc.kid != "" {
+				token.Header["kid"] = tc.kid
+			}
+			tokenString, _ := token.SignedString([]byte(tc.secret))
+
+			_, err := tg.Validate(context.Background(), tokenString)
 			if tc.ok {
 				assert.Nil(t, err)
 			} else {
@@ -112,3 +421,186 @@ func TestValidateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateToken_UnknownKeyRefetchesKeyring(t *testing.T) {
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::my-secret-arn").
+		Return(`{"keys":[{"kid":"old","secret":"old-secret"},{"kid":"new","secret":"new-secret"}]}`, nil).
+		Once()
+
+	tg := tokenHelper{
+		config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+		awsClient: secretsClient,
+		// fetched before the new key was added
+		keyring:         &keyring{keys: []signingKey{{ID: "old", Secret: "old-secret"}}},
+		lastSecretFetch: time.Now().Add(-keyringRefetchInterval),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"session-data": "test",
+		"sub":          "test",
+		"scope":        "submit",
+		"iss":          "opg-scanning",
+		"aud":          "opg-scanning",
+		"iat":          time.Now().Unix(),
+		"exp":          time.Now().Add(5 * time.Second).Unix(),
+	})
+	token.Header["kid"] = "new"
+	tokenString, _ := token.SignedString([]byte("new-secret"))
+
+	_, err := tg.Validate(context.Background(), tokenString)
+	assert.Nil(t, err)
+
+	// the keyring was fetched too recently to fetch again
+	token.Header["kid"] = "other"
+	tokenString, _ = token.SignedString([]byte("new-secret"))
+
+	_, err = tg.Validate(context.Background(), tokenString)
+	assert.NotNil(t, err)
+}
+
+func TestSigningKeyring_SharesFetches(t *testing.T) {
+	release := make(chan struct{})
+
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::my-secret-arn").
+		RunAndReturn(func(context.Context, string) (string, error) {
+			<-release
+			return "my-secret", nil
+		}).
+		Once()
+
+	tg := tokenHelper{
+		config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+		awsClient: secretsClient,
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			ring, err := tg.signingKeyring(context.Background(), false)
+			assert.Nil(t, err)
+			assert.Equal(t, []signingKey{{Secret: "my-secret"}}, ring.keys)
+		})
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestToken_AsymmetricKeys(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	testCases := map[string]signingKey{
+		"RS256": {ID: "rsa", Algorithm: "RS256", PrivateKey: rsaPEM(rsaKey)},
+		"ES256": {ID: "ec", Algorithm: "ES256", PrivateKey: ecPEM(ecKey)},
+	}
+
+	for name, key := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keyring, _ := json.Marshal(map[string][]signingKey{"keys": {key}})
+
+			secretsClient := newMockSecretsClient(t)
+			secretsClient.EXPECT().
+				GetSecretValue(mock.Anything, "aws::my-secret-arn").
+				Return(string(keyring), nil).
+				Once()
+
+			tg := tokenHelper{
+				config: &config.Config{
+					Auth: config.Auth{
+						ApiUsername:   "user@host.example",
+						JWTSecretARN:  "aws::my-secret-arn",
+						JWTIssuer:     "opg-scanning",
+						JWTAudiences:  []string{"opg-other-service"},
+						JWTExpiration: 5 * time.Second,
+					},
+				},
+				awsClient: secretsClient,
+			}
+
+			tokenString, _, err := tg.Generate(Principal{ID: "cop@example.com"})
+			assert.Nil(t, err)
+
+			principal, err := tg.Validate(context.Background(), tokenString)
+			assert.Nil(t, err)
+			assert.Equal(t, "cop@example.com", principal.ID)
+
+			// the token can be checked by another service, with only the
+			// published public key
+			keySet, err := tg.KeySet(context.Background())
+			assert.Nil(t, err)
+			assert.Len(t, keySet.Keys, 1)
+
+			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+				return publicKeyFromJWK(t, keySet.Keys[0]), nil
+			}, jwt.WithValidMethods([]string{name}), jwt.WithIssuer("opg-scanning"), jwt.WithAudience("opg-other-service"))
+			assert.Nil(t, err)
+			assert.Equal(t, key.ID, token.Header["kid"])
+		})
+	}
+}
+
+func TestValidateToken_RejectsPublicKeyAsSecret(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keyring, _ := json.Marshal(map[string][]signingKey{"keys": {{ID: "rsa", Algorithm: "RS256", PrivateKey: rsaPEM(rsaKey)}}})
+
+	secretsClient := newMockSecretsClient(t)
+	secretsClient.EXPECT().
+		GetSecretValue(mock.Anything, "aws::my-secret-arn").
+		Return(string(keyring), nil)
+
+	tg := tokenHelper{
+		config:    &config.Config{Auth: config.Auth{JWTSecretARN: "aws::my-secret-arn", JWTIssuer: "opg-scanning"}},
+		awsClient: secretsClient,
+	}
+
+	publicKey, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"session-data": "test",
+		"sub":          "test",
+		"scope":        "submit",
+		"iss":          "opg-scanning",
+		"aud":          "opg-scanning",
+		"iat":          time.Now().Unix(),
+		"exp":          time.Now().Add(5 * time.Second).Unix(),
+	})
+	token.Header["kid"] = "rsa"
+	tokenString, _ := token.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+
+	_, err := tg.Validate(context.Background(), tokenString)
+	assert.NotNil(t, err)
+}
+
+func rsaPEM(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func ecPEM(key *ecdsa.PrivateKey) string {
+	der, _ := x509.MarshalECPrivateKey(key)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func publicKeyFromJWK(t *testing.T, jwk JSONWebKey) any {
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		assert.Nil(t, err)
+		return b
+	}
+
+	switch jwk.KeyType {
+	case "RSA":
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(decode(jwk.N)), E: int(new(big.Int).SetBytes(decode(jwk.E)).Int64())}
+	case "EC":
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, decode(jwk.X)...), decode(jwk.Y)...))
+		assert.Nil(t, err)
+		return key
+	}
+
+	t.Fatalf("unexpected key type %s", jwk.KeyType)
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -15,16 +14,19 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/google/uuid"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
 	"github.com/ministryofjustice/opg-scanning/internal/sirius"
 )
 
 type AwsClientInterface interface {
 	GetSecretValue(ctx context.Context, secretName string) (string, error)
-	FetchCredentials(ctx context.Context) (map[string]string, error)
+	GetSsmValue(ctx context.Context, secretName string) (string, error)
 	PersistFormData(ctx context.Context, body []byte, docType string) (string, error)
+	PersistFormExtraction(ctx context.Context, body []byte, formFileName string) (string, error)
 	PersistSetData(ctx context.Context, body []byte) (string, error)
-	QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string) (string, error)
+	QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName, extractionFileName string) (string, error)
 }
 
 type AwsClient struct {
@@ -121,6 +123,7 @@ func (a *AwsClient) PersistFormData(ctx context.Context, body []byte, docType st
 		ServerSideEncryption: types.ServerSideEncryptionAwsKms,
 		SSEKMSKeyId:          &a.config.Aws.JobsQueueBucketKmsKey,
 		IfNoneMatch:          aws.String("*"),
+		Metadata:             objectMetadata(ctx),
 	}
 
 	// Upload the file to S3
@@ -135,22 +138,29 @@ func (a *AwsClient) PersistFormData(ctx context.Context, body []byte, docType st
 	return fileName, nil
 }
 
-func (a *AwsClient) PersistSetData(ctx context.Context, body []byte) (string, error) {
+// PersistFormExtraction stores the JSON extraction of a form next to the form
+// XML that was persisted by PersistFormData.
+func (a *AwsClient) PersistFormExtraction(ctx context.Context, body []byte, formFileName string) (string, error) {
 	bucketName := a.config.Aws.JobsQueueBucket
 	if bucketName == "" {
 		return "", fmt.Errorf("JOBSQUEUE_BUCKET is not set")
 	}
 
-	uuid := uuid.Must(uuid.NewV7()).String()
-	fileName := fmt.Sprintf("SET_%s.xml", uuid)
+	if !json.Valid(body) {
+		return "", fmt.Errorf("invalid JSON extraction for %s", formFileName)
+	}
+
+	fileName := extraction.FileName(formFileName)
 
 	input := &s3.PutObjectInput{
 		Bucket:               &bucketName,
 		Key:                  &fileName,
 		Body:                 bytes.NewReader(body),
+		ContentType:          aws.String("application/json"),
 		ServerSideEncryption: types.ServerSideEncryptionAwsKms,
 		SSEKMSKeyId:          &a.config.Aws.JobsQueueBucketKmsKey,
 		IfNoneMatch:          aws.String("*"),
+		Metadata:             objectMetadata(ctx),
 	}
 
 	_, err := a.S3.PutObject(ctx, input)
@@ -164,33 +174,55 @@ func (a *AwsClient) PersistSetData(ctx context.Context, body []byte) (string, er
 	return fileName, nil
 }
 
-func (a *AwsClient) FetchCredentials(ctx context.Context) (map[string]string, error) {
-	secretValue, err := a.GetSsmValue(ctx, a.config.Auth.CredentialsARN)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve secret from AWS: %w", err)
+func (a *AwsClient) PersistSetData(ctx context.Context, body []byte) (string, error) {
+	bucketName := a.config.Aws.JobsQueueBucket
+	if bucketName == "" {
+		return "", fmt.Errorf("JOBSQUEUE_BUCKET is not set")
 	}
 
-	secretValue = strings.TrimPrefix(secretValue, "kms:alias/aws/ssm:")
+	uuid := uuid.Must(uuid.NewV7()).String()
+	fileName := fmt.Sprintf("SET_%s.xml", uuid)
+
+	input := &s3.PutObjectInput{
+		Bucket:               &bucketName,
+		Key:                  &fileName,
+		Body:                 bytes.NewReader(body),
+		ServerSideEncryption: types.ServerSideEncryptionAwsKms,
+		SSEKMSKeyId:          &a.config.Aws.JobsQueueBucketKmsKey,
+		IfNoneMatch:          aws.String("*"),
+		Metadata:             objectMetadata(ctx),
+	}
 
-	var credentials map[string]string
-	if err := json.Unmarshal([]byte(secretValue), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	_, err := a.S3.PutObject(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to upload object to S3: %w (endpoint: %s, bucket: %s, key: %s)",
+			err, a.config.Aws.Endpoint, bucketName, fileName,
+		)
 	}
 
-	if len(credentials) == 0 {
-		return nil, fmt.Errorf("no credentials found in secret")
+	return fileName, nil
+}
+
+// objectMetadata records who submitted the set an object came from.
+func objectMetadata(ctx context.Context) map[string]string {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
 	}
 
-	return credentials, nil
+	return map[string]string{"submitted-by": principal.ID}
 }
 
-func (a *AwsClient) QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string) (string, error) {
+func (a *AwsClient) QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName, extractionFileName string) (string, error) {
 	message := struct {
-		UID      string `json:"uid"`
-		Filename string `json:"filename"`
+		UID                string `json:"uid"`
+		Filename           string `json:"filename"`
+		ExtractionFilename string `json:"extractionFilename,omitempty"`
 	}{
-		UID:      scannedCaseResponse.UID,
-		Filename: fileName,
+		UID:                scannedCaseResponse.UID,
+		Filename:           fileName,
+		ExtractionFilename: extractionFileName,
 	}
 	messageJson, err := json.Marshal(message)
 	if err != nil {
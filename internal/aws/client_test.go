@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
 	"github.com/ministryofjustice/opg-scanning/internal/sirius"
 	"github.com/stretchr/testify/assert"
@@ -22,7 +24,10 @@ var scannedCaseResponse = &sirius.ScannedCaseResponse{
 	UID: "700000001219",
 }
 
-const fileName = "SET_DDC_20250106093401__LPA_677ba389ab101.xml"
+const (
+	fileName           = "SET_DDC_20250106093401__LPA_677ba389ab101.xml"
+	extractionFileName = "SET_DDC_20250106093401__LPA_677ba389ab101.json"
+)
 
 func TestPersistFormData_LocalStack(t *testing.T) {
 	ctx := context.Background()
@@ -70,11 +75,39 @@ func TestPersistFormData_LocalStack(t *testing.T) {
 	assert.True(t, found, fmt.Sprintf("Expected object key '%s' not found in the bucket", fileName))
 }
 
-func TestPersistSetData(t *testing.T) {
+func TestPersistFormExtraction_LocalStack(t *testing.T) {
 	ctx := context.Background()
 
 	appConfig, _ := config.Read()
 
+	if appConfig.App.Environment != "local" {
+		t.Skip("Skipping test as it requires localstack")
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(ctx,
+		awsConfig.WithRegion(appConfig.Aws.Region),
+	)
+	assert.NoError(t, err, "Failed to load AWS configuration")
+
+	awsClient, err := NewAwsClient(ctx, cfg, appConfig)
+	assert.NoError(t, err, "Failed to load AWS client")
+
+	formFileName, err := awsClient.PersistFormData(ctx, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?><test>test</test>"), "TestDoc")
+	assert.NoError(t, err)
+
+	extractionFileName, err := awsClient.PersistFormExtraction(ctx, []byte(`{"schemaVersion":"1"}`), formFileName)
+	assert.NoError(t, err, "PersistFormExtraction should not return an error")
+	assert.Equal(t, strings.TrimSuffix(formFileName, ".xml")+".json", extractionFileName)
+
+	_, err = awsClient.PersistFormExtraction(ctx, []byte("not json"), formFileName)
+	assert.Error(t, err, "PersistFormExtraction should return an error for invalid JSON")
+}
+
+func TestPersistSetData(t *testing.T) {
+	ctx := auth.ContextWithPrincipal(context.Background(), auth.Principal{ID: "supplier@example.com"})
+
+	appConfig, _ := config.Read()
+
 	cfg, err := awsConfig.LoadDefaultConfig(ctx,
 		awsConfig.WithRegion(appConfig.Aws.Region),
 	)
@@ -102,6 +135,14 @@ func TestPersistSetData(t *testing.T) {
 		}
 	}
 	assert.True(t, found, fmt.Sprintf("Expected object key '%s' not found in the bucket", fileName))
+
+	headObjectOutput, err := awsClient.S3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(appConfig.Aws.JobsQueueBucket),
+		Key:    aws.String(fileName),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "supplier@example.com", headObjectOutput.Metadata["submitted-by"])
+	}
 }
 
 func TestAwsQueue_QueueSetForProcessing(t *testing.T) {
@@ -123,7 +164,7 @@ func TestAwsQueue_QueueSetForProcessing(t *testing.T) {
 	assert.NotNil(t, awsClient.SQS)
 
 	// Call the method to simulate queuing the message
-	messageID, err := awsClient.QueueSetForProcessing(context.Background(), scannedCaseResponse, fileName)
+	messageID, err := awsClient.QueueSetForProcessing(context.Background(), scannedCaseResponse, fileName, extractionFileName)
 	assert.NoError(t, err, "Failed to queue message")
 	assert.NotNil(t, messageID)
 
@@ -131,6 +172,7 @@ func TestAwsQueue_QueueSetForProcessing(t *testing.T) {
 
 	assert.Equal(t, scannedCaseResponse.UID, receivedMessage["uid"])
 	assert.Equal(t, fileName, receivedMessage["filename"])
+	assert.Equal(t, extractionFileName, receivedMessage["extractionFilename"])
 }
 
 func validateMessageInQueue(t *testing.T, ctx context.Context, sqsClient *sqs.Client, queueUrl string) map[string]any {
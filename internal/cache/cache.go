@@ -0,0 +1,179 @@
+// Package cache provides a bounded in-memory cache whose entries expire, for
+// the state each instance keeps when a shared store isn't configured.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrFull is returned when an entry can't be stored because every entry held
+// is still unexpired.
+var ErrFull = errors.New("cache is full")
+
+// Cache holds up to a fixed number of entries, each with its own expiry, so
+// memory stays bounded however many keys are seen. Set makes room by evicting
+// the least recently used entry, which suits lookups that can be made again
+// elsewhere. Put and Insert only make room by dropping expired entries, and
+// fail with ErrFull otherwise, which suits state that exists nowhere else. The
+// time is passed to each method so callers can control the clock.
+type Cache[K comparable, V any] struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	recency *list.List // most recently used at the front
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// New creates a cache holding at most capacity entries.
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	return &Cache[K, V]{
+		capacity: max(capacity, 1),
+		entries:  map[K]*list.Element{},
+		recency:  list.New(),
+	}
+}
+
+// Get returns the value stored for key, if it hasn't expired.
+func (c *Cache[K, V]) Get(key K, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key, now)
+}
+
+// Set stores a value for key until expiresAt, replacing any already stored.
+// When the cache is full, the least recently used entry is evicted.
+func (c *Cache[K, V]) Set(key K, value V, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.entries) >= c.capacity && c.entries[key] == nil {
+		c.remove(c.recency.Back())
+	}
+
+	c.set(key, value, expiresAt)
+}
+
+// Put stores a value for key until expiresAt, replacing any already stored. It
+// fails with ErrFull rather than evict an unexpired entry.
+func (c *Cache[K, V]) Put(key K, value V, expiresAt, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[key] == nil && !c.makeRoom(now) {
+		return ErrFull
+	}
+
+	c.set(key, value, expiresAt)
+	return nil
+}
+
+// Insert stores a value for key until expiresAt, unless an unexpired value is
+// already stored, in which case that value is returned with false. It fails
+// with ErrFull rather than evict an unexpired entry.
+func (c *Cache[K, V]) Insert(key K, value V, expiresAt, now time.Time) (V, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.get(key, now); ok {
+		return existing, false, nil
+	}
+
+	if !c.makeRoom(now) {
+		var zero V
+		return zero, false, ErrFull
+	}
+
+	c.set(key, value, expiresAt)
+	return value, true, nil
+}
+
+// Full reports whether a new key would be refused by Put or Insert.
+func (c *Cache[K, V]) Full(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return !c.makeRoom(now)
+}
+
+// Delete removes any value stored for key.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of entries held, including any that have expired but
+// not yet been removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+func (c *Cache[K, V]) get(key K, now time.Time) (V, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	e := element.Value.(*entry[K, V])
+	if !now.Before(e.expiresAt) {
+		c.remove(element)
+
+		var zero V
+		return zero, false
+	}
+
+	c.recency.MoveToFront(element)
+	return e.value, true
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiresAt time.Time) {
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.recency.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.recency.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+}
+
+// makeRoom drops expired entries when the cache is full, and reports whether
+// there is then room for another.
+func (c *Cache[K, V]) makeRoom(now time.Time) bool {
+	if len(c.entries) < c.capacity {
+		return true
+	}
+
+	for element := c.recency.Back(); element != nil; {
+		previous := element.Prev()
+		if !now.Before(element.Value.(*entry[K, V]).expiresAt) {
+			c.remove(element)
+		}
+		element = previous
+	}
+
+	return len(c.entries) < c.capacity
+}
+
+func (c *Cache[K, V]) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheGetAndSet(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](10)
+
+	_, ok := c.Get("a", now)
+	assert.False(t, ok)
+
+	c.Set("a", 1, now.Add(time.Minute))
+
+	value, ok := c.Get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	_, ok = c.Get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCacheDelete(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](10)
+
+	c.Set("a", 1, now.Add(time.Minute))
+	c.Delete("a")
+	c.Delete("b")
+
+	_, ok := c.Get("a", now)
+	assert.False(t, ok)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](2)
+
+	c.Set("a", 1, now.Add(time.Hour))
+	c.Set("b", 2, now.Add(time.Hour))
+
+	// Using a makes b the least recently used
+	c.Get("a", now)
+	c.Set("c", 3, now.Add(time.Hour))
+
+	assert.Equal(t, 2, c.Len())
+
+	_, ok := c.Get("b", now)
+	assert.False(t, ok)
+
+	value, ok := c.Get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, ok = c.Get("c", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+}
+
+func TestCachePut(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](2)
+
+	assert.NoError(t, c.Put("a", 1, now.Add(time.Minute), now))
+	assert.NoError(t, c.Put("b", 2, now.Add(time.Hour), now))
+
+	// Unexpired entries are never evicted
+	assert.ErrorIs(t, c.Put("c", 3, now.Add(time.Hour), now), ErrFull)
+	assert.True(t, c.Full(now))
+
+	// but can still be replaced
+	assert.NoError(t, c.Put("a", 4, now.Add(time.Minute), now))
+
+	value, ok := c.Get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+
+	// Expired entries make room
+	assert.NoError(t, c.Put("c", 3, now.Add(time.Hour), now.Add(time.Minute)))
+
+	_, ok = c.Get("b", now)
+	assert.True(t, ok)
+}
+
+func TestCacheInsert(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](1)
+
+	value, added, err := c.Insert("a", 1, now.Add(time.Minute), now)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, 1, value)
+
+	value, added, err = c.Insert("a", 2, now.Add(time.Minute), now)
+	assert.NoError(t, err)
+	assert.False(t, added)
+	assert.Equal(t, 1, value)
+
+	_, added, err = c.Insert("b", 2, now.Add(time.Minute), now)
+	assert.ErrorIs(t, err, ErrFull)
+	assert.False(t, added)
+
+	// Once a has expired, b can be added
+	value, added, err = c.Insert("b", 2, now.Add(2*time.Minute), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, 2, value)
+}
@@ -2,17 +2,23 @@ package config
 
 import (
 	"cmp"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type (
 	Config struct {
-		App  app
-		Aws  aws
-		Auth Auth
-		HTTP http
+		App   app
+		Aws   aws
+		Auth  Auth
+		HTTP  http
+		Audit audit
 	}
 
 	app struct {
@@ -21,6 +27,10 @@ type (
 		SiriusCaseStubURL  string
 		SiriusAttachDocURL string
 		XSDPath            string
+		ValidationPolicy   validation.Policy
+		// DuplicatePolicy is the severity of repeated content by document
+		// type.
+		DuplicatePolicy validation.DuplicatePolicy
 	}
 
 	aws struct {
@@ -33,15 +43,82 @@ type (
 	}
 
 	Auth struct {
-		ApiUsername    string
-		JWTSecretARN   string
-		CredentialsARN string
-		JWTExpiration  time.Duration
+		ApiUsername  string
+		JWTSecretARN string
+		// CredentialsStore is where login credentials are read from: "ssm"
+		// or "secretsmanager" using CredentialsARN, "file" using
+		// CredentialsFile, or "env" using Credentials.
+		CredentialsStore string
+		CredentialsARN   string
+		CredentialsFile  string
+		Credentials      string
+		// CredentialsTTL is how long credentials are used before they are
+		// refreshed, and CredentialsMaxStaleness how long they can still be
+		// used for when refreshing fails.
+		CredentialsTTL          time.Duration
+		CredentialsMaxStaleness time.Duration
+		JWTExpiration           time.Duration
+		// JWTIssuer is the iss claim on tokens, and the audience they must be
+		// issued for to be accepted here.
+		JWTIssuer string
+		// JWTAudiences are the other services tokens are issued for.
+		JWTAudiences []string
+		// SiriusJWTSecretARN is the keyring the tokens sent to Sirius are
+		// signed with, which is kept apart from the one clients' tokens are
+		// signed with. The tokens are issued for SiriusJWTAudience, and last
+		// for SiriusJWTExpiration.
+		SiriusJWTSecretARN  string
+		SiriusJWTAudience   string
+		SiriusJWTExpiration time.Duration
+		// ClientScopes lists the scopes granted to each client, by the email
+		// it logs in with. Clients not listed are given auth.DefaultScopes.
+		ClientScopes map[string][]string
+		// ClientCertificates maps identities in client certificates, such as
+		// "DNS:scanner.example.com" or "CN=scanner", to the principal they
+		// authenticate as.
+		ClientCertificates map[string]string
+		// RequestSigningKeysARN is the secret holding the keys clients sign
+		// requests with. Signed requests are refused when it isn't set.
+		RequestSigningKeysARN string
+		RequestSigningSkew    time.Duration
+		// NonceStore is where the nonces of signed requests are remembered:
+		// "memory" for each instance separately, or "dynamodb" to share them,
+		// which is the default outside local.
+		NonceStore string
+		// LoginAttemptsStore is where failed logins are counted: "dynamodb" to
+		// share them, or "memory" for each instance separately, which is only
+		// allowed, and the default, locally.
+		LoginAttemptsStore         string
+		MaxLoginAttemptsPerIP      int
+		MaxLoginAttemptsPerAccount int
+		LoginLockout               time.Duration
+	}
+
+	audit struct {
+		// Store is where the audit log is kept: "file" locally, where it is
+		// the default, or "dynamodb", the default elsewhere, or "s3".
+		Store        string
+		File         string
+		Bucket       string
+		BucketKmsKey string
 	}
 
 	http struct {
 		Port    string
 		Timeout time.Duration
+		// TLSCertFile and TLSKeyFile are set to serve HTTPS instead of HTTP.
+		TLSCertFile string
+		TLSKeyFile  string
+		// TLSClientCAFile is set to ask clients for certificates signed by
+		// its CAs, which they can use in place of a token.
+		TLSClientCAFile      string
+		TLSRequireClientCert bool
+		// IdempotencyStore is where responses to requests with an
+		// Idempotency-Key are kept: "memory" for each instance separately, or
+		// "dynamodb" to share them, which is the default outside local. They
+		// are kept for IdempotencyTTL.
+		IdempotencyStore string
+		IdempotencyTTL   time.Duration
 	}
 )
 
@@ -52,8 +129,11 @@ func Environment() string {
 // Loads configuration from environment variables.
 func Read() (*Config, error) {
 	var (
-		httpTimeout, jwtExpiration time.Duration
-		err                        error
+		httpTimeout, jwtExpiration, loginLockout, signingSkew   time.Duration
+		credentialsTTL, credentialsMaxStaleness, idempotencyTTL time.Duration
+		siriusJWTExpiration                                     time.Duration
+		maxAttemptsPerIP, maxAttemptsPerAccount                 int
+		err                                                     error
 	)
 
 	if val := os.Getenv("HTTP_TIMEOUT"); val != "" {
@@ -62,6 +142,12 @@ func Read() (*Config, error) {
 			return nil, fmt.Errorf("failed to load environment variables into config 'HTTP_TIMEOUT': %w", err)
 		}
 	}
+	if val := os.Getenv("IDEMPOTENCY_TTL"); val != "" {
+		idempotencyTTL, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'IDEMPOTENCY_TTL': %w", err)
+		}
+	}
 	if val := os.Getenv("JWT_EXPIRATION"); val != "" {
 		jwtExpiration, err = time.ParseDuration(val)
 		if err != nil {
@@ -69,6 +155,140 @@ func Read() (*Config, error) {
 		}
 	}
 
+	if val := os.Getenv("CREDENTIALS_TTL"); val != "" {
+		credentialsTTL, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'CREDENTIALS_TTL': %w", err)
+		}
+	}
+	if val := os.Getenv("CREDENTIALS_MAX_STALENESS"); val != "" {
+		credentialsMaxStaleness, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'CREDENTIALS_MAX_STALENESS': %w", err)
+		}
+	}
+
+	if val := os.Getenv("SIRIUS_JWT_EXPIRATION"); val != "" {
+		siriusJWTExpiration, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'SIRIUS_JWT_EXPIRATION': %w", err)
+		}
+	}
+	if val := os.Getenv("LOGIN_LOCKOUT"); val != "" {
+		loginLockout, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'LOGIN_LOCKOUT': %w", err)
+		}
+	}
+	if val := os.Getenv("REQUEST_SIGNING_SKEW"); val != "" {
+		signingSkew, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'REQUEST_SIGNING_SKEW': %w", err)
+		}
+	}
+	if val := os.Getenv("MAX_LOGIN_ATTEMPTS_PER_IP"); val != "" {
+		maxAttemptsPerIP, err = strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'MAX_LOGIN_ATTEMPTS_PER_IP': %w", err)
+		}
+	}
+	if val := os.Getenv("MAX_LOGIN_ATTEMPTS_PER_ACCOUNT"); val != "" {
+		maxAttemptsPerAccount, err = strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'MAX_LOGIN_ATTEMPTS_PER_ACCOUNT': %w", err)
+		}
+	}
+
+	var clientCertificates map[string]string
+	if val := os.Getenv("CLIENT_CERTIFICATES"); val != "" {
+		if err := json.Unmarshal([]byte(val), &clientCertificates); err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'CLIENT_CERTIFICATES': %w", err)
+		}
+	}
+
+	var requireClientCert bool
+	if val := os.Getenv("TLS_REQUIRE_CLIENT_CERT"); val != "" {
+		requireClientCert, err = strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'TLS_REQUIRE_CLIENT_CERT': %w", err)
+		}
+	}
+
+	var jwtAudiences []string
+	if val := os.Getenv("JWT_AUDIENCES"); val != "" {
+		for audience := range strings.SplitSeq(val, ",") {
+			if audience = strings.TrimSpace(audience); audience != "" {
+				jwtAudiences = append(jwtAudiences, audience)
+			}
+		}
+	}
+
+	credentialsStore := cmp.Or(os.Getenv("CREDENTIALS_STORE"), "ssm")
+	switch credentialsStore {
+	case "ssm", "secretsmanager", "file", "env":
+	default:
+		return nil, fmt.Errorf("failed to load environment variables into config 'CREDENTIALS_STORE': unknown store %q", credentialsStore)
+	}
+
+	// Only a local instance runs alone, so everywhere else state that
+	// instances must agree on is shared by default.
+	local := Environment() == "local"
+	sharedStore, defaultAuditStore := "dynamodb", "dynamodb"
+	if local {
+		sharedStore, defaultAuditStore = "memory", "file"
+	}
+
+	auditStore := cmp.Or(os.Getenv("AUDIT_STORE"), defaultAuditStore)
+	switch auditStore {
+	case "dynamodb", "s3":
+	case "file":
+		if !local {
+			return nil, fmt.Errorf("failed to load environment variables into config 'AUDIT_STORE': %q can only be written by one instance, so is only used locally", auditStore)
+		}
+	default:
+		return nil, fmt.Errorf("failed to load environment variables into config 'AUDIT_STORE': unknown store %q", auditStore)
+	}
+
+	// Counting failed logins on each instance separately would multiply the
+	// limits by the number of instances.
+	loginAttemptsStore := cmp.Or(os.Getenv("LOGIN_ATTEMPTS_STORE"), sharedStore)
+	switch loginAttemptsStore {
+	case "dynamodb":
+	case "memory":
+		if !local {
+			return nil, fmt.Errorf("failed to load environment variables into config 'LOGIN_ATTEMPTS_STORE': %q can only be used locally", loginAttemptsStore)
+		}
+	default:
+		return nil, fmt.Errorf("failed to load environment variables into config 'LOGIN_ATTEMPTS_STORE': unknown store %q", loginAttemptsStore)
+	}
+
+	nonceStore := cmp.Or(os.Getenv("NONCE_STORE"), sharedStore)
+	if nonceStore != "memory" && nonceStore != "dynamodb" {
+		return nil, fmt.Errorf("failed to load environment variables into config 'NONCE_STORE': unknown store %q", nonceStore)
+	}
+
+	idempotencyStore := cmp.Or(os.Getenv("IDEMPOTENCY_STORE"), sharedStore)
+	if idempotencyStore != "memory" && idempotencyStore != "dynamodb" {
+		return nil, fmt.Errorf("failed to load environment variables into config 'IDEMPOTENCY_STORE': unknown store %q", idempotencyStore)
+	}
+
+	var clientScopes map[string][]string
+	if val := os.Getenv("CLIENT_SCOPES"); val != "" {
+		if err := json.Unmarshal([]byte(val), &clientScopes); err != nil {
+			return nil, fmt.Errorf("failed to load environment variables into config 'CLIENT_SCOPES': %w", err)
+		}
+	}
+
+	validationPolicy, err := validation.ParsePolicy(os.Getenv("VALIDATION_POLICY"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment variables into config 'VALIDATION_POLICY': %w", err)
+	}
+
+	duplicatePolicy, err := validation.ParseDuplicatePolicy(os.Getenv("DUPLICATE_POLICY"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment variables into config 'DUPLICATE_POLICY': %w", err)
+	}
+
 	return &Config{
 		App: app{
 			Environment:        Environment(),
@@ -76,6 +296,8 @@ func Read() (*Config, error) {
 			SiriusCaseStubURL:  cmp.Or(os.Getenv("SIRIUS_CASE_STUB_URL"), "api/public/v1/scanned-cases"),
 			SiriusAttachDocURL: cmp.Or(os.Getenv("SIRIUS_ATTACH_DOC_URL"), "api/public/v1/scanned-documents"),
 			XSDPath:            cmp.Or(os.Getenv("XSD_PATH"), "xsd"),
+			ValidationPolicy:   validationPolicy,
+			DuplicatePolicy:    duplicatePolicy,
 		},
 		Aws: aws{
 			JobsQueueURL:          cmp.Or(os.Getenv("JOBQUEUE_SQS_QUEUE_URL"), "000000000000/ddc.fifo"),
@@ -86,14 +308,50 @@ func Read() (*Config, error) {
 			DocumentsTable:        os.Getenv("DOCUMENTS_TABLE"),
 		},
 		Auth: Auth{
-			ApiUsername:    cmp.Or(os.Getenv("API_USERNAME"), "opg_document_and_d@publicguardian.gsi.gov.uk"),
-			JWTSecretARN:   cmp.Or(os.Getenv("JWT_SECRET_ARN"), "local/jwt-key"),
-			CredentialsARN: cmp.Or(os.Getenv("CREDENTIALS_ARN"), "/local/local-credentials"),
-			JWTExpiration:  cmp.Or(jwtExpiration, time.Hour),
+			ApiUsername:   cmp.Or(os.Getenv("API_USERNAME"), "opg_document_and_d@publicguardian.gsi.gov.uk"),
+			JWTSecretARN:  cmp.Or(os.Getenv("JWT_SECRET_ARN"), "local/jwt-key"),
+			JWTExpiration: cmp.Or(jwtExpiration, time.Hour),
+			JWTIssuer:     cmp.Or(os.Getenv("JWT_ISSUER"), "opg-scanning"),
+			JWTAudiences:  jwtAudiences,
+			ClientScopes:  clientScopes,
+
+			SiriusJWTSecretARN:  cmp.Or(os.Getenv("SIRIUS_JWT_SECRET_ARN"), "local/sirius-jwt-key"),
+			SiriusJWTAudience:   cmp.Or(os.Getenv("SIRIUS_JWT_AUDIENCE"), "sirius"),
+			SiriusJWTExpiration: cmp.Or(siriusJWTExpiration, 5*time.Minute),
+
+			CredentialsStore:        credentialsStore,
+			CredentialsARN:          cmp.Or(os.Getenv("CREDENTIALS_ARN"), "/local/local-credentials"),
+			CredentialsFile:         os.Getenv("CREDENTIALS_FILE"),
+			Credentials:             os.Getenv("LOGIN_CREDENTIALS"),
+			CredentialsTTL:          cmp.Or(credentialsTTL, 5*time.Minute),
+			CredentialsMaxStaleness: cmp.Or(credentialsMaxStaleness, time.Hour),
+
+			ClientCertificates:         clientCertificates,
+			RequestSigningKeysARN:      os.Getenv("REQUEST_SIGNING_KEYS_ARN"),
+			RequestSigningSkew:         cmp.Or(signingSkew, 5*time.Minute),
+			NonceStore:                 nonceStore,
+			LoginAttemptsStore:         loginAttemptsStore,
+			MaxLoginAttemptsPerIP:      cmp.Or(maxAttemptsPerIP, 20),
+			MaxLoginAttemptsPerAccount: cmp.Or(maxAttemptsPerAccount, 5),
+			LoginLockout:               cmp.Or(loginLockout, time.Minute),
+		},
+		Audit: audit{
+			Store:        auditStore,
+			File:         cmp.Or(os.Getenv("AUDIT_FILE"), "audit.jsonl"),
+			Bucket:       os.Getenv("AUDIT_S3_BUCKET_NAME"),
+			BucketKmsKey: cmp.Or(os.Getenv("AUDIT_S3_ENCRYPTION_KEY"), "alias/aws/s3"),
 		},
 		HTTP: http{
 			Port:    cmp.Or(os.Getenv("HTTP_PORT"), "8081"),
 			Timeout: cmp.Or(httpTimeout, 10*time.Second),
+
+			TLSCertFile:          os.Getenv("TLS_CERT_FILE"),
+			TLSKeyFile:           os.Getenv("TLS_KEY_FILE"),
+			TLSClientCAFile:      os.Getenv("TLS_CLIENT_CA_FILE"),
+			TLSRequireClientCert: requireClientCert,
+
+			IdempotencyStore: idempotencyStore,
+			IdempotencyTTL:   cmp.Or(idempotencyTTL, 24*time.Hour),
 		},
 	}, nil
 }
@@ -3,5 +3,5 @@ package constants
 type ContextKey string
 
 const (
-	TokenContextKey ContextKey = "auth-user"
+	PrincipalContextKey ContextKey = "auth-principal"
 )
@@ -0,0 +1,5 @@
+package constants
+
+// MaxRequestBodySize is the largest request body that is read. Sets carry their
+// documents' scans as base64, so this allows for several large PDFs.
+const MaxRequestBodySize = 64 << 20
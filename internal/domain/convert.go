@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/parser/date"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+)
+
+// TitleFromSalutation converts the tick boxes used on Banktec forms into a
+// title. When more than one box is ticked the first is used.
+func TitleFromSalutation(s lp1f_types.Salutation) string {
+	switch {
+	case s.Mr:
+		return "Mr"
+	case s.Mrs:
+		return "Mrs"
+	case s.Ms:
+		return "Ms"
+	case s.Miss:
+		return "Miss"
+	case s.Other:
+		return strings.TrimSpace(s.OtherName)
+	default:
+		return ""
+	}
+}
+
+// DecisionFromAppointment converts appointment tick boxes into a decision
+// type, returning an empty Decision when no box is ticked.
+func DecisionFromAppointment(a lp1f_types.Appointment) Decision {
+	switch {
+	case a.Alone:
+		return DecisionSingleAttorney
+	case a.Jointly:
+		return DecisionJointly
+	case a.JointlyAndSeverally:
+		return DecisionJointlyAndSeverally
+	default:
+		return ""
+	}
+}
+
+func decisionFromSection3(s lp1f_types.Section3) Decision {
+	switch {
+	case s.AppointedOneAttorney:
+		return DecisionSingleAttorney
+	case s.Jointly:
+		return DecisionJointly
+	case s.JointlyAndSeverally:
+		return DecisionJointlyAndSeverally
+	case s.JointlyForSome:
+		return DecisionJointlyForSomeSeverallyForOthers
+	default:
+		return ""
+	}
+}
+
+func addressFrom(a lp1f_types.Address) Address {
+	return Address{
+		Line1:    strings.TrimSpace(a.Address1),
+		Line2:    strings.TrimSpace(a.Address2),
+		Line3:    strings.TrimSpace(a.Address3),
+		Postcode: strings.TrimSpace(a.Postcode),
+	}
+}
+
+// normaliseDate returns dates in ISO 8601 format where they can be read,
+// otherwise the value is kept as written so that it is not lost.
+func normaliseDate(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+
+	if d, err := date.Parse(s); err == nil {
+		return d.Format("2006-01-02")
+	}
+
+	return s
+}
+
+func personFromName(n lp1f_types.PersonName) Person {
+	return Person{
+		Title:      TitleFromSalutation(n.Salutation),
+		FirstNames: strings.TrimSpace(n.Forename),
+		LastName:   strings.TrimSpace(n.LastName),
+		OtherNames: strings.TrimSpace(n.OtherForenames),
+	}
+}
+
+func attorneyFrom(a lp1f_types.Attorney) Attorney {
+	attorney := Attorney{
+		Person: Person{
+			Title:       strings.TrimSpace(a.Title),
+			FirstNames:  strings.TrimSpace(a.FirstName),
+			LastName:    strings.TrimSpace(a.LastName),
+			DateOfBirth: normaliseDate(a.DOB),
+			Address:     addressFrom(a.Address),
+			Email:       strings.TrimSpace(a.EmailAddress),
+		},
+	}
+
+	if a.TrustCorporation != nil {
+		attorney.TrustCorporation = *a.TrustCorporation
+	}
+
+	return attorney
+}
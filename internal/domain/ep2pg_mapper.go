@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/types/ep2pg_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+)
+
+// FromEP2PG maps an application to register an enduring power of attorney.
+// Relatives given notice of the application are treated as people to notify.
+func FromEP2PG(doc *ep2pg_types.EP2PGDocument) *LPA {
+	part1 := doc.Page1.Part1
+	donor := personFromName(part1.Name)
+	donor.DateOfBirth = normaliseDate(part1.DOB)
+	donor.Address = addressFrom(part1.Address)
+
+	lpa := &LPA{
+		DocumentType: constants.DocumentTypeEP2PG,
+		Type:         LPATypeEnduring,
+		Donor:        &Donor{Person: donor},
+	}
+
+	part4 := doc.Page3.Part4
+	page4Part4 := doc.Page4.Part4
+	thirdAttorney := ep2pg_types.Attorney{
+		Name: lp1f_types.PersonName{
+			Salutation:     part4.Salutation,
+			LastName:       part4.LastName,
+			Forename:       part4.Forename,
+			OtherForenames: page4Part4.OtherForenames,
+		},
+		CompanyName: page4Part4.CompanyName,
+		Address:     page4Part4.Address,
+		DOB:         page4Part4.DOB,
+		Telephone:   page4Part4.Telephone,
+		Email:       page4Part4.Email,
+		Occupation:  page4Part4.Occupation,
+	}
+
+	for _, a := range []ep2pg_types.Attorney{doc.Page2.Part2.Attorney, doc.Page3.Part3.Attorney, thirdAttorney} {
+		if attorney := attorneyFromEP2PG(a); !attorney.IsZero() {
+			lpa.Attorneys = append(lpa.Attorneys, attorney)
+			lpa.Applicants = append(lpa.Applicants, Applicant{Person: attorney.Person, Role: ApplicantRoleAttorney})
+		}
+	}
+
+	relatives := append([]ep2pg_types.Relative{}, doc.Page5.Part7.Relative...)
+	relatives = append(relatives, doc.Page6.Part8.Relative...)
+	for _, r := range relatives {
+		personToNotify := PersonToNotify{Person: personFromFullName(r.FullName)}
+		personToNotify.Address = Address{
+			Line1: strings.TrimSpace(r.Address1),
+			Line2: strings.TrimSpace(r.Address2),
+			Line3: strings.TrimSpace(r.Address3),
+		}
+		if !personToNotify.IsZero() {
+			lpa.PeopleToNotify = append(lpa.PeopleToNotify, personToNotify)
+		}
+	}
+
+	part11 := doc.Page7.Part11
+	correspondent := &Correspondent{
+		Person:      personFromName(part11.Name),
+		CompanyName: strings.TrimSpace(part11.CompanyName),
+	}
+	correspondent.Address = addressFrom(part11.Address)
+	correspondent.Email = strings.TrimSpace(part11.Email)
+	correspondent.Phone = strings.TrimSpace(part11.Telephone)
+	if !correspondent.IsZero() || correspondent.CompanyName != "" {
+		correspondent.Role = CorrespondentRoleOther
+		lpa.Correspondent = correspondent
+	}
+
+	return lpa
+}
+
+func attorneyFromEP2PG(a ep2pg_types.Attorney) Attorney {
+	attorney := Attorney{
+		Person:      personFromName(a.Name),
+		CompanyName: strings.TrimSpace(a.CompanyName),
+		Occupation:  strings.TrimSpace(a.Occupation),
+	}
+	attorney.DateOfBirth = normaliseDate(a.DOB)
+	attorney.Address = addressFrom(a.Address)
+	attorney.Email = strings.TrimSpace(a.Email)
+	attorney.Phone = strings.TrimSpace(a.Telephone)
+
+	return attorney
+}
+
+// personFromFullName splits a name written on a single line, taking the last
+// word as the surname.
+func personFromFullName(fullName string) Person {
+	fields := strings.Fields(fullName)
+	if len(fields) == 0 {
+		return Person{}
+	}
+
+	return Person{
+		FirstNames: strings.Join(fields[:len(fields)-1], " "),
+		LastName:   fields[len(fields)-1],
+	}
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+)
+
+// FromLP1F maps a property and financial affairs instrument.
+func FromLP1F(doc *lp1f_types.LP1FDocument) *LPA {
+	lpa := fromLP1(doc)
+	lpa.DocumentType = constants.DocumentTypeLP1F
+	lpa.Type = LPATypePropertyAndAffairs
+
+	return lpa
+}
+
+// FromLP1H maps a health and welfare instrument. The LP1H shares its layout
+// with the LP1F apart from the life-sustaining treatment and donor signature
+// pages, neither of which name anybody.
+func FromLP1H(doc *lp1h_types.LP1HDocument) *LPA {
+	lpa := fromLP1(&doc.LP1FDocument)
+	lpa.DocumentType = constants.DocumentTypeLP1H
+	lpa.Type = LPATypePersonalWelfare
+
+	return lpa
+}
+
+func fromLP1(doc *lp1f_types.LP1FDocument) *LPA {
+	section1 := doc.Page1.Section1
+
+	lpa := &LPA{
+		Donor: &Donor{Person: Person{
+			Title:       strings.TrimSpace(section1.Title),
+			FirstNames:  strings.TrimSpace(section1.FirstName),
+			LastName:    strings.TrimSpace(section1.LastName),
+			OtherNames:  strings.TrimSpace(section1.OtherNames),
+			DateOfBirth: normaliseDate(section1.DOB),
+			Address:     addressFrom(section1.Address),
+			Email:       strings.TrimSpace(section1.EmailAddress),
+		}},
+		HowAttorneysMakeDecisions: decisionFromSection3(doc.Page4.Section3),
+	}
+
+	attorneys := []lp1f_types.Attorney{doc.Page2.Section2.Attorney1, doc.Page2.Section2.Attorney2}
+	attorneys = append(attorneys, doc.Page3.Section2.Attorney...)
+	for _, page := range doc.ContinuationPage1 {
+		attorneys = append(attorneys, page.ContinuationSheet1.Attorney...)
+	}
+
+	for _, a := range attorneys {
+		if attorney := attorneyFrom(a); !attorney.IsZero() {
+			lpa.Attorneys = append(lpa.Attorneys, attorney)
+		}
+	}
+
+	for _, a := range []lp1f_types.Attorney{doc.Page5.Section4.Attorney1, doc.Page5.Section4.Attorney2} {
+		if attorney := attorneyFrom(a); !attorney.IsZero() {
+			lpa.ReplacementAttorneys = append(lpa.ReplacementAttorneys, ReplacementAttorney{Attorney: attorney})
+		}
+	}
+
+	section10 := doc.Page11.Section10
+	certificateProvider := CertificateProvider{Person: Person{
+		Title:      strings.TrimSpace(section10.Title),
+		FirstNames: strings.TrimSpace(section10.FirstName),
+		LastName:   strings.TrimSpace(section10.LastName),
+		Address:    addressFrom(section10.Address),
+	}}
+	if !certificateProvider.IsZero() {
+		lpa.CertificateProviders = append(lpa.CertificateProviders, certificateProvider)
+	}
+
+	for _, p := range doc.Page7.Section6.PeopleToNotify {
+		personToNotify := PersonToNotify{Person: Person{
+			Title:      strings.TrimSpace(p.Title),
+			FirstNames: strings.TrimSpace(p.FirstName),
+			LastName:   strings.TrimSpace(p.LastName),
+			Address:    addressFrom(p.Address),
+		}}
+		if !personToNotify.IsZero() {
+			lpa.PeopleToNotify = append(lpa.PeopleToNotify, personToNotify)
+		}
+	}
+
+	section12 := doc.Page17.Section12
+	if section12.DonorApply {
+		lpa.Applicants = append(lpa.Applicants, Applicant{Person: lpa.Donor.Person, Role: ApplicantRoleDonor})
+	}
+	if section12.AttorneyApply {
+		for _, a := range section12.Attorney {
+			applicant := Applicant{Person: attorneyFrom(a).Person, Role: ApplicantRoleAttorney}
+			if !applicant.IsZero() {
+				lpa.Applicants = append(lpa.Applicants, applicant)
+			}
+		}
+	}
+
+	lpa.Correspondent = correspondentFromSection13(doc.Page18.Section13, lpa.Donor)
+
+	return lpa
+}
+
+func correspondentFromSection13(s lp1f_types.Section13, donor *Donor) *Correspondent {
+	correspondent := &Correspondent{
+		Person: Person{
+			Title:      strings.TrimSpace(s.Title),
+			FirstNames: strings.TrimSpace(s.FirstName),
+			LastName:   strings.TrimSpace(s.LastName),
+			Address:    addressFrom(s.Address),
+			Email:      strings.TrimSpace(s.EmailAddress),
+			Phone:      strings.TrimSpace(s.PhoneNumber),
+		},
+		CompanyName:  strings.TrimSpace(s.CompanyName),
+		ContactWelsh: s.Welsh,
+	}
+
+	switch {
+	case s.TheDonor:
+		correspondent.Role = CorrespondentRoleDonor
+	case s.AnAttorney:
+		correspondent.Role = CorrespondentRoleAttorney
+	case s.Other:
+		correspondent.Role = CorrespondentRoleOther
+	}
+
+	return completeCorrespondent(correspondent, donor)
+}
+
+// completeCorrespondent fills in the donor's details when the donor is the
+// correspondent, as the forms do not ask for them twice.
+func completeCorrespondent(c *Correspondent, donor *Donor) *Correspondent {
+	if c.Role == CorrespondentRoleDonor && c.IsZero() && donor != nil {
+		c.Title = donor.Title
+		c.FirstNames = donor.FirstNames
+		c.LastName = donor.LastName
+		if c.Address.IsZero() {
+			c.Address = donor.Address
+		}
+	}
+
+	if c.Role == "" && c.IsZero() && c.CompanyName == "" {
+		return nil
+	}
+
+	return c
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+)
+
+// FromLP2 maps an application to register. The LP2 names the donor and
+// applicants but leaves their addresses to the continuation address pages, so
+// those are matched back to applicants by name.
+func FromLP2(doc *lp2_types.LP2Document) *LPA {
+	section1 := doc.Page1.Section1
+
+	lpa := &LPA{
+		DocumentType: constants.DocumentTypeLP2,
+		Donor: &Donor{Person: Person{
+			Title:      strings.TrimSpace(section1.Title),
+			FirstNames: strings.TrimSpace(section1.FirstName),
+			LastName:   strings.TrimSpace(section1.LastName),
+		}},
+	}
+
+	switch {
+	case section1.PropertyFinancialAffairs:
+		lpa.Type = LPATypePropertyAndAffairs
+	case section1.HealthWelfare:
+		lpa.Type = LPATypePersonalWelfare
+	}
+
+	addresses := map[string]lp2_types.AddressEntry{}
+	for _, page := range doc.Page6 {
+		for _, entry := range page.Section6.Addresses {
+			addresses[nameKey(entry.FirstName, entry.LastName)] = entry
+		}
+	}
+
+	section2 := doc.Page2.Section2
+	if section2.DonorRegisteration {
+		lpa.Applicants = append(lpa.Applicants, Applicant{Person: lpa.Donor.Person, Role: ApplicantRoleDonor})
+	}
+	if section2.AttorneyRegisteration {
+		for _, a := range section2.Attorney {
+			applicant := Applicant{
+				Person: Person{
+					Title:       strings.TrimSpace(a.Title),
+					FirstNames:  strings.TrimSpace(a.FirstName),
+					LastName:    strings.TrimSpace(a.LastName),
+					DateOfBirth: normaliseDate(a.DOB),
+				},
+				Role: ApplicantRoleAttorney,
+			}
+			if applicant.IsZero() {
+				continue
+			}
+
+			if entry, ok := addresses[nameKey(a.FirstName, a.LastName)]; ok {
+				applicant.Address = addressFrom(entry.Address)
+				applicant.Email = strings.TrimSpace(entry.EmailAddress)
+			}
+
+			lpa.Applicants = append(lpa.Applicants, applicant)
+		}
+	}
+
+	section3 := doc.Page3.Section3
+	correspondent := &Correspondent{
+		Person: Person{
+			Title:      strings.TrimSpace(section3.Title),
+			FirstNames: strings.TrimSpace(section3.FirstName),
+			LastName:   strings.TrimSpace(section3.LastName),
+			Address:    addressFrom(section3.Address),
+			Email:      strings.TrimSpace(section3.EmailAddress),
+			Phone:      strings.TrimSpace(section3.PhoneNumber),
+		},
+		CompanyName:  strings.TrimSpace(section3.Company),
+		ContactWelsh: section3.Welsh,
+	}
+
+	switch {
+	case section3.TheDonor:
+		correspondent.Role = CorrespondentRoleDonor
+	case section3.AnAttorney:
+		correspondent.Role = CorrespondentRoleAttorney
+	case section3.Other:
+		correspondent.Role = CorrespondentRoleOther
+	}
+
+	lpa.Correspondent = completeCorrespondent(correspondent, lpa.Donor)
+
+	return lpa
+}
+
+func nameKey(firstName, lastName string) string {
+	return strings.ToLower(strings.TrimSpace(firstName) + "|" + strings.TrimSpace(lastName))
+}
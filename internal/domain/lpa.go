@@ -0,0 +1,130 @@
+package domain
+
+// LPAType is the kind of power of attorney an instrument or application
+// relates to.
+type LPAType string
+
+const (
+	LPATypePropertyAndAffairs LPAType = "property-and-affairs"
+	LPATypePersonalWelfare    LPAType = "personal-welfare"
+	LPATypeEnduring           LPAType = "enduring"
+)
+
+// Decision describes how attorneys have been appointed to make decisions.
+type Decision string
+
+const (
+	DecisionSingleAttorney                   Decision = "single-attorney"
+	DecisionJointly                          Decision = "jointly"
+	DecisionJointlyAndSeverally              Decision = "jointly-and-severally"
+	DecisionJointlyForSomeSeverallyForOthers Decision = "jointly-for-some-severally-for-others"
+)
+
+// ApplicantRole records who applied to register a power of attorney.
+type ApplicantRole string
+
+const (
+	ApplicantRoleDonor    ApplicantRole = "donor"
+	ApplicantRoleAttorney ApplicantRole = "attorney"
+)
+
+// CorrespondentRole records who should receive correspondence about a power of
+// attorney.
+type CorrespondentRole string
+
+const (
+	CorrespondentRoleDonor    CorrespondentRole = "donor"
+	CorrespondentRoleAttorney CorrespondentRole = "attorney"
+	CorrespondentRoleOther    CorrespondentRole = "other"
+)
+
+// LPA is a form-independent view of the people named on a power of attorney,
+// mapped from whichever scanned document described them.
+type LPA struct {
+	DocumentType              string                `json:"documentType"`
+	Type                      LPAType               `json:"type,omitempty"`
+	Donor                     *Donor                `json:"donor,omitempty"`
+	Attorneys                 []Attorney            `json:"attorneys"`
+	ReplacementAttorneys      []ReplacementAttorney `json:"replacementAttorneys"`
+	HowAttorneysMakeDecisions Decision              `json:"howAttorneysMakeDecisions,omitempty"`
+	CertificateProviders      []CertificateProvider `json:"certificateProviders"`
+	PeopleToNotify            []PersonToNotify      `json:"peopleToNotify"`
+	Applicants                []Applicant           `json:"applicants"`
+	Correspondent             *Correspondent        `json:"correspondent,omitempty"`
+	ContinuedInformation      []string              `json:"continuedInformation,omitempty"`
+}
+
+type Address struct {
+	Line1    string `json:"line1,omitempty"`
+	Line2    string `json:"line2,omitempty"`
+	Line3    string `json:"line3,omitempty"`
+	Postcode string `json:"postcode,omitempty"`
+}
+
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+type Person struct {
+	Title       string  `json:"title,omitempty"`
+	FirstNames  string  `json:"firstNames,omitempty"`
+	LastName    string  `json:"lastName,omitempty"`
+	OtherNames  string  `json:"otherNames,omitempty"`
+	DateOfBirth string  `json:"dateOfBirth,omitempty"`
+	Address     Address `json:"address"`
+	Email       string  `json:"email,omitempty"`
+	Phone       string  `json:"phone,omitempty"`
+}
+
+// IsZero reports whether no name was given for the person, which is how the
+// forms represent an unused block.
+func (p Person) IsZero() bool {
+	return p.FirstNames == "" && p.LastName == ""
+}
+
+// FullName joins the person's first and last names.
+func (p Person) FullName() string {
+	switch {
+	case p.FirstNames == "":
+		return p.LastName
+	case p.LastName == "":
+		return p.FirstNames
+	default:
+		return p.FirstNames + " " + p.LastName
+	}
+}
+
+type Donor struct {
+	Person
+}
+
+type Attorney struct {
+	Person
+	CompanyName      string `json:"companyName,omitempty"`
+	Occupation       string `json:"occupation,omitempty"`
+	TrustCorporation bool   `json:"trustCorporation,omitempty"`
+}
+
+type ReplacementAttorney struct {
+	Attorney
+}
+
+type CertificateProvider struct {
+	Person
+}
+
+type PersonToNotify struct {
+	Person
+}
+
+type Applicant struct {
+	Person
+	Role ApplicantRole `json:"role"`
+}
+
+type Correspondent struct {
+	Person
+	Role         CorrespondentRole `json:"role,omitempty"`
+	CompanyName  string            `json:"companyName,omitempty"`
+	ContactWelsh bool              `json:"contactWelsh,omitempty"`
+}
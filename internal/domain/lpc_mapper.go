@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+)
+
+// Continuation is the content of a set of LPC continuation sheets. The sheets
+// only make sense alongside the instrument they continue, which they identify
+// by the donor's name.
+type Continuation struct {
+	DonorNames            []string              `json:"donorNames"`
+	Attorneys             []Attorney            `json:"attorneys"`
+	ReplacementAttorneys  []ReplacementAttorney `json:"replacementAttorneys"`
+	PeopleToNotify        []PersonToNotify      `json:"peopleToNotify"`
+	AdditionalInformation []string              `json:"additionalInformation"`
+}
+
+// Additional information that may be continued on a sheet.
+const (
+	AdditionalInformationNotes                = "notes"
+	AdditionalInformationInstructions         = "instructions"
+	AdditionalInformationPreferences          = "preferences"
+	AdditionalInformationReplacementAttorneys = "replacement-attorneys"
+	AdditionalInformationJointly              = "jointly"
+)
+
+// FromLPC maps a set of continuation sheets.
+func FromLPC(doc *lpc_types.LPCDocument) *Continuation {
+	continuation := &Continuation{}
+
+	for _, page := range doc.Page1 {
+		sheet := page.ContinuationSheet1
+		continuation.addDonorName(sheet.Donor.FullName)
+
+		for _, a := range sheet.Attorneys {
+			person := Person{
+				Title:       strings.TrimSpace(a.Title),
+				FirstNames:  strings.TrimSpace(a.FirstName),
+				LastName:    strings.TrimSpace(a.LastName),
+				DateOfBirth: normaliseDate(a.DOB),
+				Address:     addressFrom(a.Address),
+				Email:       strings.TrimSpace(a.Email),
+			}
+			if person.IsZero() {
+				continue
+			}
+
+			switch {
+			case a.Attorney:
+				continuation.Attorneys = append(continuation.Attorneys, Attorney{Person: person})
+			case a.ReplacementAttorney:
+				continuation.ReplacementAttorneys = append(continuation.ReplacementAttorneys, ReplacementAttorney{Attorney: Attorney{Person: person}})
+			case a.PersonToNotify:
+				continuation.PeopleToNotify = append(continuation.PeopleToNotify, PersonToNotify{Person: person})
+			}
+		}
+	}
+
+	for _, page := range doc.Page2 {
+		sheet := page.ContinuationSheet2
+		continuation.addDonorName(sheet.Donor.FullName)
+
+		info := sheet.AdditionalInformation
+		for _, continued := range []struct {
+			name string
+			set  bool
+		}{
+			{AdditionalInformationNotes, info.Notes},
+			{AdditionalInformationInstructions, info.Instructions},
+			{AdditionalInformationPreferences, info.Preferences},
+			{AdditionalInformationReplacementAttorneys, info.ReplacementAttorneys},
+			{AdditionalInformationJointly, info.Jointly},
+		} {
+			if continued.set {
+				continuation.addAdditionalInformation(continued.name)
+			}
+		}
+	}
+
+	for _, page := range doc.Page3 {
+		continuation.addDonorName(page.ContinuationSheet3.Donor.FullName)
+	}
+
+	return continuation
+}
+
+// IsEmpty reports whether the sheets named nobody and continued nothing.
+func (c *Continuation) IsEmpty() bool {
+	return len(c.Attorneys) == 0 &&
+		len(c.ReplacementAttorneys) == 0 &&
+		len(c.PeopleToNotify) == 0 &&
+		len(c.AdditionalInformation) == 0
+}
+
+func (c *Continuation) addDonorName(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+
+	for _, existing := range c.DonorNames {
+		if strings.EqualFold(existing, name) {
+			return
+		}
+	}
+
+	c.DonorNames = append(c.DonorNames, name)
+}
+
+func (c *Continuation) addAdditionalInformation(name string) {
+	if slices.Contains(c.AdditionalInformation, name) {
+		return
+	}
+
+	c.AdditionalInformation = append(c.AdditionalInformation, name)
+}
+
+// Continues reports whether the sheets belong to the given power of attorney.
+// Sheets that do not name the donor are assumed to continue it.
+func (c *Continuation) Continues(lpa *LPA) bool {
+	if len(c.DonorNames) == 0 || lpa.Donor == nil {
+		return true
+	}
+
+	donorName := strings.Join(strings.Fields(lpa.Donor.FullName()), " ")
+	for _, name := range c.DonorNames {
+		if strings.EqualFold(strings.Join(strings.Fields(name), " "), donorName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Merge adds the people and additional information from continuation sheets
+// to the power of attorney they continue.
+func (l *LPA) Merge(c *Continuation) {
+	l.Attorneys = append(l.Attorneys, c.Attorneys...)
+	l.ReplacementAttorneys = append(l.ReplacementAttorneys, c.ReplacementAttorneys...)
+	l.PeopleToNotify = append(l.PeopleToNotify, c.PeopleToNotify...)
+
+	for _, name := range c.AdditionalInformation {
+		if !slices.Contains(l.ContinuedInformation, name) {
+			l.ContinuedInformation = append(l.ContinuedInformation, name)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"github.com/ministryofjustice/opg-scanning/internal/types/ep2pg_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+)
+
+// FromDocument maps a document returned by the factory into an LPA. It returns
+// false for document types that do not describe a power of attorney, including
+// continuation sheets which are mapped with FromLPC.
+func FromDocument(doc any) (*LPA, bool) {
+	switch d := doc.(type) {
+	case *lp1f_types.LP1FDocument:
+		return FromLP1F(d), true
+	case *lp1h_types.LP1HDocument:
+		return FromLP1H(d), true
+	case *lp2_types.LP2Document:
+		return FromLP2(d), true
+	case *ep2pg_types.EP2PGDocument:
+		return FromEP2PG(d), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/parser/ep2pg_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1f_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1h_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lp2_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lpc_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/types/ep2pg_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, fileName string) []byte {
+	data, err := os.ReadFile("../../testdata/xml/" + fileName)
+	require.NoError(t, err)
+	return data
+}
+
+func TestFromLP1F(t *testing.T) {
+	parsed, err := lp1f_parser.Parse(readFixture(t, "LP1F-valid.xml"))
+	require.NoError(t, err)
+
+	lpa, ok := FromDocument(parsed)
+	require.True(t, ok)
+
+	assert.Equal(t, "LP1F", lpa.DocumentType)
+	assert.Equal(t, LPATypePropertyAndAffairs, lpa.Type)
+	assert.Equal(t, "Mr", lpa.Donor.Title)
+	assert.Equal(t, "Charles Anderson", lpa.Donor.FullName())
+	assert.Equal(t, "Chuck", lpa.Donor.OtherNames)
+	assert.Equal(t, "1965-11-12", lpa.Donor.DateOfBirth)
+
+	names := []string{}
+	for _, a := range lpa.Attorneys {
+		names = append(names, a.FullName())
+	}
+	assert.Equal(t, []string{"Emily Garcia", "Daniel Martinez", "Matthew Clark", "Olivia Walker"}, names)
+	assert.True(t, lpa.Attorneys[0].TrustCorporation)
+	assert.Equal(t, DecisionJointlyForSomeSeverallyForOthers, lpa.HowAttorneysMakeDecisions)
+
+	require.Len(t, lpa.ReplacementAttorneys, 1)
+	assert.Equal(t, "Imaginary & Associates", lpa.ReplacementAttorneys[0].LastName)
+
+	require.Len(t, lpa.PeopleToNotify, 1)
+	assert.Equal(t, "Steven Bennett", lpa.PeopleToNotify[0].FullName())
+
+	require.Len(t, lpa.CertificateProviders, 1)
+	assert.Equal(t, "Isaac Roberts", lpa.CertificateProviders[0].FullName())
+
+	require.Len(t, lpa.Applicants, 1)
+	assert.Equal(t, ApplicantRoleDonor, lpa.Applicants[0].Role)
+	assert.Equal(t, "Charles Anderson", lpa.Applicants[0].FullName())
+
+	require.NotNil(t, lpa.Correspondent)
+	assert.Equal(t, CorrespondentRoleDonor, lpa.Correspondent.Role)
+	assert.Equal(t, "Charles Anderson", lpa.Correspondent.FullName())
+	assert.True(t, lpa.Correspondent.ContactWelsh)
+}
+
+func TestFromLP1H(t *testing.T) {
+	parsed, err := lp1h_parser.Parse(readFixture(t, "LP1H-valid.xml"))
+	require.NoError(t, err)
+
+	lpa := FromLP1H(parsed.(*lp1h_types.LP1HDocument))
+
+	assert.Equal(t, "LP1H", lpa.DocumentType)
+	assert.Equal(t, LPATypePersonalWelfare, lpa.Type)
+	assert.Equal(t, "John Doe", lpa.Donor.FullName())
+}
+
+func TestFromLP2(t *testing.T) {
+	parsed, err := lp2_parser.Parse(readFixture(t, "LP2-valid.xml"))
+	require.NoError(t, err)
+
+	doc := parsed.(*lp2_types.LP2Document)
+	lpa := FromLP2(doc)
+
+	assert.Equal(t, "LP2", lpa.DocumentType)
+	assert.Equal(t, LPATypePersonalWelfare, lpa.Type)
+	assert.Equal(t, "Prof", lpa.Donor.Title)
+	assert.Equal(t, "Flavio Miller", lpa.Donor.FullName())
+	require.Len(t, lpa.Applicants, 1)
+	assert.Equal(t, ApplicantRoleDonor, lpa.Applicants[0].Role)
+	require.NotNil(t, lpa.Correspondent)
+	assert.Equal(t, "Flavio Miller", lpa.Correspondent.FullName())
+
+	doc.Page2.Section2.AttorneyRegisteration = true
+	lpa = FromLP2(doc)
+
+	require.Len(t, lpa.Applicants, 3)
+	assert.Equal(t, "Julius Jaden Heidenreich", lpa.Applicants[2].FullName())
+	assert.Equal(t, "1996-02-18", lpa.Applicants[2].DateOfBirth)
+	assert.Equal(t, "IK5 7QT", lpa.Applicants[2].Address.Postcode)
+	assert.Equal(t, "jjheidenreich@business.example", lpa.Applicants[2].Email)
+}
+
+func TestFromLPC(t *testing.T) {
+	parsed, err := lpc_parser.Parse(readFixture(t, "LPC-valid.xml"))
+	require.NoError(t, err)
+
+	continuation := FromLPC(parsed.(*lpc_types.LPCDocument))
+
+	require.Len(t, continuation.Attorneys, 1)
+	assert.Equal(t, "Jack Jones", continuation.Attorneys[0].FullName())
+	require.Len(t, continuation.ReplacementAttorneys, 1)
+	assert.Equal(t, "Mary Smith", continuation.ReplacementAttorneys[0].FullName())
+	assert.Equal(t, []string{"Jane Donor", "John Donor"}, continuation.DonorNames)
+	assert.Equal(t, []string{AdditionalInformationNotes, AdditionalInformationInstructions, AdditionalInformationPreferences}, continuation.AdditionalInformation)
+	assert.False(t, continuation.IsEmpty())
+}
+
+func TestFromEP2PG(t *testing.T) {
+	parsed, err := ep2pg_parser.Parse(readFixture(t, "EP2PG-valid.xml"))
+	require.NoError(t, err)
+
+	lpa := FromEP2PG(parsed.(*ep2pg_types.EP2PGDocument))
+
+	assert.Equal(t, "EP2PG", lpa.DocumentType)
+	assert.Equal(t, LPATypeEnduring, lpa.Type)
+	assert.Equal(t, "Mrs", lpa.Donor.Title)
+	assert.Equal(t, "Dorothy Branning", lpa.Donor.FullName())
+	require.Len(t, lpa.Attorneys, 1)
+	assert.Equal(t, "Nick Cotton", lpa.Attorneys[0].FullName())
+	require.Len(t, lpa.Applicants, 1)
+	assert.Equal(t, ApplicantRoleAttorney, lpa.Applicants[0].Role)
+
+	require.Len(t, lpa.PeopleToNotify, 2)
+	assert.Equal(t, "Bradley", lpa.PeopleToNotify[0].FirstNames)
+	assert.Equal(t, "Branning", lpa.PeopleToNotify[0].LastName)
+
+	require.NotNil(t, lpa.Correspondent)
+	assert.Equal(t, CorrespondentRoleOther, lpa.Correspondent.Role)
+	assert.Equal(t, "The best company", lpa.Correspondent.CompanyName)
+}
+
+func TestFromDocumentUnsupported(t *testing.T) {
+	_, ok := FromDocument(&lpc_types.LPCDocument{})
+	assert.False(t, ok)
+}
+
+func TestTitleFromSalutation(t *testing.T) {
+	assert.Equal(t, "Ms", TitleFromSalutation(lp1f_types.Salutation{Ms: true}))
+	assert.Equal(t, "Dame", TitleFromSalutation(lp1f_types.Salutation{Other: true, OtherName: " Dame "}))
+	assert.Equal(t, "", TitleFromSalutation(lp1f_types.Salutation{}))
+}
+
+func TestDecisionFromAppointment(t *testing.T) {
+	assert.Equal(t, DecisionSingleAttorney, DecisionFromAppointment(lp1f_types.Appointment{Alone: true}))
+	assert.Equal(t, DecisionJointly, DecisionFromAppointment(lp1f_types.Appointment{Jointly: true}))
+	assert.Equal(t, DecisionJointlyAndSeverally, DecisionFromAppointment(lp1f_types.Appointment{JointlyAndSeverally: true}))
+	assert.Equal(t, Decision(""), DecisionFromAppointment(lp1f_types.Appointment{}))
+}
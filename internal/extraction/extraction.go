@@ -0,0 +1,74 @@
+package extraction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/domain"
+)
+
+// SchemaVersion identifies the shape of the JSON written alongside each form.
+// It must be incremented whenever a field in the typed documents is renamed or
+// removed, so that downstream consumers can detect the change.
+const SchemaVersion = "3"
+
+// Document is the canonical JSON representation of a parsed form.
+type Document struct {
+	SchemaVersion string `json:"schemaVersion"`
+	DocumentType  string `json:"documentType"`
+	DocumentID    string `json:"documentId,omitempty"`
+	Data          any    `json:"data"`
+
+	// LPA is the form-independent view of the document, for those that
+	// describe a power of attorney. On an instrument it includes anything
+	// merged from continuation sheets in the same set.
+	LPA           *domain.LPA    `json:"lpa,omitempty"`
+	Continuations *Continuations `json:"continuations,omitempty"`
+}
+
+// Continuations reports how the LPC continuation sheets in a set were
+// combined with the instrument they continue. Documents are identified by
+// their ID within the set. Ambiguous sheets could continue more than one
+// instrument in the set, so were merged into none of them.
+type Continuations struct {
+	Instrument string   `json:"instrument,omitempty"`
+	Merged     []string `json:"merged,omitempty"`
+	Unmatched  []string `json:"unmatched,omitempty"`
+	Surplus    []string `json:"surplus,omitempty"`
+	Ambiguous  []string `json:"ambiguous,omitempty"`
+}
+
+// New wraps a parsed form, as returned by the factory, in a versioned envelope.
+func New(docType, docID string, parsedDoc any) (*Document, error) {
+	if parsedDoc == nil {
+		return nil, errors.New("parsed document is nil")
+	}
+
+	lpa, _ := domain.FromDocument(parsedDoc)
+
+	return &Document{
+		SchemaVersion: SchemaVersion,
+		DocumentType:  docType,
+		DocumentID:    docID,
+		Data:          parsedDoc,
+		LPA:           lpa,
+	}, nil
+}
+
+// Marshal returns the JSON encoding of the document.
+func (d *Document) Marshal() ([]byte, error) {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s extraction: %w", d.DocumentType, err)
+	}
+
+	return body, nil
+}
+
+// FileName returns the name the extraction is stored under, which sits next to
+// the form XML it was derived from.
+func FileName(formFileName string) string {
+	return strings.TrimSuffix(formFileName, ".xml") + ".json"
+}
@@ -0,0 +1,65 @@
+package extraction
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1f_parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	xml, err := os.ReadFile("../../testdata/xml/LP1F-valid.xml")
+	require.NoError(t, err)
+
+	parsedDoc, err := lp1f_parser.Parse(xml)
+	require.NoError(t, err)
+
+	doc, err := New("LP1F", "abc-123", parsedDoc)
+	require.NoError(t, err)
+
+	body, err := doc.Marshal()
+	require.NoError(t, err)
+
+	var v struct {
+		SchemaVersion string `json:"schemaVersion"`
+		DocumentType  string `json:"documentType"`
+		DocumentID    string `json:"documentId"`
+		Data          struct {
+			XMLName *struct{} `json:"XMLName"`
+			Page1   struct {
+				Section1 struct {
+					FirstName string `json:"firstName"`
+					LastName  string `json:"lastName"`
+				} `json:"section1"`
+				PhysicalPage string `json:"physicalPage"`
+			} `json:"page1"`
+		} `json:"data"`
+		LPA struct {
+			Donor struct {
+				LastName string `json:"lastName"`
+			} `json:"donor"`
+		} `json:"lpa"`
+	}
+	require.NoError(t, json.Unmarshal(body, &v))
+
+	assert.Equal(t, SchemaVersion, v.SchemaVersion)
+	assert.Equal(t, "LP1F", v.DocumentType)
+	assert.Equal(t, "abc-123", v.DocumentID)
+	assert.Nil(t, v.Data.XMLName)
+	assert.Equal(t, "Charles", v.Data.Page1.Section1.FirstName)
+	assert.Equal(t, "Anderson", v.Data.Page1.Section1.LastName)
+	assert.NotEmpty(t, v.Data.Page1.PhysicalPage)
+	assert.Equal(t, "Anderson", v.LPA.Donor.LastName)
+}
+
+func TestNewNilDocument(t *testing.T) {
+	_, err := New("LP1F", "", nil)
+	assert.Error(t, err)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "FORM_DDC_0192_LP1F.json", FileName("FORM_DDC_0192_LP1F.xml"))
+}
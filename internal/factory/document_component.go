@@ -7,6 +7,7 @@ import (
 	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/corresp_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/ep2pg_parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/generic_parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1f_parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1h_parser"
@@ -54,6 +55,9 @@ var componentRegistry = map[string]component{
 		parser:    lp2_parser.Parse,
 		validator: lp2_parser.NewValidator(),
 	},
+	"EP2PG": {
+		parser: ep2pg_parser.Parse,
+	},
 }
 
 // Returns the component for the specified document type.
@@ -8,12 +8,16 @@ import (
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
 	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type DocumentProcessor struct {
 	logger    *slog.Logger
 	doc       any
+	docID     string
+	docType   string
 	validator parser.CommonValidator
+	issues    []validation.Issue
 }
 
 // Initializes a new DocumentProcessor.
@@ -45,6 +49,8 @@ func NewDocumentProcessor(data *types.BaseDocument, docType string, registry reg
 	return &DocumentProcessor{
 		logger:    logger,
 		doc:       parsedDoc,
+		docID:     data.ID,
+		docType:   docType,
 		validator: validator,
 	}, nil
 }
@@ -61,10 +67,21 @@ func (p *DocumentProcessor) Process(ctx context.Context) (any, error) {
 		return nil, fmt.Errorf("validation setup failed: %w", err)
 	}
 
-	// Return an error if any validations failed.
-	if messages := p.validator.Validate(); len(messages) > 0 {
-		p.logger.InfoContext(ctx, fmt.Sprintf("Validation failed: %v", messages))
+	// Log any validations that failed.
+	p.issues = p.validator.Validate()
+	for i := range p.issues {
+		p.issues[i].DocumentID = p.docID
+		p.issues[i].DocumentType = p.docType
+	}
+
+	if len(p.issues) > 0 {
+		p.logger.InfoContext(ctx, fmt.Sprintf("Validation failed: %v", p.issues))
 	}
 
 	return p.doc, nil
 }
+
+// Issues returns the issues found by the last call to Process.
+func (p *DocumentProcessor) Issues() []validation.Issue {
+	return p.issues
+}
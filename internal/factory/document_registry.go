@@ -5,6 +5,8 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
 )
 
 // Defines the behavior for a document registry.
@@ -54,3 +56,24 @@ func (r *Registry) getValidator(docType string) (parser.CommonValidator, error)
 	}
 	return component.validator, nil
 }
+
+// Parse decodes and parses a document without validating it.
+func (r *Registry) Parse(document *types.BaseDocument) (any, error) {
+	embeddedXML, err := util.DecodeEmbeddedXML(document.EmbeddedXML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode embedded XML: %w", err)
+	}
+
+	return r.ParseXML(document.Type, embeddedXML)
+}
+
+// ParseXML parses the XML of a document of the given type, without validating
+// it.
+func (r *Registry) ParseXML(docType string, data []byte) (any, error) {
+	parser, err := r.getParser(docType)
+	if err != nil {
+		return nil, err
+	}
+
+	return parser(data)
+}
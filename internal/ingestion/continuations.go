@@ -0,0 +1,157 @@
+package ingestion
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/domain"
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+)
+
+// continuationLinks records how the LPC continuation sheets in a set relate to
+// the LP1 instruments they continue.
+type continuationLinks struct {
+	instruments []*linkedInstrument
+	sheets      map[*types.BaseDocument]*linkedInstrument
+	// report lists the sheets that were not merged into any instrument.
+	report extraction.Continuations
+}
+
+// linkedInstrument is an instrument in the set, with the sheets merged into it.
+type linkedInstrument struct {
+	document *types.BaseDocument
+	lpa      *domain.LPA
+	report   extraction.Continuations
+	// attorneys is how many attorneys the merged sheets add.
+	attorneys int
+}
+
+// findInstruments returns the LP1 instruments among the documents parsed from
+// a set, in the order they appear, with nothing yet merged into them.
+func findInstruments(set *types.BaseSet, parsed map[*types.BaseDocument]*checkedDocument) []*linkedInstrument {
+	var instruments []*linkedInstrument
+
+	for i := range set.Body.Documents {
+		doc := &set.Body.Documents[i]
+
+		checked, ok := parsed[doc]
+		if !ok || (doc.Type != constants.DocumentTypeLP1F && doc.Type != constants.DocumentTypeLP1H) {
+			continue
+		}
+
+		instrument := &linkedInstrument{document: doc}
+		instrument.lpa, _ = domain.FromDocument(checked.data)
+		instrument.report.Instrument = documentRef(i, doc)
+		instruments = append(instruments, instrument)
+	}
+
+	return instruments
+}
+
+// linkContinuations finds the continuation sheets in a set and merges each into
+// the instrument in the same set whose donor it names. Sheets that continue
+// nothing are reported as surplus, those that name a different donor, or arrive
+// without an instrument, as unmatched, and those that could continue more than
+// one instrument as ambiguous. The documents are those already parsed from
+// the set. It returns nil when the set contains no continuation sheets.
+func linkContinuations(set *types.BaseSet, parsed map[*types.BaseDocument]*checkedDocument) *continuationLinks {
+	documents := set.Body.Documents
+	if !slices.ContainsFunc(documents, func(doc types.BaseDocument) bool { return doc.Type == constants.DocumentTypeLPC }) {
+		return nil
+	}
+
+	links := &continuationLinks{
+		instruments: findInstruments(set, parsed),
+		sheets:      map[*types.BaseDocument]*linkedInstrument{},
+	}
+
+	for i := range documents {
+		doc := &documents[i]
+		checked, ok := parsed[doc]
+		if !ok {
+			continue
+		}
+
+		lpc, ok := checked.data.(*lpc_types.LPCDocument)
+		if !ok {
+			continue
+		}
+
+		continuation := domain.FromLPC(lpc)
+		ref := documentRef(i, doc)
+
+		if continuation.IsEmpty() {
+			links.report.Surplus = append(links.report.Surplus, ref)
+			continue
+		}
+
+		var candidates []*linkedInstrument
+		for _, instrument := range links.instruments {
+			if instrument.lpa != nil && continuation.Continues(instrument.lpa) {
+				candidates = append(candidates, instrument)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			links.report.Unmatched = append(links.report.Unmatched, ref)
+		case 1:
+			candidates[0].lpa.Merge(continuation)
+			candidates[0].attorneys += len(continuation.Attorneys)
+			candidates[0].report.Merged = append(candidates[0].report.Merged, ref)
+			links.sheets[doc] = candidates[0]
+		default:
+			links.report.Ambiguous = append(links.report.Ambiguous, ref)
+		}
+	}
+
+	// Each instrument's report also lists the sheets that weren't merged.
+	for _, instrument := range links.instruments {
+		instrument.report.Unmatched = links.report.Unmatched
+		instrument.report.Surplus = links.report.Surplus
+		instrument.report.Ambiguous = links.report.Ambiguous
+	}
+
+	return links
+}
+
+// apply adds the result of linking to a document's extraction. An instrument
+// takes its merged LPA and its report, a sheet merged into an instrument takes
+// that instrument's report, and any other sheet takes the report of the sheets
+// that weren't merged.
+func (l *continuationLinks) apply(document *types.BaseDocument, doc *extraction.Document) {
+	if l == nil {
+		return
+	}
+
+	for _, instrument := range l.instruments {
+		if document == instrument.document {
+			doc.LPA = instrument.lpa
+			doc.Continuations = &instrument.report
+			return
+		}
+	}
+
+	if document.Type != constants.DocumentTypeLPC {
+		return
+	}
+
+	if instrument, ok := l.sheets[document]; ok {
+		doc.Continuations = &instrument.report
+	} else {
+		doc.Continuations = &l.report
+	}
+}
+
+// documentRef identifies a document within its set, falling back to its
+// position when the scanner did not give it an ID.
+func documentRef(index int, doc *types.BaseDocument) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+
+	return fmt.Sprintf("%s[%d]", doc.Type, index)
+}
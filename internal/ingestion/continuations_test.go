@@ -0,0 +1,150 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func embeddedFixture(docType, id, xml string) types.BaseDocument {
+	return types.BaseDocument{
+		Type:        docType,
+		ID:          id,
+		NoPages:     1,
+		EmbeddedXML: base64.StdEncoding.EncodeToString([]byte(xml)),
+	}
+}
+
+// parseFixtures parses the documents in a set as the worker does.
+func parseFixtures(t *testing.T, set *types.BaseSet) map[*types.BaseDocument]*checkedDocument {
+	t.Helper()
+
+	worker := &Worker{logger: slog.New(slog.DiscardHandler)}
+	parsed, err := worker.parseDocuments(context.Background(), set)
+	require.NoError(t, err)
+
+	return parsed
+}
+
+func readXMLFixture(t *testing.T, fileName string) string {
+	data, err := os.ReadFile("../../testdata/xml/" + fileName)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestLinkContinuations(t *testing.T) {
+	lp1f := readXMLFixture(t, "LP1F-valid.xml")
+	lpc := readXMLFixture(t, "LPC-valid.xml")
+	matchingLPC := strings.NewReplacer("Jane Donor", "Charles Anderson", "John Donor", "charles  anderson").Replace(lpc)
+
+	set := &types.BaseSet{Body: types.BaseBody{Documents: []types.BaseDocument{
+		embeddedFixture("LPC", "lpc-1", matchingLPC),
+		embeddedFixture("LP1F", "lp1f", lp1f),
+		embeddedFixture("LPC", "lpc-2", lpc),
+		embeddedFixture("LPC", "", `<LPC></LPC>`),
+	}}}
+
+	links := linkContinuations(set, parseFixtures(t, set))
+	require.NotNil(t, links)
+
+	require.Len(t, links.instruments, 1)
+	instrument := links.instruments[0]
+
+	assert.Same(t, &set.Body.Documents[1], instrument.document)
+	assert.Equal(t, extraction.Continuations{
+		Instrument: "lp1f",
+		Merged:     []string{"lpc-1"},
+		Unmatched:  []string{"lpc-2"},
+		Surplus:    []string{"LPC[3]"},
+	}, instrument.report)
+
+	require.Len(t, instrument.lpa.Attorneys, 5)
+	assert.Equal(t, "Jack Jones", instrument.lpa.Attorneys[4].FullName())
+	require.Len(t, instrument.lpa.ReplacementAttorneys, 2)
+	assert.Equal(t, "Mary Smith", instrument.lpa.ReplacementAttorneys[1].FullName())
+	assert.Equal(t, []string{"notes", "instructions", "preferences"}, instrument.lpa.ContinuedInformation)
+
+	doc := &extraction.Document{}
+	links.apply(&set.Body.Documents[1], doc)
+	assert.Same(t, instrument.lpa, doc.LPA)
+	assert.Equal(t, &instrument.report, doc.Continuations)
+
+	doc = &extraction.Document{}
+	links.apply(&set.Body.Documents[0], doc)
+	assert.Nil(t, doc.LPA)
+	assert.Equal(t, &instrument.report, doc.Continuations)
+
+	doc = &extraction.Document{}
+	links.apply(&set.Body.Documents[2], doc)
+	assert.Nil(t, doc.LPA)
+	assert.Equal(t, &links.report, doc.Continuations)
+}
+
+func TestLinkContinuationsWithTwoInstruments(t *testing.T) {
+	lpc := readXMLFixture(t, "LPC-valid.xml")
+	lp1fLPC := strings.NewReplacer("Jane Donor", "Charles Anderson", "John Donor", "Charles Anderson").Replace(lpc)
+	lp1hLPC := strings.NewReplacer("Jane Donor", "John Doe", "John Donor", "john doe").Replace(lpc)
+	unnamedLPC := strings.NewReplacer("Jane Donor", "", "John Donor", "").Replace(lpc)
+
+	set := &types.BaseSet{Body: types.BaseBody{Documents: []types.BaseDocument{
+		embeddedFixture("LP1F", "lp1f", readXMLFixture(t, "LP1F-valid.xml")),
+		embeddedFixture("LP1H", "lp1h", readXMLFixture(t, "LP1H-valid.xml")),
+		embeddedFixture("LPC", "lpc-1", lp1hLPC),
+		embeddedFixture("LPC", "lpc-2", lp1fLPC),
+		embeddedFixture("LPC", "lpc-3", unnamedLPC),
+	}}}
+
+	links := linkContinuations(set, parseFixtures(t, set))
+	require.Len(t, links.instruments, 2)
+
+	assert.Equal(t, extraction.Continuations{
+		Instrument: "lp1f",
+		Merged:     []string{"lpc-2"},
+		Ambiguous:  []string{"lpc-3"},
+	}, links.instruments[0].report)
+	assert.Equal(t, extraction.Continuations{
+		Instrument: "lp1h",
+		Merged:     []string{"lpc-1"},
+		Ambiguous:  []string{"lpc-3"},
+	}, links.instruments[1].report)
+	assert.Equal(t, extraction.Continuations{
+		Ambiguous: []string{"lpc-3"},
+	}, links.report)
+
+	doc := &extraction.Document{}
+	links.apply(&set.Body.Documents[2], doc)
+	assert.Equal(t, &links.instruments[1].report, doc.Continuations)
+}
+
+func TestLinkContinuationsWithoutInstrument(t *testing.T) {
+	set := &types.BaseSet{Body: types.BaseBody{Documents: []types.BaseDocument{
+		embeddedFixture("LPC", "lpc", readXMLFixture(t, "LPC-valid.xml")),
+	}}}
+
+	links := linkContinuations(set, parseFixtures(t, set))
+
+	assert.Equal(t, []string{"lpc"}, links.report.Unmatched)
+	assert.Empty(t, links.report.Merged)
+}
+
+func TestLinkContinuationsWithoutSheets(t *testing.T) {
+	set := &types.BaseSet{Body: types.BaseBody{Documents: []types.BaseDocument{
+		embeddedFixture("LP1F", "lp1f", readXMLFixture(t, "LP1F-valid.xml")),
+	}}}
+
+	links := linkContinuations(set, parseFixtures(t, set))
+	assert.Nil(t, links)
+
+	// a nil set of links leaves extractions untouched
+	doc := &extraction.Document{}
+	links.apply(&set.Body.Documents[0], doc)
+	assert.Nil(t, doc.Continuations)
+}
@@ -0,0 +1,196 @@
+package ingestion
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/domain"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/corresp_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpa115_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpa116_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+// Codes for issues found by comparing documents within a set.
+const (
+	CodeDonorNameMismatch         = "donor-name-mismatch"
+	CodeReferenceMismatch         = "reference-mismatch"
+	CodeContinuationAttorneyCount = "continuation-attorney-count"
+	CodeCaseNumberMismatch        = "case-number-mismatch"
+)
+
+// CrossCheckSet compares the documents in a set with each other and with the
+// set header, returning any inconsistencies with the severity given by the
+// validation policy. Every check is only a warning unless the policy makes it
+// an error. The documents are those already parsed from the set, and links
+// the continuation sheets linked to its instruments, if there are any.
+func (v *Validator) CrossCheckSet(set *types.BaseSet, parsed map[*types.BaseDocument]*checkedDocument, links *continuationLinks) []validation.Issue {
+	var instruments []*linkedInstrument
+	if links != nil {
+		instruments = links.instruments
+	} else {
+		instruments = findInstruments(set, parsed)
+	}
+
+	// The first instrument's LPA names the attorneys on the sheets merged into
+	// it too.
+	var instrumentLPA *domain.LPA
+	if len(instruments) > 0 {
+		instrumentLPA = instruments[0].lpa
+	}
+
+	caseNo := ""
+	if set.Header != nil {
+		caseNo = set.Header.CaseNo
+	}
+
+	var issues []validation.Issue
+
+	for i := range set.Body.Documents {
+		doc := &set.Body.Documents[i]
+
+		checked, ok := parsed[doc]
+		if !ok {
+			continue
+		}
+
+		newIssue := func(code string, severity validation.Severity, format string, args ...any) validation.Issue {
+			return validation.Issue{
+				DocumentID:   doc.ID,
+				DocumentType: doc.Type,
+				Code:         code,
+				Severity:     severity,
+				Message:      fmt.Sprintf(format, args...),
+			}
+		}
+
+		switch parsed := checked.data.(type) {
+		case *lp2_types.LP2Document:
+			if instrumentLPA == nil {
+				continue
+			}
+
+			donor := domain.FromLP2(parsed).Donor
+			if !sameName(donor.FullName(), instrumentLPA.Donor.FullName()) {
+				issues = append(issues, newIssue(CodeDonorNameMismatch, validation.SeverityWarning,
+					"%s donor %q does not match instrument donor %q", doc.Type, donor.FullName(), instrumentLPA.Donor.FullName()))
+			}
+
+		case *lpa115_types.LPA115Document:
+			if instrumentLPA == nil {
+				continue
+			}
+
+			for _, page := range parsed.Page3 {
+				if name := page.PartA.FullName; name != "" && !namedOn(instrumentLPA, name) {
+					issues = append(issues, newIssue(CodeReferenceMismatch, validation.SeverityWarning,
+						"%s is signed by %q who is not named on the instrument", doc.Type, name))
+				}
+			}
+
+		case *lpa116_types.LPA116Document:
+			if instrumentLPA == nil {
+				continue
+			}
+
+			for _, page := range parsed.Page1 {
+				if name := page.PartC.Attorney.FullName; name != "" && !namedOn(instrumentLPA, name) {
+					issues = append(issues, newIssue(CodeReferenceMismatch, validation.SeverityWarning,
+						"%s is signed by attorney %q who is not named on the instrument", doc.Type, name))
+				}
+			}
+
+		case *corresp_types.Correspondence:
+			if caseNo == "" {
+				continue
+			}
+
+			// Correspondence may refer to several cases, so only flag it
+			// when none of them is the case it is being filed against.
+			var caseNumbers []string
+			for _, caseNumber := range parsed.CaseNumber {
+				if caseNumber != "" {
+					caseNumbers = append(caseNumbers, caseNumber)
+				}
+			}
+
+			if len(caseNumbers) > 0 && !slices.ContainsFunc(caseNumbers, func(caseNumber string) bool { return sameCaseNumber(caseNumber, caseNo) }) {
+				issues = append(issues, newIssue(CodeCaseNumberMismatch, validation.SeverityWarning,
+					"%s case numbers %s do not include set case number %q", doc.Type, strings.Join(caseNumbers, ", "), caseNo))
+			}
+		}
+	}
+
+	// Only the sheets merged into an instrument add attorneys to it.
+	for _, instrument := range instruments {
+		if instrument.attorneys == 0 {
+			continue
+		}
+
+		var form *lp1f_types.LP1FDocument
+		switch data := parsed[instrument.document].data.(type) {
+		case *lp1f_types.LP1FDocument:
+			form = data
+		case *lp1h_types.LP1HDocument:
+			form = &data.LP1FDocument
+		default:
+			continue
+		}
+
+		newIssue := func(format string) validation.Issue {
+			return validation.Issue{
+				DocumentID:   instrument.document.ID,
+				DocumentType: instrument.document.Type,
+				Code:         CodeContinuationAttorneyCount,
+				Severity:     validation.SeverityWarning,
+				Message:      fmt.Sprintf(format, instrument.attorneys),
+			}
+		}
+
+		if form.Page4.Section3.AppointedOneAttorney {
+			issues = append(issues, newIssue("continuation sheets add %d attorneys but the instrument appoints one attorney"))
+		} else if !form.Page3.Section2.MoreAttorneys {
+			issues = append(issues, newIssue("continuation sheets add %d attorneys but the instrument does not indicate more attorneys"))
+		}
+	}
+
+	return v.policy.Apply(issues)
+}
+
+// namedOn reports whether a person with the given name is the donor or one of
+// the attorneys on a power of attorney.
+func namedOn(lpa *domain.LPA, name string) bool {
+	if lpa.Donor != nil && sameName(name, lpa.Donor.FullName()) {
+		return true
+	}
+
+	for _, attorney := range lpa.Attorneys {
+		if sameName(name, attorney.FullName()) {
+			return true
+		}
+	}
+
+	for _, attorney := range lpa.ReplacementAttorneys {
+		if sameName(name, attorney.FullName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// sameCaseNumber compares case numbers ignoring the formatting used when they
+// are written by hand, such as 7000-0000-0000.
+func sameCaseNumber(a, b string) bool {
+	normalise := strings.NewReplacer("-", "", " ", "")
+	return strings.EqualFold(normalise.Replace(a), normalise.Replace(b))
+}
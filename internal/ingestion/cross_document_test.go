@@ -0,0 +1,142 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrossCheckSet(t *testing.T) {
+	lp1f := readXMLFixture(t, "LP1F-valid.xml")
+	lpc := strings.NewReplacer("Jane Donor", "Charles Anderson", "John Donor", "Charles Anderson").Replace(readXMLFixture(t, "LPC-valid.xml"))
+
+	testCases := []struct {
+		name      string
+		caseNo    string
+		documents []types.BaseDocument
+		policy    validation.Policy
+		expected  []validation.Issue
+	}{
+		{
+			name: "consistent set",
+			documents: []types.BaseDocument{
+				embeddedFixture("LP1F", "1", lp1f),
+				embeddedFixture("LPC", "2", lpc),
+				embeddedFixture("LPA115", "3", strings.ReplaceAll(readXMLFixture(t, "LPA115-valid.xml"), "John Doe", "charles anderson")),
+				embeddedFixture("LPA116", "4", strings.ReplaceAll(readXMLFixture(t, "LPA116-valid.xml"), "John Attorney", "Jack Jones")),
+			},
+		},
+		{
+			name: "LP2 donor differs from instrument",
+			documents: []types.BaseDocument{
+				embeddedFixture("LP1F", "1", lp1f),
+				embeddedFixture("LP2", "2", readXMLFixture(t, "LP2-valid.xml")),
+			},
+			expected: []validation.Issue{{
+				DocumentID:   "2",
+				DocumentType: "LP2",
+				Code:         CodeDonorNameMismatch,
+				Severity:     validation.SeverityWarning,
+				Message:      `LP2 donor "Flavio Miller" does not match instrument donor "Charles Anderson"`,
+			}},
+		},
+		{
+			name: "forms signed by people not on the instrument",
+			documents: []types.BaseDocument{
+				embeddedFixture("LPA115", "1", readXMLFixture(t, "LPA115-valid.xml")),
+				embeddedFixture("LPA116", "2", readXMLFixture(t, "LPA116-valid.xml")),
+				embeddedFixture("LP1F", "3", lp1f),
+			},
+			expected: []validation.Issue{{
+				DocumentID:   "1",
+				DocumentType: "LPA115",
+				Code:         CodeReferenceMismatch,
+				Severity:     validation.SeverityWarning,
+				Message:      `LPA115 is signed by "John Doe" who is not named on the instrument`,
+			}, {
+				DocumentID:   "2",
+				DocumentType: "LPA116",
+				Code:         CodeReferenceMismatch,
+				Severity:     validation.SeverityWarning,
+				Message:      `LPA116 is signed by attorney "John Attorney" who is not named on the instrument`,
+			}},
+		},
+		{
+			name: "continuation attorneys without more attorneys ticked",
+			documents: []types.BaseDocument{
+				embeddedFixture("LP1F", "1", strings.Replace(lp1f, "<MoreAttorneys>true</MoreAttorneys>", "<MoreAttorneys>false</MoreAttorneys>", 1)),
+				embeddedFixture("LPC", "2", lpc),
+			},
+			expected: []validation.Issue{{
+				DocumentID:   "1",
+				DocumentType: "LP1F",
+				Code:         CodeContinuationAttorneyCount,
+				Severity:     validation.SeverityWarning,
+				Message:      "continuation sheets add 1 attorneys but the instrument does not indicate more attorneys",
+			}},
+		},
+		{
+			name: "continuation attorneys for another donor",
+			documents: []types.BaseDocument{
+				embeddedFixture("LP1F", "1", strings.Replace(lp1f, "<MoreAttorneys>true</MoreAttorneys>", "<MoreAttorneys>false</MoreAttorneys>", 1)),
+				embeddedFixture("LPC", "2", readXMLFixture(t, "LPC-valid.xml")),
+			},
+		},
+		{
+			name:   "correspondence for another case",
+			caseNo: "7000-0000-0001",
+			documents: []types.BaseDocument{
+				embeddedFixture("Correspondence", "1", readXMLFixture(t, "Correspondence-valid.xml")),
+			},
+			expected: []validation.Issue{{
+				DocumentID:   "1",
+				DocumentType: "Correspondence",
+				Code:         CodeCaseNumberMismatch,
+				Severity:     validation.SeverityWarning,
+				Message:      `Correspondence case numbers 12345, 67890 do not include set case number "7000-0000-0001"`,
+			}},
+		},
+		{
+			name:   "correspondence for the same case",
+			caseNo: "6789-0",
+			documents: []types.BaseDocument{
+				embeddedFixture("Correspondence", "1", readXMLFixture(t, "Correspondence-valid.xml")),
+			},
+		},
+		{
+			name:   "policy overrides severity",
+			caseNo: "700000000001",
+			documents: []types.BaseDocument{
+				embeddedFixture("Correspondence", "1", readXMLFixture(t, "Correspondence-valid.xml")),
+				embeddedFixture("LPA115", "2", readXMLFixture(t, "LPA115-valid.xml")),
+				embeddedFixture("LP1F", "3", lp1f),
+			},
+			policy: validation.Policy{
+				CodeCaseNumberMismatch: validation.SeverityError,
+				CodeReferenceMismatch:  validation.SeverityIgnore,
+			},
+			expected: []validation.Issue{{
+				DocumentID:   "1",
+				DocumentType: "Correspondence",
+				Code:         CodeCaseNumberMismatch,
+				Severity:     validation.SeverityError,
+				Message:      `Correspondence case numbers 12345, 67890 do not include set case number "700000000001"`,
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := &types.BaseSet{
+				Header: &types.BaseHeader{CaseNo: tc.caseNo},
+				Body:   types.BaseBody{Documents: tc.documents},
+			}
+
+			parsed := parseFixtures(t, set)
+			assert.Equal(t, tc.expected, NewValidator(tc.policy).CrossCheckSet(set, parsed, linkContinuations(set, parsed)))
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 )
 
 const (
@@ -38,14 +39,20 @@ func (s *DocumentTracker) SetProcessing(ctx context.Context, id, caseNo string)
 		return nil
 	}
 
+	item := map[string]types.AttributeValue{
+		"PK":     &types.AttributeValueMemberS{Value: "DOCUMENT#" + id},
+		"SK":     &types.AttributeValueMemberS{Value: "DOCUMENT#" + id},
+		"CaseNo": &types.AttributeValueMemberS{Value: caseNo},
+		"Status": &types.AttributeValueMemberS{Value: statusProcessing},
+	}
+
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		item["SubmittedBy"] = &types.AttributeValueMemberS{Value: principal.ID}
+	}
+
 	_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(s.tableName),
-		Item: map[string]types.AttributeValue{
-			"PK":     &types.AttributeValueMemberS{Value: "DOCUMENT#" + id},
-			"SK":     &types.AttributeValueMemberS{Value: "DOCUMENT#" + id},
-			"CaseNo": &types.AttributeValueMemberS{Value: caseNo},
-			"Status": &types.AttributeValueMemberS{Value: statusProcessing},
-		},
+		TableName:                           aws.String(s.tableName),
+		Item:                                item,
 		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 		ConditionExpression:                 aws.String("attribute_not_exists(PK) OR #Status = :Failed"),
 		ExpressionAttributeNames: map[string]string{"#Status": "Status"},
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -113,3 +114,23 @@ func TestIntegrationDocumentTracker_ErrorsWhenCurrentlyProcessing(t *testing.T)
 		assert.ErrorAs(t, err, &v)
 	})
 }
+
+func TestIntegrationDocumentTracker_RecordsSubmitter(t *testing.T) {
+	withDocumentTracker(t, func(tracker *DocumentTracker) {
+		ctx := auth.ContextWithPrincipal(ctx, auth.Principal{ID: "supplier@example.com"})
+
+		err := tracker.SetProcessing(ctx, "my-id", "my-caseno")
+		assert.Nil(t, err)
+
+		output, err := tracker.dynamo.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String("test"),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: "DOCUMENT#my-id"},
+				"SK": &types.AttributeValueMemberS{Value: "DOCUMENT#my-id"},
+			},
+		})
+		if assert.Nil(t, err) {
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "supplier@example.com"}, output.Item["SubmittedBy"])
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ministryofjustice/opg-scanning/internal/factory"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+// Codes for issues that stop a set or document from being checked further.
+const (
+	CodeSetUnreadable           = "set-unreadable"
+	CodeDocumentTypeUnsupported = "document-type-unsupported"
+	CodeDocumentUnreadable      = "document-unreadable"
+)
+
+// Report lists every issue found when checking a set without processing it.
+type Report struct {
+	Valid  bool               `json:"valid"`
+	Issues []validation.Issue `json:"issues"`
+}
+
+// Validate runs the checks made by Process against a set, but does not store
+// anything or contact Sirius. When the worker has a fingerprint store it looks
+// for earlier submissions of the set's content, without recording this one.
+// Rather than stopping at the first problem it carries on as far as it can, so
+// the report contains every issue found.
+func (w *Worker) Validate(ctx context.Context, body []byte) (*Report, error) {
+	report := &Report{Issues: []validation.Issue{}}
+
+	set, issues := w.checkSetXML(ctx, body)
+	report.add(issues...)
+
+	if set != nil {
+		report.add(permissionIssues(ctx, set)...)
+		report.add(w.validator.SetIssues(set)...)
+
+		issues, err := w.checkDocuments(ctx, set)
+		if err != nil {
+			return nil, err
+		}
+		report.add(issues...)
+
+		if w.fingerprints != nil {
+			fingerprints := setFingerprints(set)
+
+			earlier, err := w.fingerprints.Find(ctx, fingerprints)
+			if err != nil {
+				return nil, FingerprintLookupError{Err: fmt.Errorf("failed to find earlier submissions: %w", err)}
+			}
+
+			report.add(duplicateIssues(set, fingerprints, earlier, w.duplicatePolicy)...)
+		}
+	}
+
+	report.Valid = len(validation.Errors(report.Issues)) == 0
+
+	return report, nil
+}
+
+func (r *Report) add(issues ...validation.Issue) {
+	r.Issues = append(r.Issues, issues...)
+}
+
+// checkSetXML validates a set against its schema and parses it. The set is
+// still parsed when it does not match the schema, so that its documents can be
+// checked too.
+func (w *Worker) checkSetXML(ctx context.Context, body []byte) (*types.BaseSet, []validation.Issue) {
+	var issues []validation.Issue
+
+	schemaLocation, err := ExtractSchemaLocation(body)
+	if err != nil {
+		return nil, []validation.Issue{unreadable(CodeSetUnreadable, nil, err)}
+	}
+
+	w.logger.InfoContext(ctx, "Validating against XSD")
+	xsdValidator, err := NewXSDValidator(w.config, schemaLocation, body)
+	if err != nil {
+		return nil, []validation.Issue{unreadable(CodeSetUnreadable, nil, err)}
+	}
+
+	if err := xsdValidator.ValidateXsd(); err != nil {
+		var schemaValidationError SchemaValidationError
+		if !errors.As(err, &schemaValidationError) {
+			return nil, []validation.Issue{unreadable(CodeSetUnreadable, nil, fmt.Errorf("set failed XSD validation: %w", err))}
+		}
+
+		issues = append(issues, schemaIssues(schemaValidationError, nil)...)
+	}
+
+	set, err := NewXmlValidator(*w.config).XmlValidate(body)
+	if err != nil {
+		return nil, append(issues, unreadable(CodeSetUnreadable, nil, err))
+	}
+
+	return set, issues
+}
+
+// checkDocuments validates each document in a set against its schema and form
+// validator, then checks the documents against each other. The cross-document
+// checks need every document, so are skipped if any could not be parsed.
+func (w *Worker) checkDocuments(ctx context.Context, set *types.BaseSet) ([]validation.Issue, error) {
+	registry, err := factory.NewRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	var issues []validation.Issue
+	parsed := make(map[*types.BaseDocument]*checkedDocument, len(set.Body.Documents))
+	allParsed := true
+
+	for i := range set.Body.Documents {
+		document := &set.Body.Documents[i]
+
+		if err := w.validateDocument(*document); err != nil {
+			var problem Problem
+			switch {
+			case errors.As(err, &problem) && len(problem.Issues) > 0:
+				issues = append(issues, problem.Issues...)
+			case errors.As(err, &problem):
+				allParsed = false
+				issues = append(issues, unreadable(CodeDocumentTypeUnsupported, document, err))
+				continue
+			default:
+				allParsed = false
+				issues = append(issues, unreadable(CodeDocumentUnreadable, document, err))
+				continue
+			}
+		}
+
+		doc, err := w.parseDocument(ctx, registry, document)
+		if err != nil {
+			allParsed = false
+			issues = append(issues, unreadable(CodeDocumentUnreadable, document, err))
+			continue
+		}
+
+		parsed[document] = doc
+		issues = append(issues, doc.issues...)
+	}
+
+	if !allParsed {
+		w.logger.InfoContext(ctx, "Skipping cross-document checks as not every document could be parsed")
+		return issues, nil
+	}
+
+	return append(issues, w.validator.CrossCheckSet(set, parsed, linkContinuations(set, parsed))...), nil
+}
+
+// unreadable creates an error issue for a set or document that could not be
+// checked any further.
+func unreadable(code string, document *types.BaseDocument, err error) validation.Issue {
+	issue := validation.Issue{
+		Code:     code,
+		Severity: validation.SeverityError,
+		Message:  err.Error(),
+	}
+
+	if document != nil {
+		issue.DocumentID = document.ID
+		issue.DocumentType = document.Type
+	}
+
+	return issue
+}
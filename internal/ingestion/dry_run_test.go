@@ -0,0 +1,185 @@
+package ingestion
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// noEarlierSubmissions is a fingerprint store that has not seen the set
+// before. Nothing may be recorded in it.
+func noEarlierSubmissions(t *testing.T) *mockFingerprintStore {
+	fingerprints := newMockFingerprintStore(t)
+	fingerprints.EXPECT().
+		Find(mock.Anything, mock.Anything).
+		Return(map[Fingerprint]Submission{}, nil)
+
+	return fingerprints
+}
+
+func TestWorkerValidate(t *testing.T) {
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		awsClient:     newMockAwsClient(t),
+		siriusService: newMockSiriusService(t),
+		validator:     NewValidator(nil),
+		fingerprints:  noEarlierSubmissions(t),
+	}
+
+	report, err := worker.Validate(context.Background(), []byte(xmlPayload))
+	require.NoError(t, err)
+
+	assert.True(t, report.Valid)
+	assert.Empty(t, validation.Errors(report.Issues))
+}
+
+func TestWorkerValidate_Duplicate(t *testing.T) {
+	config, _ := config.Read()
+
+	fingerprints := newMockFingerprintStore(t)
+	fingerprints.EXPECT().
+		Find(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error) {
+			return map[Fingerprint]Submission{fingerprints[0]: {CaseNo: "7000-0000-0001"}}, nil
+		})
+
+	worker := &Worker{
+		logger:          slog.New(slog.DiscardHandler),
+		config:          config,
+		awsClient:       newMockAwsClient(t),
+		siriusService:   newMockSiriusService(t),
+		validator:       NewValidator(nil),
+		fingerprints:    fingerprints,
+		duplicatePolicy: validation.DuplicatePolicy{"LP2": validation.SeverityError},
+	}
+
+	report, err := worker.Validate(context.Background(), []byte(xmlPayload))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+	if errs := validation.Errors(report.Issues); assert.Len(t, errs, 1) {
+		assert.Equal(t, CodeDuplicateSet, errs[0].Code)
+	}
+}
+
+func TestWorkerValidate_DuplicateLookupFails(t *testing.T) {
+	config, _ := config.Read()
+
+	fingerprints := newMockFingerprintStore(t)
+	fingerprints.EXPECT().
+		Find(mock.Anything, mock.Anything).
+		Return(nil, expectedError)
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		awsClient:     newMockAwsClient(t),
+		siriusService: newMockSiriusService(t),
+		validator:     NewValidator(nil),
+		fingerprints:  fingerprints,
+	}
+
+	_, err := worker.Validate(context.Background(), []byte(xmlPayload))
+	assert.ErrorAs(t, err, &FingerprintLookupError{})
+}
+
+func TestWorkerValidate_DocumentTypeNotPermitted(t *testing.T) {
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		awsClient:     newMockAwsClient(t),
+		siriusService: newMockSiriusService(t),
+		validator:     NewValidator(nil),
+		fingerprints:  noEarlierSubmissions(t),
+	}
+
+	ctx := auth.ContextWithPrincipal(context.Background(), auth.Principal{
+		ID:     "cop@example.com",
+		Scopes: []string{auth.ScopeSubmit, auth.DocumentScope("COPORD")},
+	})
+
+	report, err := worker.Validate(ctx, []byte(xmlPayload))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+	assert.Equal(t, []validation.Issue{{
+		DocumentType: "LP2",
+		Code:         CodeDocumentTypeNotPermitted,
+		Severity:     validation.SeverityError,
+		Message:      "cop@example.com is not permitted to submit LP2 documents",
+	}}, validation.Errors(report.Issues))
+}
+
+func TestWorkerValidate_ReportsEveryIssue(t *testing.T) {
+	payload := `<Set xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="SET.xsd">
+		<Header CaseNo="700000000001" Scanner="9" ScanTime="2014-09-26T12:38:53" ScannerOperator="Administrator" Schedule="" />
+		<Body>
+			<Document Type="LP1F" Encoding="UTF-8" NoPages="19">
+				<XML>PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0iVVRGLTgiIHN0YW5kYWxvbmU9Im5vIj8+CjxMUDIgeG1sbnM6eHNpPSJodHRwOi8vd3d3LnczLm9yZy8yMDAxL1hNTFNjaGVtYS1pbnN0YW5jZSIgeHNpOm5vTmFtZXNwYWNlU2NoZW1hTG9jYXRpb249IkxQMi54c2QiPjwvTFAyPg==</XML>
+				<PDF>SGVsbG8gd29ybGQ=</PDF>
+			</Document>
+			<Document Type="LP2" Encoding="UTF-8" NoPages="0">
+				<XML>bm90IHhtbA==</XML>
+				<PDF>SGVsbG8gd29ybGQ=</PDF>
+			</Document>
+		</Body>
+	</Set>`
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		awsClient:     newMockAwsClient(t),
+		siriusService: newMockSiriusService(t),
+		validator:     NewValidator(nil),
+		fingerprints:  noEarlierSubmissions(t),
+	}
+
+	report, err := worker.Validate(context.Background(), []byte(payload))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+
+	var issues []string
+	for _, issue := range report.Issues {
+		issues = append(issues, issue.DocumentType+" "+issue.Code+": "+issue.Message)
+	}
+
+	assert.Equal(t, []string{
+		" set-invalid: missing required Schedule attribute on Header",
+		"LP2 set-invalid: document NoPages attribute is missing or invalid",
+		" set-invalid: set cannot contain multiple cases which would create a case",
+		" set-invalid: must not supply a case number when creating a new case",
+		"LP1F schema-invalid: Element 'LP2': Missing child element(s). Expected is ( Page1 ).",
+		"LP1F document-unreadable: failed to parse document: expected element type <LP1F> but have <LP2>",
+		"LP2 document-unreadable: failed to extract schema from LP2: failed to parse XML: EOF",
+	}, issues)
+}
+
+func TestWorkerValidate_UnreadableSet(t *testing.T) {
+	worker := &Worker{
+		logger:    slog.New(slog.DiscardHandler),
+		validator: NewValidator(nil),
+	}
+
+	report, err := worker.Validate(context.Background(), []byte(`<Set>`))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+	if assert.Len(t, report.Issues, 1) {
+		assert.Equal(t, CodeSetUnreadable, report.Issues[0].Code)
+	}
+}
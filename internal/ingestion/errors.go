@@ -1,12 +1,32 @@
 package ingestion
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
 
 var ErrScannedCaseResponseUIDMissing = errors.New("scannedCaseResponse UID missing")
 
 type Problem struct {
 	Title            string
 	ValidationErrors []string
+	Issues           []validation.Issue
+}
+
+// newProblem creates a Problem for a set of issues, keeping their messages as
+// ValidationErrors for clients that only read those.
+func newProblem(title string, issues []validation.Issue) Problem {
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.Message
+	}
+
+	return Problem{
+		Title:            title,
+		ValidationErrors: messages,
+		Issues:           issues,
+	}
 }
 
 func (p Problem) Error() string {
@@ -40,3 +60,28 @@ type PersistSetError struct {
 
 func (e PersistSetError) Error() string { return e.Err.Error() }
 func (e PersistSetError) Unwrap() error { return e.Err }
+
+// DuplicateError is returned when a set, or a PDF in it, has been submitted
+// before and the duplicate policy rejects it.
+type DuplicateError struct {
+	Err error
+}
+
+func (e DuplicateError) Error() string { return e.Err.Error() }
+func (e DuplicateError) Unwrap() error { return e.Err }
+
+// FingerprintLookupError is returned when earlier submissions can't be looked
+// up, so it isn't known whether the set is a duplicate.
+type FingerprintLookupError struct {
+	Err error
+}
+
+func (e FingerprintLookupError) Error() string { return e.Err.Error() }
+func (e FingerprintLookupError) Unwrap() error { return e.Err }
+
+type ForbiddenError struct {
+	Err error
+}
+
+func (e ForbiddenError) Error() string { return e.Err.Error() }
+func (e ForbiddenError) Unwrap() error { return e.Err }
@@ -0,0 +1,272 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+// Kinds of content that are fingerprinted.
+const (
+	FingerprintSet = "SET"
+	FingerprintPDF = "PDF"
+)
+
+// Codes for issues raised when content has been submitted before.
+const (
+	CodeDuplicateSet = "duplicate-set"
+	CodeDuplicatePDF = "duplicate-pdf"
+)
+
+// maxBatchGetKeys is the most keys DynamoDB will read in one BatchGetItem.
+const maxBatchGetKeys = 100
+
+// Fingerprint identifies a whole set, by what it contains, or the PDF of one of
+// its documents, by the SHA-256 hash of its content.
+type Fingerprint struct {
+	Kind         string
+	Hash         string
+	DocumentID   string
+	DocumentType string
+}
+
+func (f Fingerprint) key() string {
+	return "FINGERPRINT#" + f.Kind + "#" + f.Hash
+}
+
+// setFingerprints returns the fingerprints of a set and each decoded PDF in
+// it. Documents without a PDF that can be decoded are left out.
+func setFingerprints(set *types.BaseSet) []Fingerprint {
+	fingerprints := []Fingerprint{{Kind: FingerprintSet, Hash: setHash(set)}}
+
+	for _, document := range set.Body.Documents {
+		if hash, ok := pdfHash(&document); ok {
+			fingerprints = append(fingerprints, Fingerprint{
+				Kind:         FingerprintPDF,
+				Hash:         hash,
+				DocumentID:   document.ID,
+				DocumentType: document.Type,
+			})
+		}
+	}
+
+	return fingerprints
+}
+
+// setHash hashes the type, and the hashes of the decoded XML and PDF, of each
+// document in the set, in order. Document IDs and the header, which change
+// each time a set is scanned, are left out so that a set scanned again is
+// still recognised.
+func setHash(set *types.BaseSet) string {
+	documents := make([][3]string, len(set.Body.Documents))
+
+	for i, document := range set.Body.Documents {
+		documents[i][0] = document.Type
+
+		if decodedXML, err := util.DecodeEmbeddedXML(document.EmbeddedXML); err == nil {
+			documents[i][1] = audit.ContentHash(decodedXML)
+		}
+		if hash, ok := pdfHash(&document); ok {
+			documents[i][2] = hash
+		}
+	}
+
+	// Marshalling a slice of strings can't fail
+	data, _ := json.Marshal(documents)
+
+	return audit.ContentHash(data)
+}
+
+func pdfHash(document *types.BaseDocument) (string, bool) {
+	if document.EmbeddedPDF == "" {
+		return "", false
+	}
+
+	decodedPDF, err := base64.StdEncoding.DecodeString(document.EmbeddedPDF)
+	if err != nil {
+		return "", false
+	}
+
+	return audit.ContentHash(decodedPDF), true
+}
+
+// Submission is the case a fingerprint was first submitted for, and when.
+type Submission struct {
+	CaseNo      string
+	SubmittedAt time.Time
+}
+
+// FingerprintStore remembers the content that has been submitted, so that
+// repeats can be found whatever their document IDs.
+type FingerprintStore struct {
+	dynamo    *dynamodb.Client
+	tableName string
+	now       func() time.Time
+}
+
+func NewFingerprintStore(dynamo *dynamodb.Client, tableName string) *FingerprintStore {
+	return &FingerprintStore{dynamo: dynamo, tableName: tableName, now: time.Now}
+}
+
+// Find returns the earlier submission of each fingerprint that has been
+// recorded before.
+func (s *FingerprintStore) Find(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error) {
+	var keys []map[string]dynamotypes.AttributeValue
+	seen := map[string]bool{}
+
+	for _, fingerprint := range fingerprints {
+		if key := fingerprint.key(); !seen[key] {
+			seen[key] = true
+			keys = append(keys, map[string]dynamotypes.AttributeValue{
+				"PK": &dynamotypes.AttributeValueMemberS{Value: key},
+				"SK": &dynamotypes.AttributeValueMemberS{Value: key},
+			})
+		}
+	}
+
+	submissions := map[string]Submission{}
+
+	for len(keys) > 0 {
+		batch := keys[:min(len(keys), maxBatchGetKeys)]
+		keys = keys[len(batch):]
+
+		output, err := s.dynamo.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]dynamotypes.KeysAndAttributes{
+				s.tableName: {Keys: batch, ConsistentRead: aws.Bool(true)},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range output.Responses[s.tableName] {
+			var v struct {
+				PK          string
+				CaseNo      string
+				SubmittedAt time.Time
+			}
+			if err := attributevalue.UnmarshalMap(item, &v); err != nil {
+				return nil, err
+			}
+
+			submissions[v.PK] = Submission{CaseNo: v.CaseNo, SubmittedAt: v.SubmittedAt}
+		}
+
+		if unprocessed, ok := output.UnprocessedKeys[s.tableName]; ok {
+			keys = append(keys, unprocessed.Keys...)
+		}
+	}
+
+	found := map[Fingerprint]Submission{}
+	for _, fingerprint := range fingerprints {
+		if submission, ok := submissions[fingerprint.key()]; ok {
+			found[fingerprint] = submission
+		}
+	}
+
+	return found, nil
+}
+
+// Record stores the fingerprints against the case they were submitted for.
+// Fingerprints that have already been recorded keep their first submission.
+func (s *FingerprintStore) Record(ctx context.Context, caseNo string, fingerprints []Fingerprint) error {
+	submittedAt := s.now().UTC()
+
+	for _, fingerprint := range fingerprints {
+		item := map[string]dynamotypes.AttributeValue{
+			"PK":          &dynamotypes.AttributeValueMemberS{Value: fingerprint.key()},
+			"SK":          &dynamotypes.AttributeValueMemberS{Value: fingerprint.key()},
+			"CaseNo":      &dynamotypes.AttributeValueMemberS{Value: caseNo},
+			"SubmittedAt": &dynamotypes.AttributeValueMemberS{Value: submittedAt.Format(time.RFC3339Nano)},
+		}
+
+		if fingerprint.DocumentType != "" {
+			item["DocumentType"] = &dynamotypes.AttributeValueMemberS{Value: fingerprint.DocumentType}
+		}
+
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			item["SubmittedBy"] = &dynamotypes.AttributeValueMemberS{Value: principal.ID}
+		}
+
+		_, err := s.dynamo.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(s.tableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		})
+
+		var condErr *dynamotypes.ConditionalCheckFailedException
+		if err != nil && !errors.As(err, &condErr) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// duplicateIssues describes the fingerprints that were submitted before. The
+// policy gives the severity of duplicates by document type, and a duplicate
+// set takes the most severe for the documents in it.
+func duplicateIssues(set *types.BaseSet, fingerprints []Fingerprint, earlier map[Fingerprint]Submission, policy validation.DuplicatePolicy) []validation.Issue {
+	var issues []validation.Issue
+
+	for _, fingerprint := range fingerprints {
+		submission, ok := earlier[fingerprint]
+		if !ok {
+			continue
+		}
+
+		submitted := fmt.Sprintf("case %s at %s", submission.CaseNo, submission.SubmittedAt.UTC().Format(time.RFC3339))
+
+		issue := validation.Issue{
+			DocumentID:   fingerprint.DocumentID,
+			DocumentType: fingerprint.DocumentType,
+		}
+
+		switch fingerprint.Kind {
+		case FingerprintSet:
+			issue.Code = CodeDuplicateSet
+			issue.Severity = validation.SeverityIgnore
+			for _, document := range set.Body.Documents {
+				issue.Severity = moreSevere(issue.Severity, policy.Severity(document.Type))
+			}
+			issue.Message = "Set was already submitted for " + submitted
+		case FingerprintPDF:
+			issue.Code = CodeDuplicatePDF
+			issue.Severity = policy.Severity(fingerprint.DocumentType)
+			issue.Message = fmt.Sprintf("PDF of %s document was already submitted for %s", fingerprint.DocumentType, submitted)
+		}
+
+		if issue.Severity != validation.SeverityIgnore {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+func moreSevere(a, b validation.Severity) validation.Severity {
+	rank := map[validation.Severity]int{
+		validation.SeverityIgnore:  0,
+		validation.SeverityWarning: 1,
+		validation.SeverityError:   2,
+	}
+
+	if rank[b] > rank[a] {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,134 @@
+package ingestion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+var expectedError = errors.New("err")
+
+func TestSetFingerprints(t *testing.T) {
+	set := &types.BaseSet{}
+	set.Body.Documents = []types.BaseDocument{
+		{ID: "doc-1", Type: "LP1F", EmbeddedPDF: "SGVsbG8gd29ybGQ="},
+		{ID: "doc-2", Type: "LPC"},
+		{ID: "doc-3", Type: "LPC", EmbeddedPDF: "not base64"},
+	}
+
+	assert.Equal(t, []Fingerprint{
+		{Kind: FingerprintSet, Hash: setHash(set)},
+		{Kind: FingerprintPDF, Hash: audit.ContentHash([]byte("Hello world")), DocumentID: "doc-1", DocumentType: "LP1F"},
+	}, setFingerprints(set))
+}
+
+func TestSetHash(t *testing.T) {
+	newSet := func(scanTime string, documents ...types.BaseDocument) *types.BaseSet {
+		set := &types.BaseSet{Header: &types.BaseHeader{ScanTime: scanTime}}
+		set.Body.Documents = documents
+		return set
+	}
+
+	lp1f := types.BaseDocument{ID: "doc-1", Type: "LP1F", EmbeddedXML: "PExQMUYvPg==", EmbeddedPDF: "SGVsbG8gd29ybGQ="}
+	lpc := types.BaseDocument{ID: "doc-2", Type: "LPC", EmbeddedXML: "PExQQy8+", EmbeddedPDF: "SGVsbG8gYWdhaW4="}
+
+	rescannedLP1F := lp1f
+	rescannedLP1F.ID = "doc-3"
+	rescannedLPC := lpc
+	rescannedLPC.ID = "doc-4"
+
+	changedLPC := lpc
+	changedLPC.EmbeddedPDF = "Q2hhbmdlZA=="
+
+	hash := setHash(newSet("2016-09-09 18:50:00", lp1f, lpc))
+
+	assert.Equal(t, hash, setHash(newSet("2026-03-04 12:00:00", rescannedLP1F, rescannedLPC)))
+	assert.NotEqual(t, hash, setHash(newSet("2016-09-09 18:50:00", lpc, lp1f)))
+	assert.NotEqual(t, hash, setHash(newSet("2016-09-09 18:50:00", lp1f, changedLPC)))
+	assert.NotEqual(t, hash, setHash(newSet("2016-09-09 18:50:00", lp1f)))
+}
+
+func TestDuplicateIssues(t *testing.T) {
+	set := &types.BaseSet{}
+	set.Body.Documents = []types.BaseDocument{{Type: "LP1F"}, {Type: "Correspondence"}}
+
+	setFingerprint := Fingerprint{Kind: FingerprintSet, Hash: "a"}
+	pdfFingerprint := Fingerprint{Kind: FingerprintPDF, Hash: "b", DocumentID: "doc-2", DocumentType: "Correspondence"}
+	fingerprints := []Fingerprint{setFingerprint, pdfFingerprint}
+
+	earlier := map[Fingerprint]Submission{
+		setFingerprint: {CaseNo: "7000-0000-0001", SubmittedAt: time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)},
+		pdfFingerprint: {CaseNo: "7000-0000-0002", SubmittedAt: time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)},
+	}
+
+	testCases := map[string]struct {
+		policy     validation.DuplicatePolicy
+		severities []validation.Severity
+	}{
+		"flagged by default": {
+			severities: []validation.Severity{validation.SeverityWarning, validation.SeverityWarning},
+		},
+		"set takes the most severe of its documents": {
+			policy:     validation.DuplicatePolicy{"LP1F": validation.SeverityError},
+			severities: []validation.Severity{validation.SeverityError, validation.SeverityWarning},
+		},
+		"ignored": {
+			policy:     validation.DuplicatePolicy{"LP1F": validation.SeverityIgnore, "Correspondence": validation.SeverityIgnore},
+			severities: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var severities []validation.Severity
+			for _, issue := range duplicateIssues(set, fingerprints, earlier, tc.policy) {
+				severities = append(severities, issue.Severity)
+			}
+
+			assert.Equal(t, tc.severities, severities)
+		})
+	}
+
+	issues := duplicateIssues(set, fingerprints, earlier, nil)
+	assert.Equal(t, []validation.Issue{{
+		Code:     CodeDuplicateSet,
+		Severity: validation.SeverityWarning,
+		Message:  "Set was already submitted for case 7000-0000-0001 at 2026-03-04T12:00:00Z",
+	}, {
+		DocumentID:   "doc-2",
+		DocumentType: "Correspondence",
+		Code:         CodeDuplicatePDF,
+		Severity:     validation.SeverityWarning,
+		Message:      "PDF of Correspondence document was already submitted for case 7000-0000-0002 at 2026-03-05T12:00:00Z",
+	}}, issues)
+}
+
+func TestIntegrationFingerprintStore(t *testing.T) {
+	withDocumentTracker(t, func(tracker *DocumentTracker) {
+		store := NewFingerprintStore(tracker.dynamo, "test")
+
+		first := Fingerprint{Kind: FingerprintSet, Hash: "first"}
+		second := Fingerprint{Kind: FingerprintPDF, Hash: "second", DocumentType: "LP1F"}
+
+		found, err := store.Find(ctx, []Fingerprint{first, second})
+		assert.Nil(t, err)
+		assert.Empty(t, found)
+
+		submittedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+		store.now = func() time.Time { return submittedAt }
+		assert.Nil(t, store.Record(ctx, "my-caseno", []Fingerprint{first}))
+
+		// keeps the first submission
+		store.now = time.Now
+		assert.Nil(t, store.Record(ctx, "my-other-caseno", []Fingerprint{first}))
+
+		found, err = store.Find(ctx, []Fingerprint{first, second})
+		assert.Nil(t, err)
+		assert.Equal(t, map[Fingerprint]Submission{first: {CaseNo: "my-caseno", SubmittedAt: submittedAt}}, found)
+	})
+}
@@ -216,6 +216,164 @@ func (_c *mockDocumentTracker_SetProcessing_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// newMockFingerprintStore creates a new instance of mockFingerprintStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockFingerprintStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockFingerprintStore {
+	mock := &mockFingerprintStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockFingerprintStore is an autogenerated mock type for the fingerprintStore type
+type mockFingerprintStore struct {
+	mock.Mock
+}
+
+type mockFingerprintStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockFingerprintStore) EXPECT() *mockFingerprintStore_Expecter {
+	return &mockFingerprintStore_Expecter{mock: &_m.Mock}
+}
+
+// Find provides a mock function for the type mockFingerprintStore
+func (_mock *mockFingerprintStore) Find(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error) {
+	ret := _mock.Called(ctx, fingerprints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 map[Fingerprint]Submission
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []Fingerprint) (map[Fingerprint]Submission, error)); ok {
+		return returnFunc(ctx, fingerprints)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []Fingerprint) map[Fingerprint]Submission); ok {
+		r0 = returnFunc(ctx, fingerprints)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[Fingerprint]Submission)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []Fingerprint) error); ok {
+		r1 = returnFunc(ctx, fingerprints)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockFingerprintStore_Find_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Find'
+type mockFingerprintStore_Find_Call struct {
+	*mock.Call
+}
+
+// Find is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fingerprints []Fingerprint
+func (_e *mockFingerprintStore_Expecter) Find(ctx interface{}, fingerprints interface{}) *mockFingerprintStore_Find_Call {
+	return &mockFingerprintStore_Find_Call{Call: _e.mock.On("Find", ctx, fingerprints)}
+}
+
+func (_c *mockFingerprintStore_Find_Call) Run(run func(ctx context.Context, fingerprints []Fingerprint)) *mockFingerprintStore_Find_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []Fingerprint
+		if args[1] != nil {
+			arg1 = args[1].([]Fingerprint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockFingerprintStore_Find_Call) Return(fingerprintToSubmission map[Fingerprint]Submission, err error) *mockFingerprintStore_Find_Call {
+	_c.Call.Return(fingerprintToSubmission, err)
+	return _c
+}
+
+func (_c *mockFingerprintStore_Find_Call) RunAndReturn(run func(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error)) *mockFingerprintStore_Find_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function for the type mockFingerprintStore
+func (_mock *mockFingerprintStore) Record(ctx context.Context, caseNo string, fingerprints []Fingerprint) error {
+	ret := _mock.Called(ctx, caseNo, fingerprints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []Fingerprint) error); ok {
+		r0 = returnFunc(ctx, caseNo, fingerprints)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockFingerprintStore_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type mockFingerprintStore_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - caseNo string
+//   - fingerprints []Fingerprint
+func (_e *mockFingerprintStore_Expecter) Record(ctx interface{}, caseNo interface{}, fingerprints interface{}) *mockFingerprintStore_Record_Call {
+	return &mockFingerprintStore_Record_Call{Call: _e.mock.On("Record", ctx, caseNo, fingerprints)}
+}
+
+func (_c *mockFingerprintStore_Record_Call) Run(run func(ctx context.Context, caseNo string, fingerprints []Fingerprint)) *mockFingerprintStore_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []Fingerprint
+		if args[2] != nil {
+			arg2 = args[2].([]Fingerprint)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockFingerprintStore_Record_Call) Return(err error) *mockFingerprintStore_Record_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockFingerprintStore_Record_Call) RunAndReturn(run func(ctx context.Context, caseNo string, fingerprints []Fingerprint) error) *mockFingerprintStore_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockAwsClient creates a new instance of mockAwsClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockAwsClient(t interface {
@@ -243,6 +401,72 @@ func (_m *mockAwsClient) EXPECT() *mockAwsClient_Expecter {
 	return &mockAwsClient_Expecter{mock: &_m.Mock}
 }
 
+// GetSecretValue provides a mock function for the type mockAwsClient
+func (_mock *mockAwsClient) GetSecretValue(ctx context.Context, secretName string) (string, error) {
+	ret := _mock.Called(ctx, secretName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecretValue")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, secretName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, secretName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, secretName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAwsClient_GetSecretValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSecretValue'
+type mockAwsClient_GetSecretValue_Call struct {
+	*mock.Call
+}
+
+// GetSecretValue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - secretName string
+func (_e *mockAwsClient_Expecter) GetSecretValue(ctx interface{}, secretName interface{}) *mockAwsClient_GetSecretValue_Call {
+	return &mockAwsClient_GetSecretValue_Call{Call: _e.mock.On("GetSecretValue", ctx, secretName)}
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) Run(run func(ctx context.Context, secretName string)) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) Return(s string, err error) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockAwsClient_GetSecretValue_Call) RunAndReturn(run func(ctx context.Context, secretName string) (string, error)) *mockAwsClient_GetSecretValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PersistFormData provides a mock function for the type mockAwsClient
 func (_mock *mockAwsClient) PersistFormData(ctx context.Context, body []byte, docType string) (string, error) {
 	ret := _mock.Called(ctx, body, docType)
@@ -315,6 +539,78 @@ func (_c *mockAwsClient_PersistFormData_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// PersistFormExtraction provides a mock function for the type mockAwsClient
+func (_mock *mockAwsClient) PersistFormExtraction(ctx context.Context, body []byte, formFileName string) (string, error) {
+	ret := _mock.Called(ctx, body, formFileName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PersistFormExtraction")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte, string) (string, error)); ok {
+		return returnFunc(ctx, body, formFileName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte, string) string); ok {
+		r0 = returnFunc(ctx, body, formFileName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []byte, string) error); ok {
+		r1 = returnFunc(ctx, body, formFileName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockAwsClient_PersistFormExtraction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PersistFormExtraction'
+type mockAwsClient_PersistFormExtraction_Call struct {
+	*mock.Call
+}
+
+// PersistFormExtraction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - body []byte
+//   - formFileName string
+func (_e *mockAwsClient_Expecter) PersistFormExtraction(ctx interface{}, body interface{}, formFileName interface{}) *mockAwsClient_PersistFormExtraction_Call {
+	return &mockAwsClient_PersistFormExtraction_Call{Call: _e.mock.On("PersistFormExtraction", ctx, body, formFileName)}
+}
+
+func (_c *mockAwsClient_PersistFormExtraction_Call) Run(run func(ctx context.Context, body []byte, formFileName string)) *mockAwsClient_PersistFormExtraction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []byte
+		if args[1] != nil {
+			arg1 = args[1].([]byte)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockAwsClient_PersistFormExtraction_Call) Return(s string, err error) *mockAwsClient_PersistFormExtraction_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockAwsClient_PersistFormExtraction_Call) RunAndReturn(run func(ctx context.Context, body []byte, formFileName string) (string, error)) *mockAwsClient_PersistFormExtraction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PersistSetData provides a mock function for the type mockAwsClient
 func (_mock *mockAwsClient) PersistSetData(ctx context.Context, body []byte) (string, error) {
 	ret := _mock.Called(ctx, body)
@@ -382,8 +678,8 @@ func (_c *mockAwsClient_PersistSetData_Call) RunAndReturn(run func(ctx context.C
 }
 
 // QueueSetForProcessing provides a mock function for the type mockAwsClient
-func (_mock *mockAwsClient) QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string) (string, error) {
-	ret := _mock.Called(ctx, scannedCaseResponse, fileName)
+func (_mock *mockAwsClient) QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string, extractionFileName string) (string, error) {
+	ret := _mock.Called(ctx, scannedCaseResponse, fileName, extractionFileName)
 
 	if len(ret) == 0 {
 		panic("no return value specified for QueueSetForProcessing")
@@ -391,16 +687,16 @@ func (_mock *mockAwsClient) QueueSetForProcessing(ctx context.Context, scannedCa
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *sirius.ScannedCaseResponse, string) (string, error)); ok {
-		return returnFunc(ctx, scannedCaseResponse, fileName)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sirius.ScannedCaseResponse, string, string) (string, error)); ok {
+		return returnFunc(ctx, scannedCaseResponse, fileName, extractionFileName)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *sirius.ScannedCaseResponse, string) string); ok {
-		r0 = returnFunc(ctx, scannedCaseResponse, fileName)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sirius.ScannedCaseResponse, string, string) string); ok {
+		r0 = returnFunc(ctx, scannedCaseResponse, fileName, extractionFileName)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *sirius.ScannedCaseResponse, string) error); ok {
-		r1 = returnFunc(ctx, scannedCaseResponse, fileName)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *sirius.ScannedCaseResponse, string, string) error); ok {
+		r1 = returnFunc(ctx, scannedCaseResponse, fileName, extractionFileName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -416,11 +712,12 @@ type mockAwsClient_QueueSetForProcessing_Call struct {
 //   - ctx context.Context
 //   - scannedCaseResponse *sirius.ScannedCaseResponse
 //   - fileName string
-func (_e *mockAwsClient_Expecter) QueueSetForProcessing(ctx interface{}, scannedCaseResponse interface{}, fileName interface{}) *mockAwsClient_QueueSetForProcessing_Call {
-	return &mockAwsClient_QueueSetForProcessing_Call{Call: _e.mock.On("QueueSetForProcessing", ctx, scannedCaseResponse, fileName)}
+//   - extractionFileName string
+func (_e *mockAwsClient_Expecter) QueueSetForProcessing(ctx interface{}, scannedCaseResponse interface{}, fileName interface{}, extractionFileName interface{}) *mockAwsClient_QueueSetForProcessing_Call {
+	return &mockAwsClient_QueueSetForProcessing_Call{Call: _e.mock.On("QueueSetForProcessing", ctx, scannedCaseResponse, fileName, extractionFileName)}
 }
 
-func (_c *mockAwsClient_QueueSetForProcessing_Call) Run(run func(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string)) *mockAwsClient_QueueSetForProcessing_Call {
+func (_c *mockAwsClient_QueueSetForProcessing_Call) Run(run func(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string, extractionFileName string)) *mockAwsClient_QueueSetForProcessing_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -434,10 +731,15 @@ func (_c *mockAwsClient_QueueSetForProcessing_Call) Run(run func(ctx context.Con
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -448,7 +750,7 @@ func (_c *mockAwsClient_QueueSetForProcessing_Call) Return(s string, err error)
 	return _c
 }
 
-func (_c *mockAwsClient_QueueSetForProcessing_Call) RunAndReturn(run func(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string) (string, error)) *mockAwsClient_QueueSetForProcessing_Call {
+func (_c *mockAwsClient_QueueSetForProcessing_Call) RunAndReturn(run func(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string, extractionFileName string) (string, error)) *mockAwsClient_QueueSetForProcessing_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,152 @@
+package ingestion
+
+/*
+#cgo pkg-config: libxml-2.0
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <libxml/tree.h>
+#include <libxml/xmlerror.h>
+#include <libxml/xmlschemas.h>
+
+#define SCHEMA_ERRORS_SIZE 32
+
+typedef struct {
+	char *messages[SCHEMA_ERRORS_SIZE];
+	char *paths[SCHEMA_ERRORS_SIZE];
+	int count;
+} schema_errors;
+
+// The error is taken as void * because newer versions of libxml2 pass it as
+// const, which would otherwise not match the handler type.
+static void collect_schema_error(void *ctx, void *e) {
+	schema_errors *errs = (schema_errors *) ctx;
+	const xmlError *err = (const xmlError *) e;
+	xmlChar *path = NULL;
+
+	if (errs->count >= SCHEMA_ERRORS_SIZE) {
+		return;
+	}
+
+	if (err->node != NULL) {
+		path = xmlGetNodePath((xmlNodePtr) err->node);
+	}
+
+	errs->messages[errs->count] = strdup(err->message != NULL ? err->message : "");
+	errs->paths[errs->count] = strdup(path != NULL ? (char *) path : "");
+	errs->count++;
+
+	if (path != NULL) {
+		xmlFree(path);
+	}
+}
+
+static int validate_schema(uintptr_t schema, uintptr_t doc, schema_errors *errs) {
+	xmlSchemaValidCtxtPtr ctxt = xmlSchemaNewValidCtxt((xmlSchemaPtr) schema);
+	int ret;
+
+	if (ctxt == NULL) {
+		return -1;
+	}
+
+	xmlSchemaSetValidStructuredErrors(ctxt, (xmlStructuredErrorFunc) collect_schema_error, errs);
+	ret = xmlSchemaValidateDoc(ctxt, (xmlDocPtr) doc);
+	xmlSchemaFreeValidCtxt(ctxt);
+
+	return ret;
+}
+
+static void free_schema_errors(schema_errors *errs) {
+	for (int i = 0; i < errs->count; i++) {
+		free(errs->messages[i]);
+		free(errs->paths[i]);
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lestrrat-go/libxml2/types"
+	"github.com/lestrrat-go/libxml2/xsd"
+)
+
+// SchemaError is a single error found by XSD validation, with the path of the
+// node it was raised against.
+type SchemaError struct {
+	Message string
+	Path    string
+}
+
+// SchemaValidationError is returned when a document does not match its schema.
+type SchemaValidationError struct {
+	errors []SchemaError
+}
+
+func (e SchemaValidationError) Error() string {
+	return "schema validation failed"
+}
+
+func (e SchemaValidationError) Errors() []SchemaError {
+	return e.errors
+}
+
+// validateSchema validates the document like xsd.Schema.Validate, but keeps the
+// node each error was raised against, which the binding discards.
+func validateSchema(schema *xsd.Schema, doc types.Document) error {
+	if schema.Pointer() == 0 || doc.Pointer() == 0 {
+		return errors.New("failed to validate schema: schema or document not loaded")
+	}
+
+	var errs C.schema_errors
+	defer C.free_schema_errors(&errs)
+
+	ret := C.validate_schema(C.uintptr_t(schema.Pointer()), C.uintptr_t(doc.Pointer()), &errs)
+	if ret == 0 {
+		return nil
+	}
+	if ret < 0 && errs.count == 0 {
+		return fmt.Errorf("failed to validate schema: libxml2 returned %d", int(ret))
+	}
+
+	validationErr := SchemaValidationError{errors: make([]SchemaError, int(errs.count))}
+	for i := range validationErr.errors {
+		validationErr.errors[i] = SchemaError{
+			Message: strings.TrimRight(C.GoString(errs.messages[i]), "\n"),
+			Path:    C.GoString(errs.paths[i]),
+		}
+	}
+
+	return validationErr
+}
+
+// schemaField converts a libxml2 node path, such as
+// "/LP1F/Page12[2]/Section11/Attorney/Date", into the form validators use for
+// fields, such as "Page12[1].Section11.Attorney.Date". The root element is
+// dropped unless the error is on the root itself, and indices are made 0-based.
+func schemaField(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	for i, segment := range segments {
+		name, index, ok := strings.Cut(segment, "[")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+		if err != nil || n < 1 {
+			continue
+		}
+
+		segments[i] = fmt.Sprintf("%s[%d]", name, n-1)
+	}
+
+	return strings.Join(segments, ".")
+}
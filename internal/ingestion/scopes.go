@@ -0,0 +1,39 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+// CodeDocumentTypeNotPermitted is reported for a document of a type the client
+// submitting the set has not been granted.
+const CodeDocumentTypeNotPermitted = "document-type-not-permitted"
+
+// permissionIssues reports each document in a set that the client submitting
+// it may not submit. Sets checked outside of a request, such as by scanctl,
+// are not restricted.
+func permissionIssues(ctx context.Context, set *types.BaseSet) []validation.Issue {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var issues []validation.Issue
+	for _, document := range set.Body.Documents {
+		if !principal.CanSubmit(document.Type) {
+			issues = append(issues, validation.Issue{
+				DocumentID:   document.ID,
+				DocumentType: document.Type,
+				Code:         CodeDocumentTypeNotPermitted,
+				Severity:     validation.SeverityError,
+				Message:      fmt.Sprintf("%s is not permitted to submit %s documents", principal.ID, document.Type),
+			})
+		}
+	}
+
+	return issues
+}
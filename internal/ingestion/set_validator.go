@@ -5,40 +5,68 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
+// CodeSetInvalid is the code for issues with the structure of a set.
+const CodeSetInvalid = "set-invalid"
+
 type Validator struct {
+	policy validation.Policy
 }
 
-func NewValidator() *Validator {
-	return &Validator{}
+func NewValidator(policy validation.Policy) *Validator {
+	return &Validator{policy: policy}
 }
 
+// ValidateSet returns the first problem found with the structure of a set.
 func (v *Validator) ValidateSet(parsedSet *types.BaseSet) error {
+	if issues := v.SetIssues(parsedSet); len(issues) > 0 {
+		return errors.New(issues[0].Message)
+	}
+
+	return nil
+}
+
+// SetIssues returns every problem found with the structure of a set.
+func (v *Validator) SetIssues(parsedSet *types.BaseSet) []validation.Issue {
+	var issues []validation.Issue
+	addIssue := func(documentID, documentType, message string) {
+		issues = append(issues, validation.Issue{
+			DocumentID:   documentID,
+			DocumentType: documentType,
+			Code:         CodeSetInvalid,
+			Severity:     validation.SeverityError,
+			Message:      message,
+		})
+	}
+
 	if parsedSet == nil {
-		return errors.New("parsedSet is nil")
+		addIssue("", "", "parsedSet is nil")
+		return issues
 	}
 
 	// Validate Header fields
 	if parsedSet.Header == nil {
-		return errors.New("missing required Header element")
+		addIssue("", "", "missing required Header element")
+		return issues
 	}
 
 	if parsedSet.Header.Schedule == "" {
-		return errors.New("missing required Schedule attribute on Header")
+		addIssue("", "", "missing required Schedule attribute on Header")
 	}
 
 	// Validate Body and Documents
 	if len(parsedSet.Body.Documents) == 0 {
-		return errors.New("no Document elements found in Body")
+		addIssue("", "", "no Document elements found in Body")
 	}
 
 	for _, doc := range parsedSet.Body.Documents {
 		if doc.Type == "" {
-			return errors.New("document Type attribute is missing")
+			addIssue(doc.ID, doc.Type, "document Type attribute is missing")
 		}
 		if doc.NoPages <= 0 {
-			return errors.New("document NoPages attribute is missing or invalid")
+			addIssue(doc.ID, doc.Type, "document NoPages attribute is missing or invalid")
 		}
 	}
 
@@ -47,22 +75,22 @@ func (v *Validator) ValidateSet(parsedSet *types.BaseSet) error {
 	newCaseDocuments := v.getEmbeddedDocumentTypes(parsedSet, createCaseDocumentTypes)
 
 	if len(newCaseDocuments) > 1 {
-		return errors.New("set cannot contain multiple cases which would create a case")
+		addIssue("", "", "set cannot contain multiple cases which would create a case")
 	}
 
 	if len(newCaseDocuments) > 0 {
 		// Sets that create new cases must not have a case number
 		if parsedSet.Header.CaseNo != "" {
-			return errors.New("must not supply a case number when creating a new case")
+			addIssue("", "", "must not supply a case number when creating a new case")
 		}
 	} else {
 		// Sets that don't create new cases must have a case number
 		if parsedSet.Header.CaseNo == "" {
-			return errors.New("must supply a case number when not creating a new case")
+			addIssue("", "", "must supply a case number when not creating a new case")
 		}
 	}
 
-	return nil
+	return issues
 }
 
 func (v *Validator) getEmbeddedDocumentTypes(parsedSet *types.BaseSet, validTypes []string) []string {
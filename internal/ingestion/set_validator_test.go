@@ -165,7 +165,7 @@ func TestSetValidatorValidationRules(t *testing.T) {
 		},
 	}
 
-	v := NewValidator()
+	v := NewValidator(nil)
 
 	for _, tc := range tests {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -8,14 +8,17 @@ import (
 	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/lestrrat-go/libxml2/xsd"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
 	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
 	"github.com/ministryofjustice/opg-scanning/internal/factory"
 	"github.com/ministryofjustice/opg-scanning/internal/logger"
 	"github.com/ministryofjustice/opg-scanning/internal/sirius"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
 	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type documentTracker interface {
@@ -24,10 +27,17 @@ type documentTracker interface {
 	SetFailed(ctx context.Context, id string) error
 }
 
+type fingerprintStore interface {
+	Find(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error)
+	Record(ctx context.Context, caseNo string, fingerprints []Fingerprint) error
+}
+
 type AwsClient interface {
 	PersistFormData(ctx context.Context, body []byte, docType string) (string, error)
+	PersistFormExtraction(ctx context.Context, body []byte, formFileName string) (string, error)
 	PersistSetData(ctx context.Context, body []byte) (string, error)
-	QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName string) (string, error)
+	QueueSetForProcessing(ctx context.Context, scannedCaseResponse *sirius.ScannedCaseResponse, fileName, extractionFileName string) (string, error)
+	GetSecretValue(ctx context.Context, secretName string) (string, error)
 }
 
 type SiriusService interface {
@@ -41,26 +51,82 @@ type Worker struct {
 	siriusService   SiriusService
 	awsClient       AwsClient
 	documentTracker documentTracker
+	fingerprints    fingerprintStore
 	validator       *Validator
+	duplicatePolicy validation.DuplicatePolicy
+	audit           *audit.Log
 }
 
-func NewWorker(logger *slog.Logger, config *config.Config, awsClient AwsClient, dynamoClient *dynamodb.Client) *Worker {
-	return &Worker{
+func NewWorker(logger *slog.Logger, config *config.Config, awsClient AwsClient, dynamoClient *dynamodb.Client, auditLog *audit.Log) *Worker {
+	worker := &Worker{
 		logger:          logger,
 		config:          config,
-		siriusService:   sirius.NewService(config),
+		siriusService:   sirius.NewService(config, auth.NewServiceTokens(config, awsClient)),
 		awsClient:       awsClient,
 		documentTracker: NewDocumentTracker(dynamoClient, config.Aws.DocumentsTable),
-		validator:       NewValidator(),
+		validator:       NewValidator(config.App.ValidationPolicy),
+		duplicatePolicy: config.App.DuplicatePolicy,
+		audit:           auditLog,
+	}
+
+	// Sets checked offline, as by scanctl, have no earlier submissions to
+	// look for.
+	if dynamoClient != nil {
+		worker.fingerprints = NewFingerprintStore(dynamoClient, config.Aws.DocumentsTable)
+	}
+
+	return worker
+}
+
+// Result is the outcome of processing a set. Issues lists the problems found
+// that didn't stop it being processed, such as warnings from the cross-document
+// checks and the findings of each form's validator.
+type Result struct {
+	UID    string
+	Issues []validation.Issue
+}
+
+// Process stores a set, creates its case stub and attaches its documents,
+// recording the outcome of each step in the audit log. A Result is returned
+// once the case stub has been created, even if a later step fails.
+func (w *Worker) Process(ctx context.Context, body []byte) (*Result, error) {
+	submission := audit.Event{
+		Action:  audit.ActionSubmitSet,
+		Hashes:  map[string]string{"set": audit.ContentHash(body)},
+		Outcome: audit.OutcomeSuccess,
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		submission.Principal = principal.ID
+	}
+
+	result, err := w.process(ctx, body, &submission)
+	if result != nil {
+		submission.CaseUID = result.UID
+	}
+	if err != nil {
+		submission.Outcome = audit.OutcomeFailure
+		submission.Detail = err.Error()
+
+		var alreadyProcessed AlreadyProcessedError
+		if errors.As(err, &alreadyProcessed) {
+			submission.CaseUID = alreadyProcessed.CaseNo
+		}
 	}
+
+	w.audit.Record(ctx, submission)
+	return result, err
 }
 
-func (w *Worker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseResponse, error) {
+// process does the work of Process. The submission is given the ID of the
+// stored set, which the audit entries for each step also refer to.
+func (w *Worker) process(ctx context.Context, body []byte, submission *audit.Event) (*Result, error) {
 	filename, err := w.awsClient.PersistSetData(ctx, body)
 	if err != nil {
 		return nil, PersistSetError{Err: err}
 	}
 
+	submission.SetID = filename
+
 	w.logger.InfoContext(ctx, "Stored Set data", slog.String("set_filename", filename))
 
 	set, err := w.validateAndSanitizeXML(ctx, body)
@@ -68,13 +134,90 @@ func (w *Worker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseR
 		return nil, ValidateAndSanitizeError{Err: err}
 	}
 
+	if issues := permissionIssues(ctx, set); len(issues) > 0 {
+		return nil, ForbiddenError{Err: newProblem("Set contains documents the client may not submit", issues)}
+	}
+
 	if err := w.validator.ValidateSet(set); err != nil {
 		return nil, ValidateSetError{Err: err}
 	}
 
+	parsed, err := w.parseDocuments(ctx, set)
+	if err != nil {
+		return nil, err
+	}
+
+	links := linkContinuations(set, parsed)
+	if links != nil {
+		if len(links.report.Unmatched) > 0 {
+			w.logger.WarnContext(ctx, "Continuation sheets do not match an instrument in the set", slog.Any("documents", links.report.Unmatched))
+		}
+		if len(links.report.Surplus) > 0 {
+			w.logger.WarnContext(ctx, "Continuation sheets do not continue anything", slog.Any("documents", links.report.Surplus))
+		}
+		if len(links.report.Ambiguous) > 0 {
+			w.logger.WarnContext(ctx, "Continuation sheets could continue more than one instrument in the set", slog.Any("documents", links.report.Ambiguous))
+		}
+	}
+
+	issues := w.validator.CrossCheckSet(set, parsed, links)
+
+	for _, issue := range validation.Warnings(issues) {
+		w.logger.WarnContext(ctx, issue.Message,
+			slog.String("code", issue.Code),
+			slog.String("document_id", issue.DocumentID),
+			slog.String("document_type", issue.DocumentType),
+		)
+	}
+
+	if errs := validation.Errors(issues); len(errs) > 0 {
+		return nil, ValidateSetError{Err: newProblem("Set failed cross-document checks", errs)}
+	}
+
+	fingerprints := setFingerprints(set)
+
+	earlier, err := w.fingerprints.Find(ctx, fingerprints)
+	if err != nil {
+		return nil, FingerprintLookupError{Err: fmt.Errorf("failed to find earlier submissions: %w", err)}
+	}
+
+	duplicates := duplicateIssues(set, fingerprints, earlier, w.duplicatePolicy)
+
+	for _, issue := range validation.Warnings(duplicates) {
+		w.logger.WarnContext(ctx, issue.Message,
+			slog.String("code", issue.Code),
+			slog.String("document_id", issue.DocumentID),
+			slog.String("document_type", issue.DocumentType),
+		)
+	}
+
+	if errs := validation.Errors(duplicates); len(errs) > 0 {
+		return nil, DuplicateError{Err: newProblem("Set has already been submitted", errs)}
+	}
+
 	scannedCaseResponse, err := w.createCaseStub(ctx, set)
+
+	stub := audit.Event{
+		Action:    audit.ActionCreateCaseStub,
+		Principal: submission.Principal,
+		SetID:     submission.SetID,
+		Outcome:   audit.OutcomeSuccess,
+	}
+	if err != nil {
+		stub.Outcome = audit.OutcomeFailure
+		stub.Detail = err.Error()
+	} else {
+		stub.CaseUID = scannedCaseResponse.UID
+	}
+	w.audit.Record(ctx, stub)
+
 	if err != nil {
-		return scannedCaseResponse, err
+		return nil, err
+	}
+
+	result := &Result{
+		UID:    scannedCaseResponse.UID,
+		Issues: append(validation.Warnings(issues), validation.Warnings(duplicates)...),
 	}
 
 	w.logger.InfoContext(ctx, "Queueing documents for processing", slog.Any("Header", set.Header))
@@ -90,10 +233,12 @@ func (w *Worker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseR
 		)
 
 		if err := w.documentTracker.SetProcessing(ctx, doc.ID, scannedCaseResponse.UID); err != nil {
-			return scannedCaseResponse, fmt.Errorf("failed to set document to processing '%s': %w", doc.ID, err)
+			return result, fmt.Errorf("failed to set document to processing '%s': %w", doc.ID, err)
 		}
 
-		if err := w.processDocument(ctx, set, doc, scannedCaseResponse); err != nil {
+		result.Issues = append(result.Issues, parsed[doc].issues...)
+
+		if err := w.processDocument(ctx, set, doc, parsed[doc], scannedCaseResponse, links, *submission); err != nil {
 			if err := w.documentTracker.SetFailed(ctx, doc.ID); err != nil {
 				w.logger.ErrorContext(ctx, err.Error())
 			}
@@ -102,7 +247,7 @@ func (w *Worker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseR
 				w.logger.ErrorContext(ctx, err.Error())
 			}
 
-			return scannedCaseResponse, err
+			return result, err
 		}
 
 		if err := w.documentTracker.SetCompleted(ctx, doc.ID); err != nil {
@@ -112,8 +257,14 @@ func (w *Worker) Process(ctx context.Context, body []byte) (*sirius.ScannedCaseR
 		w.logger.InfoContext(ctx, "Document added for processing")
 	}
 
+	// Only recorded once every document is attached, so that a set which
+	// failed part way through can be sent again.
+	if err := w.fingerprints.Record(ctx, scannedCaseResponse.UID, fingerprints); err != nil {
+		w.logger.ErrorContext(ctx, "Failed to record fingerprints", slog.Any("error", err))
+	}
+
 	w.logger.InfoContext(ctx, "No errors found!")
-	return scannedCaseResponse, nil
+	return result, nil
 }
 
 func (w *Worker) createCaseStub(ctx context.Context, set *types.BaseSet) (*sirius.ScannedCaseResponse, error) {
@@ -132,27 +283,81 @@ func (w *Worker) createCaseStub(ctx context.Context, set *types.BaseSet) (*siriu
 	return scannedCaseResponse, nil
 }
 
-func (w *Worker) processDocument(ctx context.Context, set *types.BaseSet, document *types.BaseDocument, scannedCaseResponse *sirius.ScannedCaseResponse) error {
-	ctx, cancel := context.WithTimeout(ctx, w.config.HTTP.Timeout)
-	defer cancel()
-
-	ctx = context.WithValue(ctx, constants.TokenContextKey, ctx.Value(constants.TokenContextKey))
+// checkedDocument is a document that has been parsed and checked by its form
+// validator.
+type checkedDocument struct {
+	data   any
+	issues []validation.Issue
+}
 
+// parseDocuments parses every document in a set before anything is sent to
+// Sirius. A document that can't be parsed rejects the set with the same
+// issue Validate reports for it.
+func (w *Worker) parseDocuments(ctx context.Context, set *types.BaseSet) (map[*types.BaseDocument]*checkedDocument, error) {
 	registry, err := factory.NewRegistry()
 	if err != nil {
-		return fmt.Errorf("failed to create registry: %v", err)
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	parsed := make(map[*types.BaseDocument]*checkedDocument, len(set.Body.Documents))
+	var issues []validation.Issue
+
+	for i := range set.Body.Documents {
+		document := &set.Body.Documents[i]
+
+		doc, err := w.parseDocument(ctx, registry, document)
+		if err != nil {
+			issues = append(issues, unreadable(CodeDocumentUnreadable, document, err))
+			continue
+		}
+
+		parsed[document] = doc
+	}
+
+	if len(issues) > 0 {
+		return nil, ValidateSetError{Err: newProblem("Set contains documents that could not be parsed", issues)}
 	}
 
+	return parsed, nil
+}
+
+// parseDocument parses a document and runs its form validator. The validator's
+// findings don't stop the document being processed, so are returned with it.
+func (w *Worker) parseDocument(ctx context.Context, registry *factory.Registry, document *types.BaseDocument) (*checkedDocument, error) {
 	processor, err := factory.NewDocumentProcessor(document, document.Type, registry, w.logger)
 	if err != nil {
-		return fmt.Errorf("failed to initialize processor: %v", err)
+		return nil, err
 	}
 
-	if _, err := processor.Process(ctx); err != nil {
-		return fmt.Errorf("failed to process job: %v", err)
+	data, err := processor.Process(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	return &checkedDocument{data: data, issues: processor.Issues()}, nil
+}
+
+func (w *Worker) processDocument(ctx context.Context, set *types.BaseSet, document *types.BaseDocument, parsed *checkedDocument, scannedCaseResponse *sirius.ScannedCaseResponse, links *continuationLinks, submission audit.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, w.config.HTTP.Timeout)
+	defer cancel()
+
 	attchResp, decodedXML, docErr := w.siriusService.AttachDocuments(ctx, set, document, scannedCaseResponse)
+
+	attachment := audit.Event{
+		Action:     audit.ActionAttachDocument,
+		Principal:  submission.Principal,
+		SetID:      submission.SetID,
+		DocumentID: document.ID,
+		CaseUID:    scannedCaseResponse.UID,
+		Hashes:     documentHashes(document),
+		Outcome:    audit.OutcomeSuccess,
+	}
+	if docErr != nil {
+		attachment.Outcome = audit.OutcomeFailure
+		attachment.Detail = docErr.Error()
+	}
+	w.audit.Record(ctx, attachment)
+
 	if docErr != nil {
 		return fmt.Errorf("failed to attach document: %w", docErr)
 	}
@@ -174,8 +379,15 @@ func (w *Worker) processDocument(ctx context.Context, set *types.BaseSet, docume
 
 	w.logger.InfoContext(ctx, "Stored Form data", slog.String("filename", fileName))
 
+	extractionFileName, err := w.persistExtraction(ctx, fileName, document, parsed.data, links)
+	if err != nil {
+		return fmt.Errorf("failed to persist extraction: %w", err)
+	}
+
+	w.logger.InfoContext(ctx, "Stored Form extraction", slog.String("extraction_filename", extractionFileName))
+
 	// Queue the document for external processing.
-	messageID, err := w.awsClient.QueueSetForProcessing(ctx, scannedCaseResponse, fileName)
+	messageID, err := w.awsClient.QueueSetForProcessing(ctx, scannedCaseResponse, fileName, extractionFileName)
 	if err != nil {
 		w.logger.ErrorContext(ctx, "Failed to queue document for processing",
 			slog.String("error", err.Error()),
@@ -192,6 +404,21 @@ func (w *Worker) processDocument(ctx context.Context, set *types.BaseSet, docume
 	return nil
 }
 
+// documentHashes returns the hashes of a document's decoded XML and PDF, for
+// the audit log.
+func documentHashes(document *types.BaseDocument) map[string]string {
+	hashes := map[string]string{}
+
+	if decodedXML, err := util.DecodeEmbeddedXML(document.EmbeddedXML); err == nil {
+		hashes["xml"] = audit.ContentHash(decodedXML)
+	}
+	if hash, ok := pdfHash(document); ok {
+		hashes["pdf"] = hash
+	}
+
+	return hashes
+}
+
 func (w *Worker) persist(ctx context.Context, decodedXML []byte, originalDoc *types.BaseDocument) (string, error) {
 	fileName, err := w.awsClient.PersistFormData(ctx, decodedXML, originalDoc.Type)
 	if err != nil {
@@ -201,6 +428,22 @@ func (w *Worker) persist(ctx context.Context, decodedXML []byte, originalDoc *ty
 	return fileName, nil
 }
 
+func (w *Worker) persistExtraction(ctx context.Context, formFileName string, originalDoc *types.BaseDocument, parsedDoc any, links *continuationLinks) (string, error) {
+	doc, err := extraction.New(originalDoc.Type, originalDoc.ID, parsedDoc)
+	if err != nil {
+		return "", err
+	}
+
+	links.apply(originalDoc, doc)
+
+	body, err := doc.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	return w.awsClient.PersistFormExtraction(ctx, body, formFileName)
+}
+
 func (w *Worker) validateAndSanitizeXML(ctx context.Context, body []byte) (*types.BaseSet, error) {
 	schemaLocation, err := ExtractSchemaLocation(body)
 	if err != nil {
@@ -214,15 +457,8 @@ func (w *Worker) validateAndSanitizeXML(ctx context.Context, body []byte) (*type
 		return nil, err
 	}
 	if err := xsdValidator.ValidateXsd(); err != nil {
-		if schemaValidationError, ok := err.(xsd.SchemaValidationError); ok {
-			var validationErrors []string
-			for _, error := range schemaValidationError.Errors() {
-				validationErrors = append(validationErrors, error.Error())
-			}
-			return nil, Problem{
-				Title:            "Validate and sanitize XML failed",
-				ValidationErrors: validationErrors,
-			}
+		if schemaValidationError, ok := err.(SchemaValidationError); ok {
+			return nil, newProblem("Validate and sanitize XML failed", schemaIssues(schemaValidationError, nil))
 		}
 		return nil, fmt.Errorf("set failed XSD validation: %w", err)
 	}
@@ -268,15 +504,8 @@ func (w *Worker) validateDocument(document types.BaseDocument) error {
 	}
 
 	if err := xsdValidator.ValidateXsd(); err != nil {
-		if schemaValidationError, ok := err.(xsd.SchemaValidationError); ok {
-			var validationErrors []string
-			for _, error := range schemaValidationError.Errors() {
-				validationErrors = append(validationErrors, error.Error())
-			}
-			return Problem{
-				Title:            fmt.Sprintf("XML for %s failed XSD validation", document.Type),
-				ValidationErrors: validationErrors,
-			}
+		if schemaValidationError, ok := err.(SchemaValidationError); ok {
+			return newProblem(fmt.Sprintf("XML for %s failed XSD validation", document.Type), schemaIssues(schemaValidationError, &document))
 		}
 		return fmt.Errorf("failed XSD validation: %w", err)
 	}
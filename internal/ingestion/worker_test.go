@@ -3,14 +3,23 @@ package ingestion
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"encoding/xml"
 	"log/slog"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
+	"github.com/ministryofjustice/opg-scanning/internal/auth"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/extraction"
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/sirius"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
 	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -84,6 +93,41 @@ func TestWorkerProcess_InvalidXML(t *testing.T) {
 	assert.ErrorAs(t, err, &verr)
 }
 
+func TestWorkerProcess_DocumentTypeNotPermitted(t *testing.T) {
+	awsClient := newMockAwsClient(t)
+	awsClient.EXPECT().
+		PersistSetData(mock.Anything, mock.Anything).
+		Return("filename", nil)
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:    slog.New(slog.DiscardHandler),
+		config:    config,
+		awsClient: awsClient,
+	}
+
+	ctx := auth.ContextWithPrincipal(context.Background(), auth.Principal{
+		ID:     "cop@example.com",
+		Scopes: []string{auth.ScopeSubmit, auth.DocumentScope("COPORD")},
+	})
+
+	_, err := worker.Process(ctx, []byte(xmlPayload))
+
+	var ferr ForbiddenError
+	assert.ErrorAs(t, err, &ferr)
+
+	var perr Problem
+	if assert.ErrorAs(t, ferr, &perr) {
+		assert.Equal(t, []validation.Issue{{
+			DocumentType: "LP2",
+			Code:         CodeDocumentTypeNotPermitted,
+			Severity:     validation.SeverityError,
+			Message:      "cop@example.com is not permitted to submit LP2 documents",
+		}}, perr.Issues)
+	}
+}
+
 func TestWorkerProcess_InvalidXMLExplainsXSDErrors(t *testing.T) {
 	xmlPayloadMalformed := `<Set xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="SET.xsd">
 		<Header CaseNo="1234"></Header>
@@ -109,6 +153,12 @@ func TestWorkerProcess_InvalidXMLExplainsXSDErrors(t *testing.T) {
 	var perr Problem
 	if assert.ErrorAs(t, verr, &perr) {
 		assert.Equal(t, []string{"Element 'Set': Missing child element(s). Expected is ( Body )."}, perr.ValidationErrors)
+		assert.Equal(t, []validation.Issue{{
+			Field:    "Set",
+			Code:     CodeSchemaInvalid,
+			Severity: validation.SeverityError,
+			Message:  "Element 'Set': Missing child element(s). Expected is ( Body ).",
+		}}, perr.Issues)
 	}
 }
 
@@ -143,6 +193,46 @@ func TestWorkerProcess_InvalidEmbeddedXMLProvidesDetails(t *testing.T) {
 	var perr Problem
 	if assert.ErrorAs(t, verr, &perr) {
 		assert.Equal(t, []string{"Element 'LP2': Missing child element(s). Expected is ( Page1 )."}, perr.ValidationErrors)
+		if assert.Len(t, perr.Issues, 1) {
+			assert.Equal(t, "LP1F", perr.Issues[0].DocumentType)
+			assert.Equal(t, "LP2", perr.Issues[0].Field)
+		}
+	}
+}
+
+func TestWorkerProcess_RejectsUnparseableDocumentsBeforeCreatingCase(t *testing.T) {
+	// The LP2 matches its own schema, but can't be parsed as an LP1F.
+	payload := strings.Replace(xmlPayload, `Type="LP2"`, `Type="LP1F"`, 1)
+
+	awsClient := newMockAwsClient(t)
+	awsClient.EXPECT().
+		PersistSetData(mock.Anything, mock.Anything).
+		Return("filename", nil)
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		awsClient:     awsClient,
+		siriusService: newMockSiriusService(t),
+		validator:     NewValidator(nil),
+	}
+
+	_, err := worker.Process(context.Background(), []byte(payload))
+
+	var serr ValidateSetError
+	assert.ErrorAs(t, err, &serr)
+
+	var perr Problem
+	if assert.ErrorAs(t, serr, &perr) {
+		assert.Equal(t, "Set contains documents that could not be parsed", perr.Title)
+		assert.Equal(t, []validation.Issue{{
+			DocumentType: "LP1F",
+			Code:         CodeDocumentUnreadable,
+			Severity:     validation.SeverityError,
+			Message:      "failed to parse document: expected element type <LP1F> but have <LP2>",
+		}}, perr.Issues)
 	}
 }
 
@@ -199,7 +289,7 @@ func TestValidateDocumentHandlesErrorCases(t *testing.T) {
 
 	worker := &Worker{
 		config:    config,
-		validator: NewValidator(),
+		validator: NewValidator(nil),
 	}
 
 	for _, tc := range testCases {
@@ -221,3 +311,153 @@ func TestValidateDocumentHandlesErrorCases(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkerProcessDocument_PersistsExtraction(t *testing.T) {
+	var set types.BaseSet
+	require.NoError(t, xml.Unmarshal([]byte(xmlPayload), &set))
+	document := &set.Body.Documents[0]
+	document.ID = "lp2"
+
+	decodedXML, err := util.DecodeEmbeddedXML(document.EmbeddedXML)
+	require.NoError(t, err)
+
+	// Leave the LP2 without a sub-type, so its validator finds an issue.
+	decodedXML = []byte(strings.Replace(string(decodedXML), "<HealthWelfare>1</HealthWelfare>", "<HealthWelfare>0</HealthWelfare>", 1))
+	document.EmbeddedXML = base64.StdEncoding.EncodeToString(decodedXML)
+
+	caseResponse := &sirius.ScannedCaseResponse{UID: "700000000001"}
+
+	siriusService := newMockSiriusService(t)
+	siriusService.EXPECT().
+		AttachDocuments(mock.Anything, &set, document, caseResponse).
+		Return(&sirius.ScannedDocumentResponse{UUID: "pdf-uuid"}, decodedXML, nil)
+
+	var extractionBody []byte
+
+	awsClient := newMockAwsClient(t)
+	awsClient.EXPECT().
+		PersistFormData(mock.Anything, decodedXML, "LP2").
+		Return("FORM_DDC_1_LP2.xml", nil)
+	awsClient.EXPECT().
+		PersistFormExtraction(mock.Anything, mock.Anything, "FORM_DDC_1_LP2.xml").
+		Run(func(ctx context.Context, body []byte, formFileName string) {
+			extractionBody = body
+		}).
+		Return("FORM_DDC_1_LP2.json", nil)
+	awsClient.EXPECT().
+		QueueSetForProcessing(mock.Anything, caseResponse, "FORM_DDC_1_LP2.xml", "FORM_DDC_1_LP2.json").
+		Return("message-id", nil)
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		siriusService: siriusService,
+		awsClient:     awsClient,
+	}
+
+	parsed, err := worker.parseDocuments(context.Background(), &set)
+	require.NoError(t, err)
+	assert.Equal(t, []validation.Issue{{
+		DocumentID:   "lp2",
+		DocumentType: "LP2",
+		Field:        "Page1.Section1",
+		Code:         parser.CodeSelectionInvalid,
+		Severity:     validation.SeverityWarning,
+		Message:      "Neither LPA sub-type is selected",
+	}}, parsed[document].issues)
+
+	err = worker.processDocument(context.Background(), &set, document, parsed[document], caseResponse, nil, audit.Event{})
+	require.NoError(t, err)
+
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(extractionBody, &v))
+	assert.Equal(t, extraction.SchemaVersion, v["schemaVersion"])
+	assert.Equal(t, "LP2", v["documentType"])
+	assert.Contains(t, v["data"], "page1")
+}
+
+func TestWorkerProcess_RejectsDuplicates(t *testing.T) {
+	submittedAt := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	awsClient := newMockAwsClient(t)
+	awsClient.EXPECT().
+		PersistSetData(mock.Anything, mock.Anything).
+		Return("filename", nil)
+
+	fingerprints := newMockFingerprintStore(t)
+	fingerprints.EXPECT().
+		Find(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error) {
+			return map[Fingerprint]Submission{
+				fingerprints[0]: {CaseNo: "7000-0000-0001", SubmittedAt: submittedAt},
+				fingerprints[1]: {CaseNo: "7000-0000-0002", SubmittedAt: submittedAt},
+			}, nil
+		})
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:          slog.New(slog.DiscardHandler),
+		config:          config,
+		awsClient:       awsClient,
+		fingerprints:    fingerprints,
+		validator:       NewValidator(nil),
+		duplicatePolicy: validation.DuplicatePolicy{"LP2": validation.SeverityError},
+	}
+
+	_, err := worker.Process(context.Background(), []byte(xmlPayload))
+
+	var derr DuplicateError
+	assert.ErrorAs(t, err, &derr)
+
+	var perr Problem
+	if assert.ErrorAs(t, derr, &perr) {
+		assert.Equal(t, []validation.Issue{{
+			Code:     CodeDuplicateSet,
+			Severity: validation.SeverityError,
+			Message:  "Set was already submitted for case 7000-0000-0001 at 2026-03-04T12:00:00Z",
+		}, {
+			DocumentType: "LP2",
+			Code:         CodeDuplicatePDF,
+			Severity:     validation.SeverityError,
+			Message:      "PDF of LP2 document was already submitted for case 7000-0000-0002 at 2026-03-04T12:00:00Z",
+		}}, perr.Issues)
+	}
+}
+
+func TestWorkerProcess_FlagsDuplicates(t *testing.T) {
+	awsClient := newMockAwsClient(t)
+	awsClient.EXPECT().
+		PersistSetData(mock.Anything, mock.Anything).
+		Return("filename", nil)
+
+	fingerprints := newMockFingerprintStore(t)
+	fingerprints.EXPECT().
+		Find(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fingerprints []Fingerprint) (map[Fingerprint]Submission, error) {
+			return map[Fingerprint]Submission{fingerprints[0]: {CaseNo: "7000-0000-0001"}}, nil
+		})
+
+	siriusService := newMockSiriusService(t)
+	siriusService.EXPECT().
+		CreateCaseStub(mock.Anything, mock.Anything).
+		Return(nil, expectedError)
+
+	config, _ := config.Read()
+
+	worker := &Worker{
+		logger:        slog.New(slog.DiscardHandler),
+		config:        config,
+		siriusService: siriusService,
+		awsClient:     awsClient,
+		fingerprints:  fingerprints,
+		validator:     NewValidator(nil),
+	}
+
+	_, err := worker.Process(context.Background(), []byte(xmlPayload))
+
+	var serr FailedToCreateCaseStubError
+	assert.ErrorAs(t, err, &serr)
+}
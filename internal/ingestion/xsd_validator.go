@@ -13,8 +13,13 @@ import (
 	"github.com/lestrrat-go/libxml2/parser"
 	"github.com/lestrrat-go/libxml2/xsd"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
+// CodeSchemaInvalid is the code for issues raised by XSD validation.
+const CodeSchemaInvalid = "schema-invalid"
+
 type XSDValidator struct {
 	schema     *xsd.Schema
 	xmlContent []byte
@@ -48,7 +53,7 @@ func (v *XSDValidator) ValidateXsd() error {
 		return err
 	}
 	defer doc.Free()
-	return v.schema.Validate(doc)
+	return validateSchema(v.schema, doc)
 }
 
 func ExtractSchemaLocation(xmlContent []byte) (string, error) {
@@ -72,3 +77,27 @@ func ExtractSchemaLocation(xmlContent []byte) (string, error) {
 
 	return root.SchemaLocation, nil
 }
+
+// schemaIssues converts XSD validation errors into issues, using the path of
+// the node at fault as the field. The document is nil for errors in the set
+// itself.
+func schemaIssues(err SchemaValidationError, document *types.BaseDocument) []validation.Issue {
+	var issues []validation.Issue
+	for _, e := range err.Errors() {
+		issue := validation.Issue{
+			Field:    schemaField(e.Path),
+			Code:     CodeSchemaInvalid,
+			Severity: validation.SeverityError,
+			Message:  e.Message,
+		}
+
+		if document != nil {
+			issue.DocumentID = document.ID
+			issue.DocumentType = document.Type
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
@@ -0,0 +1,56 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaIssuesUseNodePath(t *testing.T) {
+	lp1f := readXMLFixture(t, "LP1F-valid.xml")
+
+	// Break the attorney on the second Page12
+	second := strings.Index(lp1f, "<Page12>") + 1
+	second += strings.Index(lp1f[second:], "<Page12>")
+	lp1f = lp1f[:second] + strings.Replace(lp1f[second:], "<Title>Dr</Title>", "<Nickname>Dr</Nickname>", 1)
+
+	appConfig, _ := config.Read()
+	validator, err := NewXSDValidator(appConfig, "LP1F.xsd", []byte(lp1f))
+	require.NoError(t, err)
+
+	err = validator.ValidateXsd()
+
+	var schemaErr SchemaValidationError
+	require.ErrorAs(t, err, &schemaErr)
+
+	issues := schemaIssues(schemaErr, &types.BaseDocument{ID: "1", Type: "LP1F"})
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "1", issues[0].DocumentID)
+		assert.Equal(t, "LP1F", issues[0].DocumentType)
+		assert.Equal(t, "Page12[1].Section11.Attorney.Nickname", issues[0].Field)
+		assert.Equal(t, CodeSchemaInvalid, issues[0].Code)
+		assert.Equal(t, validation.SeverityError, issues[0].Severity)
+		assert.Contains(t, issues[0].Message, "Element 'Nickname': This element is not expected.")
+	}
+}
+
+func TestSchemaField(t *testing.T) {
+	testCases := map[string]string{
+		"/Set":                                  "Set",
+		"/Set/Header":                           "Header",
+		"/LP1F/Page12[2]/Section11/Attorney":    "Page12[1].Section11.Attorney",
+		"/LP1F/Page1/Section1/Donor/Address[1]": "Page1.Section1.Donor.Address[0]",
+		"":                                      "",
+	}
+
+	for path, field := range testCases {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, field, schemaField(path))
+		})
+	}
+}
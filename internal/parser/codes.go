@@ -0,0 +1,16 @@
+package parser
+
+// Codes for issues raised by document validators. These are part of the API
+// and must not change once published.
+const (
+	CodeSignatureMissing     = "signature-missing"
+	CodeRequiredFieldMissing = "required-field-missing"
+	CodeAddressInvalid       = "address-invalid"
+	CodeDateMissing          = "date-missing"
+	CodeDateInvalid          = "date-invalid"
+	CodeDateInFuture         = "date-in-future"
+	CodeDateOrder            = "date-order"
+	CodeFieldUnreadable      = "field-unreadable"
+	CodeSelectionInvalid     = "selection-invalid"
+	CodeBlockCount           = "block-count"
+)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types/corresp_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -29,6 +30,6 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
-	return v.baseValidator.GetValidatorErrorMessages()
+func (v *Validator) Validate() []validation.Issue {
+	return v.baseValidator.Issues()
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types/ep2pg_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -30,18 +31,18 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	if v.doc.Page1.Part1.DOB != "" {
 		if _, err := time.Parse("02012006", v.doc.Page1.Part1.DOB); err != nil {
-			v.baseValidator.AddValidatorErrorMessage("Failed to parse date of birth for Donor: " + err.Error())
+			v.baseValidator.AddIssue("Page1.Part1.DOB", parser.CodeDateInvalid, "Failed to parse date of birth for Donor: "+err.Error())
 		}
 	}
 
 	if v.doc.Page4.Part4.DOB != "" {
 		if _, err := time.Parse("02012006", v.doc.Page4.Part4.DOB); err != nil {
-			v.baseValidator.AddValidatorErrorMessage("Failed to parse date of birth for Attorney: " + err.Error())
+			v.baseValidator.AddIssue("Page4.Part4.DOB", parser.CodeDateInvalid, "Failed to parse date of birth for Attorney: "+err.Error())
 		}
 	}
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
@@ -1,6 +1,8 @@
 package parser
 
+import "github.com/ministryofjustice/opg-scanning/internal/validation"
+
 type CommonValidator interface {
 	Setup(doc any) error
-	Validate() []string
+	Validate() []validation.Issue
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -29,7 +30,7 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	// Common witness validations
 	v.baseValidator.WitnessSignatureFullNameAddressValidator("Page10", "Section9")
 
@@ -49,5 +50,5 @@ func (v *Validator) Validate() []string {
 		v.baseValidator.ApplicantSignatureValidator(fmt.Sprintf("Page20[%d]", i))
 	}
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
@@ -32,6 +32,20 @@ func TestInvalidXML(t *testing.T) {
 	parser.DocumentValidationTestHelper(t, fileName, expectedErrMsgs, validator)
 }
 
+func TestInvalidXMLIssueFields(t *testing.T) {
+	issues := getValidator(t, "LP1F-invalid-dates.xml").Validate()
+
+	var fields []string
+	for _, issue := range issues {
+		fields = append(fields, issue.Field+" "+issue.Code)
+	}
+
+	assert.Contains(t, fields, "Page10.Section9.Witness.Signature "+parser.CodeSignatureMissing)
+	assert.Contains(t, fields, "Page12[0].Section11.Witness.Address "+parser.CodeAddressInvalid)
+	assert.Contains(t, fields, "Page20[0].Section15.Applicant[0].Date "+parser.CodeDateInvalid)
+	assert.Contains(t, fields, "Page20[0].Section15.Applicant "+parser.CodeDateOrder)
+}
+
 func getValidator(t *testing.T, fileName string) parser.CommonValidator {
 	xml, err := os.ReadFile("../../../testdata/xml/" + fileName)
 	require.NoError(t, err)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -30,7 +31,7 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	// Common witness validations
 	v.baseValidator.WitnessSignatureFullNameAddressValidator("Page10", "Section9")
 
@@ -49,32 +50,33 @@ func (v *Validator) Validate() []string {
 	}
 
 	// LP1H specific validation
-	err := v.donorSignatureDateValidator()
+	field, code, err := v.donorSignatureDateValidator()
 	if err != nil {
-		v.baseValidator.AddValidatorErrorMessage(err.Error())
+		v.baseValidator.AddIssue(field, code, err.Error())
 	}
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
 
-// Helper function to extract date from a given section.
-func (v *Validator) extractDate(page, section, path string) (*time.Time, error) {
+// Helper function to extract date from a given section, returning the issue
+// code when it cannot be read.
+func (v *Validator) extractDate(page, section, path string) (*time.Time, string, error) {
 	fields, err := v.baseValidator.GetFieldByPath(page, section, path, "DOB")
 	if err != nil || len(fields) == 0 {
-		return nil, err
+		return nil, parser.CodeDateMissing, err
 	}
 
 	dateStr, ok := fields[0].(string)
 	if !ok || dateStr == "" {
-		return nil, fmt.Errorf("invalid date format or empty value")
+		return nil, parser.CodeDateMissing, fmt.Errorf("invalid date format or empty value")
 	}
 
 	date, err := time.Parse("02012006", dateStr)
 	if err != nil {
-		return nil, err
+		return nil, parser.CodeDateInvalid, err
 	}
 
-	return &date, nil
+	return &date, "", nil
 }
 
 // Helper function to extract the value of the signature.
@@ -92,47 +94,50 @@ func (v *Validator) extractSignature(page string, section string, path string) (
 	return signature, nil
 }
 
-func (v *Validator) donorSignatureDateValidator() error {
+// donorSignatureDateValidator returns the path of the field at fault and the
+// issue code along with the error, so a missing signature is reported
+// differently from a bad date.
+func (v *Validator) donorSignatureDateValidator() (string, string, error) {
 	// Option A validation
-	signatureDateA, err := v.extractDate("Page6", "Section5", "OptionA")
+	signatureDateA, code, err := v.extractDate("Page6", "Section5", "OptionA")
 	if err != nil {
-		return fmt.Errorf("failed to extract DOB OptionA: %v", err)
+		return parser.FieldPath("Page6", "Section5", "OptionA", "DOB"), code, fmt.Errorf("failed to extract DOB OptionA: %v", err)
 	}
-	section9Date, err := v.extractDate("Page10", "Section9", "Donor")
+	section9Date, code, err := v.extractDate("Page10", "Section9", "Donor")
 	if err != nil {
-		return fmt.Errorf("failed to extract DOB from Donor: %v", err)
+		return parser.FieldPath("Page10", "Section9", "Donor", "DOB"), code, fmt.Errorf("failed to extract DOB from Donor: %v", err)
 	}
 	// Ensure that Section 9 date is after Section 5 date
 	if section9Date.After(*signatureDateA) {
-		return fmt.Errorf("section 9 donor signature date is not after Section 5's donor signature date")
+		return parser.FieldPath("Page10", "Section9", "Donor", "DOB"), parser.CodeDateOrder, fmt.Errorf("section 9 donor signature date is not after Section 5's donor signature date")
 	}
 
 	signatureA, err := v.extractSignature("Page6", "Section5", "OptionA")
 	if err != nil {
-		return fmt.Errorf("failed to extract signature from OptionA: %v", err)
+		return parser.FieldPath("Page6", "Section5", "OptionA", "Signature"), parser.CodeSignatureMissing, fmt.Errorf("failed to extract signature from OptionA: %v", err)
 	}
 
 	// Option A must have both the date and signature
 	if signatureDateA != nil && signatureA != "false" {
-		return nil
+		return "", "", nil
 	}
 
 	// Option B validation
-	signatureDateB, err := v.extractDate("Page6", "Section5", "OptionB")
+	signatureDateB, code, err := v.extractDate("Page6", "Section5", "OptionB")
 	if err != nil {
-		return fmt.Errorf("failed to extract date from OptionB: %v", err)
+		return parser.FieldPath("Page6", "Section5", "OptionB", "DOB"), code, fmt.Errorf("failed to extract date from OptionB: %v", err)
 	}
 
 	signatureB, err := v.extractSignature("Page6", "Section5", "OptionB")
 	if err != nil {
-		return fmt.Errorf("failed to extract signature from OptionB: %v", err)
+		return parser.FieldPath("Page6", "Section5", "OptionB", "Signature"), parser.CodeSignatureMissing, fmt.Errorf("failed to extract signature from OptionB: %v", err)
 	}
 
 	// Option B must have both the date and signature
 	if signatureDateB != nil && signatureB != "false" {
-		return nil
+		return "", "", nil
 	}
 
 	// If neither option has both date and signature, return an error
-	return fmt.Errorf("donor signature and date are not set in either Option A or Option B")
+	return parser.FieldPath("Page6", "Section5"), parser.CodeSignatureMissing, fmt.Errorf("donor signature and date are not set in either Option A or Option B")
 }
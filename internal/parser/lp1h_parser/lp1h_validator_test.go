@@ -2,6 +2,7 @@ package lp1h_parser
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
@@ -14,6 +15,52 @@ func TestValidXML(t *testing.T) {
 	assert.Len(t, validator.Validate(), 0, "Expected no validation errors")
 }
 
+func TestInvalidDonorSignatureDate(t *testing.T) {
+	testCases := map[string]struct {
+		old, new string
+		field    string
+		code     string
+	}{
+		"section 5 date invalid": {
+			old:   "<DOB>09122014</DOB>",
+			new:   "<DOB>31132014</DOB>",
+			field: "Page6.Section5.OptionA.DOB",
+			code:  parser.CodeDateInvalid,
+		},
+		"section 9 date missing": {
+			old:   "<DOB>06122014</DOB>",
+			new:   "<DOB/>",
+			field: "Page10.Section9.Donor.DOB",
+			code:  parser.CodeDateMissing,
+		},
+		"section 9 signed before section 5": {
+			old:   "<DOB>06122014</DOB>",
+			new:   "<DOB>10122014</DOB>",
+			field: "Page10.Section9.Donor.DOB",
+			code:  parser.CodeDateOrder,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			xml, err := os.ReadFile("../../../testdata/xml/LP1H-valid.xml")
+			require.NoError(t, err)
+			xml = []byte(strings.Replace(string(xml), tc.old, tc.new, 1))
+
+			doc, err := Parse(xml)
+			require.NoError(t, err)
+			validator := NewValidator()
+			require.NoError(t, validator.Setup(doc))
+
+			issues := validator.Validate()
+			if assert.Len(t, issues, 1) {
+				assert.Equal(t, tc.field, issues[0].Field)
+				assert.Equal(t, tc.code, issues[0].Code)
+			}
+		})
+	}
+}
+
 func getValidator(t *testing.T, fileName string) parser.CommonValidator {
 	xml, err := os.ReadFile("../../../testdata/xml/" + fileName)
 	require.NoError(t, err)
@@ -6,6 +6,7 @@ import (
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/date"
 	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -30,44 +31,44 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	// Validate LP2 Sub-type Selection
 	isPF, err := v.baseValidator.GetFieldByPath("Page1", "Section1", "PropertyFinancialAffairs")
 	if err != nil {
-		v.baseValidator.AddValidatorErrorMessage(fmt.Sprintf("Error reading PropertyFinancialAffairs: %v", err))
+		v.baseValidator.AddIssue("Page1.Section1.PropertyFinancialAffairs", parser.CodeFieldUnreadable, fmt.Sprintf("Error reading PropertyFinancialAffairs: %v", err))
 	}
 	isHW, err := v.baseValidator.GetFieldByPath("Page1", "Section1", "HealthWelfare")
 	if err != nil {
-		v.baseValidator.AddValidatorErrorMessage(fmt.Sprintf("Error reading HealthWelfare: %v", err))
+		v.baseValidator.AddIssue("Page1.Section1.HealthWelfare", parser.CodeFieldUnreadable, fmt.Sprintf("Error reading HealthWelfare: %v", err))
 	}
 	// Exactly one must be true.
 	if isPF[0].(bool) == isHW[0].(bool) {
 		if isHW[0].(bool) {
-			v.baseValidator.AddValidatorErrorMessage("Both LPA sub-types are selected")
+			v.baseValidator.AddIssue("Page1.Section1", parser.CodeSelectionInvalid, "Both LPA sub-types are selected")
 		} else {
-			v.baseValidator.AddValidatorErrorMessage("Neither LPA sub-type is selected")
+			v.baseValidator.AddIssue("Page1.Section1", parser.CodeSelectionInvalid, "Neither LPA sub-type is selected")
 		}
 	}
 
 	// Validate Attorney Signature Dates
-	for _, attorney := range v.doc.Page5.Section5.Attorney {
+	for i, attorney := range v.doc.Page5.Section5.Attorney {
 		if attorney.Date == "" {
 			continue
 		}
 		if _, err := date.Parse(attorney.Date); err != nil {
-			v.baseValidator.AddValidatorErrorMessage("Failed to parse attorney signature date: " + err.Error())
+			v.baseValidator.AddIssue(fmt.Sprintf("Page5.Section5.Attorney[%d].Date", i), parser.CodeDateInvalid, "Failed to parse attorney signature date: "+err.Error())
 		}
 	}
 
 	// Validate Attorney Date of Birth
-	for _, attorney := range v.doc.Page2.Section2.Attorney {
+	for i, attorney := range v.doc.Page2.Section2.Attorney {
 		if attorney.DOB == "" {
 			continue
 		}
 		if _, err := date.Parse(attorney.DOB); err != nil {
-			v.baseValidator.AddValidatorErrorMessage("Failed to parse attorney date of birth: " + err.Error())
+			v.baseValidator.AddIssue(fmt.Sprintf("Page2.Section2.Attorney[%d].DOB", i), parser.CodeDateInvalid, "Failed to parse attorney date of birth: "+err.Error())
 		}
 	}
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/parser/date"
 	"github.com/ministryofjustice/opg-scanning/internal/types/lpa115_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -34,17 +35,21 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	if len(v.doc.Page3) > 0 {
-		for _, part := range v.doc.Page3 {
+		for i, part := range v.doc.Page3 {
 			if _, err := date.Parse(part.PartA.OptionA.Date); err != nil {
-				v.baseValidator.AddValidatorErrorMessage(
+				v.baseValidator.AddIssue(
+					fmt.Sprintf("Page3[%d].PartA.OptionA.Date", i),
+					parser.CodeDateInvalid,
 					fmt.Sprintf("Failed to parse Page 3 Option A date: %v", err),
 				)
 			}
 
 			if _, err := date.Parse(part.PartA.OptionB.Date); err != nil {
-				v.baseValidator.AddValidatorErrorMessage(
+				v.baseValidator.AddIssue(
+					fmt.Sprintf("Page3[%d].PartA.OptionB.Date", i),
+					parser.CodeDateInvalid,
 					fmt.Sprintf("Failed to parse Page 3 Option B date: %v", err),
 				)
 			}
@@ -52,14 +57,16 @@ func (v *Validator) Validate() []string {
 	}
 
 	if len(v.doc.Page6) > 0 {
-		for _, part := range v.doc.Page6 {
+		for i, part := range v.doc.Page6 {
 			if _, err := date.Parse(part.PartB.Date); err != nil {
-				v.baseValidator.AddValidatorErrorMessage(
+				v.baseValidator.AddIssue(
+					fmt.Sprintf("Page6[%d].PartB.Date", i),
+					parser.CodeDateInvalid,
 					fmt.Sprintf("Failed to parse Page 6 Part B date: %v", err),
 				)
 			}
 		}
 	}
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
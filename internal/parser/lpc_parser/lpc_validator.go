@@ -5,6 +5,7 @@ import (
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser"
 	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
 type Validator struct {
@@ -30,12 +31,12 @@ func (v *Validator) Setup(doc any) error {
 	return nil
 }
 
-func (v *Validator) Validate() []string {
+func (v *Validator) Validate() []validation.Issue {
 	v.validatePage1()
 	v.validatePage3()
 	v.validatePage4()
 
-	return v.baseValidator.GetValidatorErrorMessages()
+	return v.baseValidator.Issues()
 }
 
 func (v *Validator) validatePage1() {
@@ -43,7 +44,9 @@ func (v *Validator) validatePage1() {
 		cs := p.ContinuationSheet1
 
 		if len(cs.Attorneys) < 2 {
-			v.baseValidator.AddValidatorErrorMessage(
+			v.baseValidator.AddIssue(
+				fmt.Sprintf("Page1[%d].ContinuationSheet1.Attorney", i),
+				parser.CodeBlockCount,
 				fmt.Sprintf("Page1[%d] requires exactly 2 Attorney blocks, found %d", i, len(cs.Attorneys)),
 			)
 		}
@@ -55,7 +58,9 @@ func (v *Validator) validatePage3() {
 		cs := p.ContinuationSheet3
 
 		if len(cs.Witnesses) < 2 {
-			v.baseValidator.AddValidatorErrorMessage(
+			v.baseValidator.AddIssue(
+				fmt.Sprintf("Page3[%d].ContinuationSheet3.Witnesses", i),
+				parser.CodeBlockCount,
 				fmt.Sprintf("Page3[%d] requires exactly 2 Witness blocks, found %d", i, len(cs.Witnesses)),
 			)
 		}
@@ -67,7 +72,9 @@ func (v *Validator) validatePage4() {
 		cs := p.ContinuationSheet4
 
 		if len(cs.AuthorisedPerson) < 2 {
-			v.baseValidator.AddValidatorErrorMessage(
+			v.baseValidator.AddIssue(
+				fmt.Sprintf("Page4[%d].ContinuationSheet4.AuthorisedPerson", i),
+				parser.CodeBlockCount,
 				fmt.Sprintf("Page4[%d] requires exactly 2 AuthorisedPerson blocks, found %d", i, len(cs.AuthorisedPerson)),
 			)
 		}
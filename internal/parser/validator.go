@@ -9,32 +9,52 @@ import (
 	"time"
 
 	"github.com/ministryofjustice/opg-scanning/internal/parser/date"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
 )
 
-// Validator is a struct that holds document data and validation error messages
+// Validator is a struct that holds document data and validation issues
 type BaseValidator struct {
-	doc           any
-	errorMessages []string
-	dates         []time.Time
+	doc    any
+	issues []validation.Issue
+	dates  []time.Time
 }
 
 // NewValidator creates a new instance of Validator
 func NewBaseValidator(doc interface{}) *BaseValidator {
 	return &BaseValidator{
-		doc:           doc,
-		errorMessages: []string{},
+		doc:    doc,
+		issues: []validation.Issue{},
 	}
 }
 
-// AddValidatorErrorMessage adds an error message to the validator's list of error messages
-func (v *BaseValidator) AddValidatorErrorMessage(msg string) {
-	v.errorMessages = append(v.errorMessages, msg)
+// AddIssue records a problem with the field at the given path. Document
+// validation does not reject a set, so issues are raised as warnings.
+func (v *BaseValidator) AddIssue(field, code, message string) {
+	v.issues = append(v.issues, validation.Issue{
+		Field:    field,
+		Code:     code,
+		Severity: validation.SeverityWarning,
+		Message:  message,
+	})
 }
 
-// GetValidatorErrorMessages returns a copy of the validator's error messages
-func (v *BaseValidator) GetValidatorErrorMessages() []string {
-	errorMessages := []string{}
-	return append(errorMessages, v.errorMessages...)
+// Issues returns a copy of the validator's issues
+func (v *BaseValidator) Issues() []validation.Issue {
+	issues := []validation.Issue{}
+	return append(issues, v.issues...)
+}
+
+// FieldPath joins the parts of a path to a field, as used in issues, for
+// example "Page12[1].Section11.Attorney.Date". Empty parts are skipped.
+func FieldPath(parts ...string) string {
+	var path []string
+	for _, part := range parts {
+		if part != "" {
+			path = append(path, part)
+		}
+	}
+
+	return strings.Join(path, ".")
 }
 
 // Helper functions for working with field values
@@ -115,30 +135,32 @@ func parseFieldWithIndex(field string) (string, *int, error) {
 // Validates the presence of witness signature, full name, and address
 func (v *BaseValidator) WitnessSignatureFullNameAddressValidator(page string, section string) bool {
 	if !v.formHasWitnessSignature(page, section) {
-		v.AddValidatorErrorMessage(fmt.Sprintf("%s %s Witness Signature not set.", page, section))
+		v.AddIssue(FieldPath(page, section, "Witness", "Signature"), CodeSignatureMissing,
+			fmt.Sprintf("%s %s Witness Signature not set.", page, section))
 	}
 
 	if !v.formHasWitnessFullName(page, section) {
-		v.AddValidatorErrorMessage(fmt.Sprintf("%s %s Witness Full Name not set.", page, section))
+		v.AddIssue(FieldPath(page, section, "Witness", "FullName"), CodeRequiredFieldMissing,
+			fmt.Sprintf("%s %s Witness Full Name not set.", page, section))
 	}
 
 	if !v.formHasWitnessAddress(page, section) {
-		v.AddValidatorErrorMessage(fmt.Sprintf("%s %s Witness Address not valid.", page, section))
+		v.AddIssue(FieldPath(page, section, "Witness", "Address"), CodeAddressInvalid,
+			fmt.Sprintf("%s %s Witness Address not valid.", page, section))
 	}
 
-	return len(v.errorMessages) == 0
+	return len(v.issues) == 0
 }
 
 // Validates the presence and format of a signature date for a specific section
 func (v *BaseValidator) ValidateSignatureDate(page, section, field string) {
-	dateStr, err := v.getFieldValues(page, section, field)
-	if err != nil {
-		v.AddValidatorErrorMessage(err.Error())
+	dateStr, ok := v.getFieldValues(page, section, field)
+	if !ok {
 		return
 	}
 
-	if date, err := validateSignatureDate(dateStr, field); err != nil {
-		v.AddValidatorErrorMessage(err.Error())
+	if date, code, err := validateSignatureDate(dateStr, field); err != nil {
+		v.AddIssue(FieldPath(page, section, field, "Date"), code, err.Error())
 	} else {
 		v.dates = append(v.dates, date)
 	}
@@ -151,11 +173,14 @@ func (v *BaseValidator) ApplicantSignatureValidator(page string) []time.Time {
 	// Retrieve and validate applicant signature dates
 	applicants, err := v.GetFieldByPath(page, "Section15", "Applicant")
 	if err != nil {
-		v.AddValidatorErrorMessage(fmt.Sprintf("failed to retrieve applicant data: %v", err))
+		v.AddIssue(FieldPath(page, "Section15", "Applicant"), CodeFieldUnreadable,
+			fmt.Sprintf("failed to retrieve applicant data: %v", err))
 		return nil
 	}
 
-	for _, applicant := range applicants {
+	for i, applicant := range applicants {
+		dateFieldPath := FieldPath(page, "Section15", fmt.Sprintf("Applicant[%d]", i), "Date")
+
 		applicantVal := reflect.ValueOf(applicant)
 		if applicantVal.Kind() == reflect.Ptr {
 			applicantVal = applicantVal.Elem()
@@ -168,14 +193,14 @@ func (v *BaseValidator) ApplicantSignatureValidator(page string) []time.Time {
 			continue
 		}
 		if !dateField.IsValid() || dateField.Kind() != reflect.String {
-			v.AddValidatorErrorMessage("applicant date is missing or invalid")
+			v.AddIssue(dateFieldPath, CodeDateMissing, "applicant date is missing or invalid")
 			continue
 		}
 
 		dateStr := dateField.String()
 		signatureDate, err := date.Parse(dateStr)
 		if err != nil {
-			v.AddValidatorErrorMessage("applicant date is invalid")
+			v.AddIssue(dateFieldPath, CodeDateInvalid, "applicant date is invalid")
 			continue
 		}
 
@@ -184,34 +209,35 @@ func (v *BaseValidator) ApplicantSignatureValidator(page string) []time.Time {
 
 	// Ensure applicant dates follow correct ordering rules
 	if len(applicantSignatureDates) == 0 {
-		v.AddValidatorErrorMessage("no valid applicant signature/dates found")
+		v.AddIssue(FieldPath(page, "Section15", "Applicant"), CodeSignatureMissing, "no valid applicant signature/dates found")
 	} else {
-		v.checkDatesAgainstEarliestApplicantDate(applicantSignatureDates)
+		v.checkDatesAgainstEarliestApplicantDate(page, applicantSignatureDates)
 	}
 
 	return applicantSignatureDates
 }
 
-// Checks if the date string is valid and not in the future
-func validateSignatureDate(dateStr, label string) (time.Time, error) {
+// Checks if the date string is valid and not in the future, returning the
+// issue code when it is not
+func validateSignatureDate(dateStr, label string) (time.Time, string, error) {
 	parsedDate, err := date.Parse(dateStr)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid %s date format: %w", label, err)
+		return time.Time{}, CodeDateInvalid, fmt.Errorf("invalid %s date format: %w", label, err)
 	}
 
 	if parsedDate.After(time.Now()) {
-		return time.Time{}, fmt.Errorf("%s date cannot be in the future", label)
+		return time.Time{}, CodeDateInFuture, fmt.Errorf("%s date cannot be in the future", label)
 	}
 
-	return parsedDate, nil
+	return parsedDate, "", nil
 }
 
 // Helper function to check if all form dates are before the earliest applicant signature date
-func (v *BaseValidator) checkDatesAgainstEarliestApplicantDate(applicantSignatureDates []time.Time) {
+func (v *BaseValidator) checkDatesAgainstEarliestApplicantDate(page string, applicantSignatureDates []time.Time) {
 	earliestDate := getEarliestDate(applicantSignatureDates)
 	for _, date := range v.dates {
 		if date.After(earliestDate) {
-			v.AddValidatorErrorMessage("all form dates must be before the earliest applicant signature date")
+			v.AddIssue(FieldPath(page, "Section15", "Applicant"), CodeDateOrder, "all form dates must be before the earliest applicant signature date")
 			return
 		}
 	}
@@ -263,18 +289,22 @@ func (v *BaseValidator) formHasWitnessAddress(page, section string) bool {
 	return false
 }
 
-// Retrieves and validates the signature and date fields for a section
-func (v *BaseValidator) getFieldValues(page, section, field string) (string, error) {
+// Retrieves and validates the signature and date fields for a section,
+// recording an issue when either is missing
+func (v *BaseValidator) getFieldValues(page, section, field string) (string, bool) {
 	signatureVal, err := v.GetFieldByPath(page, section, field, "Signature")
 	if err != nil || !signatureVal[0].(bool) {
-		return "", fmt.Errorf("%s %s %s signature not set or invalid", page, section, field)
+		v.AddIssue(FieldPath(page, section, field, "Signature"), CodeSignatureMissing,
+			fmt.Sprintf("%s %s %s signature not set or invalid", page, section, field))
+		return "", false
 	}
 
 	dateVal, err := v.GetFieldByPath(page, section, field, "Date")
-	dateStr := dateVal[0].(string)
-	if err != nil || dateStr == "" {
-		return "", fmt.Errorf("missing %s %s %s date", page, section, field)
+	if err != nil || dateVal[0].(string) == "" {
+		v.AddIssue(FieldPath(page, section, field, "Date"), CodeDateMissing,
+			fmt.Sprintf("missing %s %s %s date", page, section, field))
+		return "", false
 	}
 
-	return dateStr, nil
+	return dateVal[0].(string), true
 }
@@ -14,10 +14,11 @@ func DocumentValidationTestHelper(
 	expectedPatterns []string,
 	validator CommonValidator,
 ) {
-	messages := validator.Validate()
+	var messages []string
 	t.Log("Actual messages from validation:")
-	for _, msg := range messages {
-		t.Log(msg)
+	for _, issue := range validator.Validate() {
+		t.Log(issue.Field, issue.Code, issue.Message)
+		messages = append(messages, issue.Message)
 	}
 	for _, pattern := range expectedPatterns {
 		regex, compErr := regexp.Compile(pattern)
@@ -0,0 +1,121 @@
+// Package problem describes the errors returned by the API as RFC 7807 problem
+// details. Each kind of error has a stable code so that clients can act on it
+// without matching message text.
+package problem
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+)
+
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:opg-scanning:problem:"
+
+// Type is an entry in the error catalogue.
+type Type struct {
+	Code   string
+	Title  string
+	Status int
+}
+
+// URI identifies the type in the "type" member of a problem.
+func (t Type) URI() string {
+	return typePrefix + t.Code
+}
+
+var (
+	Unauthorized          = Type{"unauthorized", "Unauthorized", http.StatusUnauthorized}
+	AuthenticationFailed  = Type{"authentication-failed", "Authentication failed", http.StatusUnauthorized}
+	Forbidden             = Type{"forbidden", "Forbidden", http.StatusForbidden}
+	TooManyLoginAttempts  = Type{"too-many-login-attempts", "Too many failed login attempts", http.StatusTooManyRequests}
+	MethodNotAllowed      = Type{"method-not-allowed", "Invalid HTTP method", http.StatusMethodNotAllowed}
+	InvalidRequestBody    = Type{"invalid-request-body", "Invalid request body", http.StatusBadRequest}
+	InvalidContentType    = Type{"invalid-content-type", "Invalid content type", http.StatusBadRequest}
+	XMLValidationFailed   = Type{"xml-validation-failed", "Validate and sanitize XML failed", http.StatusBadRequest}
+	SetValidationFailed   = Type{"set-validation-failed", "Validate set failed", http.StatusBadRequest}
+	IdempotencyKeyInvalid = Type{"idempotency-key-invalid", "Idempotency-Key is not valid", http.StatusBadRequest}
+	IdempotencyKeyReused  = Type{"idempotency-key-reused", "Idempotency-Key has already been used for a different request", http.StatusConflict}
+	RequestInProgress     = Type{"request-in-progress", "A request with this Idempotency-Key is still being processed", http.StatusConflict}
+	DuplicateSubmission   = Type{"duplicate-submission", "Set has already been submitted", http.StatusConflict}
+	CaseReferenceInvalid  = Type{"case-reference-invalid", "Case UID is not valid", http.StatusBadRequest}
+	CaseNotFound          = Type{"case-not-found", "Case not found", http.StatusBadRequest}
+	PayloadTooLarge       = Type{"payload-too-large", "Request content too large", http.StatusRequestEntityTooLarge}
+	CaseStubFailed        = Type{"case-stub-failed", "Failed to create case stub in Sirius", http.StatusInternalServerError}
+	SiriusResponseInvalid = Type{"sirius-response-invalid", "Invalid response from Sirius", http.StatusInternalServerError}
+	PersistSetFailed      = Type{"persist-set-failed", "Could not persist set to S3", http.StatusInternalServerError}
+	DuplicateCheckFailed  = Type{"duplicate-check-failed", "Could not check for earlier submissions", http.StatusInternalServerError}
+	SiriusUnavailable     = Type{"sirius-unavailable", "Failed to persist document to Sirius", http.StatusInternalServerError}
+	InternalError         = Type{"internal-error", "Internal server error", http.StatusInternalServerError}
+)
+
+// Details is the body of an application/problem+json response. Code,
+// ValidationErrors and ValidationIssues are extension members.
+type Details struct {
+	Type             string             `json:"type"`
+	Title            string             `json:"title"`
+	Status           int                `json:"status"`
+	Detail           string             `json:"detail,omitempty"`
+	Instance         string             `json:"instance,omitempty"`
+	Code             string             `json:"code"`
+	ValidationErrors []string           `json:"validationErrors,omitempty"`
+	ValidationIssues []validation.Issue `json:"validationIssues,omitempty"`
+}
+
+// New creates the details of a problem of type t that occurred handling r. The
+// detail is omitted when it only repeats the title.
+func New(t Type, r *http.Request, detail string) Details {
+	details := Details{
+		Type:   t.URI(),
+		Title:  t.Title,
+		Status: t.Status,
+		Code:   t.Code,
+	}
+
+	if detail != t.Title {
+		details.Detail = detail
+	}
+
+	if r != nil && r.URL != nil {
+		details.Instance = r.URL.Path
+	}
+
+	return details
+}
+
+// Wanted reports whether the client asked for problem details in the Accept
+// header. Clients that do not ask are sent the legacy error bodies.
+func Wanted(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, accept := range r.Header.Values("Accept") {
+		for mediaRange := range strings.SplitSeq(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mediaType != ContentType {
+				continue
+			}
+
+			if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+				continue
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Write sends the problem as the response.
+func Write(w http.ResponseWriter, details Details) error {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(details.Status)
+
+	return json.NewEncoder(w).Encode(details)
+}
@@ -0,0 +1,63 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWanted(t *testing.T) {
+	testCases := map[string]struct {
+		accept   string
+		expected bool
+	}{
+		"none":          {accept: "", expected: false},
+		"json":          {accept: "application/json", expected: false},
+		"any":           {accept: "*/*", expected: false},
+		"problem":       {accept: "application/problem+json", expected: true},
+		"in a list":     {accept: "application/json;q=0.9, application/problem+json", expected: true},
+		"with quality":  {accept: "application/problem+json;q=0.5", expected: true},
+		"refused":       {accept: "application/problem+json;q=0", expected: false},
+		"refused fully": {accept: "application/problem+json;q=0.000", expected: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+
+			assert.Equal(t, tc.expected, Wanted(r))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/ddc?x=1", nil)
+
+	assert.Equal(t, Details{
+		Type:     "urn:opg-scanning:problem:case-not-found",
+		Title:    "Case not found",
+		Status:   http.StatusBadRequest,
+		Detail:   "Case not found with UID 700000000001",
+		Instance: "/api/ddc",
+		Code:     "case-not-found",
+	}, New(CaseNotFound, r, "Case not found with UID 700000000001"))
+
+	assert.Empty(t, New(CaseNotFound, r, "Case not found").Detail)
+}
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := Write(w, New(Unauthorized, nil, "Unauthorized: Missing token"))
+	assert.Nil(t, err)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"urn:opg-scanning:problem:unauthorized","title":"Unauthorized","status":401,"detail":"Unauthorized: Missing token","code":"unauthorized"}`, w.Body.String())
+}
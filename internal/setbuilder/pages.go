@@ -0,0 +1,89 @@
+package setbuilder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+var (
+	pdfPageRegex  = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfCountRegex = regexp.MustCompile(`/Count\s+(\d+)`)
+)
+
+// CountPages returns the number of pages in a scanned document. Scanners send
+// either PDFs or multi-page TIFFs in the PDF element of a set, so both are
+// supported.
+func CountPages(data []byte) (int, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return countPDFPages(data)
+	case bytes.HasPrefix(data, []byte("II*\x00")):
+		return countTIFFPages(data, binary.LittleEndian)
+	case bytes.HasPrefix(data, []byte("MM\x00*")):
+		return countTIFFPages(data, binary.BigEndian)
+	}
+
+	return 0, errors.New("document is not a PDF or TIFF")
+}
+
+// countPDFPages counts the page objects in a PDF. When pages are hidden in
+// compressed object streams it falls back to the largest /Count, which is that
+// of the root of the page tree.
+func countPDFPages(data []byte) (int, error) {
+	if pages := len(pdfPageRegex.FindAll(data, -1)); pages > 0 {
+		return pages, nil
+	}
+
+	pages := 0
+	for _, match := range pdfCountRegex.FindAllSubmatch(data, -1) {
+		if count, err := strconv.Atoi(string(match[1])); err == nil && count > pages {
+			pages = count
+		}
+	}
+
+	if pages == 0 {
+		return 0, errors.New("unable to find any pages in PDF")
+	}
+
+	return pages, nil
+}
+
+// countTIFFPages follows the chain of image file directories in a TIFF, each
+// of which is a page.
+func countTIFFPages(data []byte, order binary.ByteOrder) (int, error) {
+	if len(data) < 8 {
+		return 0, errors.New("TIFF header is truncated")
+	}
+
+	pages := 0
+	seen := map[uint32]bool{}
+
+	for offset := order.Uint32(data[4:8]); offset != 0; {
+		if seen[offset] {
+			return 0, errors.New("TIFF directories form a loop")
+		}
+		seen[offset] = true
+
+		if uint64(offset)+2 > uint64(len(data)) {
+			return 0, errors.New("TIFF directory is outside the file")
+		}
+
+		entries := uint64(order.Uint16(data[offset : offset+2]))
+		next := uint64(offset) + 2 + entries*12
+		if next+4 > uint64(len(data)) {
+			return 0, errors.New("TIFF directory is truncated")
+		}
+
+		pages++
+		offset = order.Uint32(data[next : next+4])
+	}
+
+	if pages == 0 {
+		return 0, errors.New("unable to find any pages in TIFF")
+	}
+
+	return pages, nil
+}
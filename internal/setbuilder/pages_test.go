@@ -0,0 +1,75 @@
+package setbuilder
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiff builds a little-endian TIFF with the given number of empty directories.
+func tiff(pages int) []byte {
+	data := []byte("II*\x00")
+	data = binary.LittleEndian.AppendUint32(data, 8)
+
+	for i := range pages {
+		next := uint32(0)
+		if i < pages-1 {
+			next = uint32(len(data) + 6)
+		}
+
+		data = binary.LittleEndian.AppendUint16(data, 0)
+		data = binary.LittleEndian.AppendUint32(data, next)
+	}
+
+	return data
+}
+
+func TestCountPages(t *testing.T) {
+	pdf, err := os.ReadFile("../../testdata/pdf/dummy.pdf")
+	require.NoError(t, err)
+
+	scannedTIFF, err := base64.StdEncoding.DecodeString("SUkqAAoAAAAAtBEAAAEDAAEAAAABAAAAAQEDAAEAAAABAAAAAgEDAAEAAAABAAAAAwEDAAEAAAABAAAABgEDAAEAAAABAAAACgEDAAEAAAABAAAADQECAAEAAAAAAAAAEQEEAAEAAAAIAAAAEgEDAAEAAAABAAAAFQEDAAEAAAABAAAAFgEDAAEAAAAAIAAAFwEEAAEAAAABAAAAGgEFAAEAAADcAAAAGwEFAAEAAADkAAAAHAEDAAEAAAABAAAAKAEDAAEAAAACAAAAKQEDAAIAAAAAAAEAAAAAAEgAAAABAAAASAAAAAEAAAA=")
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		data  []byte
+		pages int
+	}{
+		"PDF":                     {data: pdf, pages: 1},
+		"PDF with object streams": {data: []byte("%PDF-1.7\n<</Type/Pages/Kids[4 0 R]/Count 3>>\n<</Type/Outlines/Count 1>>"), pages: 3},
+		"scanned TIFF":            {data: scannedTIFF, pages: 1},
+		"multi-page TIFF":         {data: tiff(3), pages: 3},
+		"big-endian TIFF":         {data: []byte("MM\x00*\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00"), pages: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			pages, err := CountPages(tc.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.pages, pages)
+		})
+	}
+}
+
+func TestCountPagesErrors(t *testing.T) {
+	loop := []byte("II*\x00\x08\x00\x00\x00\x00\x00\x08\x00\x00\x00")
+
+	testCases := map[string][]byte{
+		"unknown format":      []byte("GIF89a"),
+		"PDF without pages":   []byte("%PDF-1.4\n%%EOF"),
+		"truncated TIFF":      []byte("II*\x00\x08"),
+		"TIFF outside file":   []byte("II*\x00\xff\x00\x00\x00"),
+		"TIFF directory loop": loop,
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := CountPages(data)
+			assert.Error(t, err)
+		})
+	}
+}
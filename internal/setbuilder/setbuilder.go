@@ -0,0 +1,106 @@
+// Package setbuilder assembles Set XML, as sent by the scanning supplier, from
+// form XML and scanned documents. It is used to build sets for testing.
+package setbuilder
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+)
+
+// Header describes the scan a set comes from.
+type Header struct {
+	CaseNo          string `json:"caseNo"`
+	Scanner         string `json:"scanner"`
+	ScanTime        string `json:"scanTime"`
+	ScannerOperator string `json:"scannerOperator"`
+	Schedule        string `json:"schedule"`
+	FeeNumber       string `json:"feeNumber,omitempty"`
+}
+
+// Document is a form to include in a set. PDF holds the scanned pages, which
+// may be a PDF or TIFF. An ID is generated when one is not given.
+type Document struct {
+	Type string
+	ID   string
+	XML  []byte
+	PDF  []byte
+}
+
+type set struct {
+	XMLName        xml.Name          `xml:"Set"`
+	XSI            string            `xml:"xmlns:xsi,attr"`
+	SchemaLocation string            `xml:"xsi:noNamespaceSchemaLocation,attr"`
+	Header         *types.BaseHeader `xml:"Header"`
+	Body           types.BaseBody    `xml:"Body"`
+}
+
+// Build returns a set containing the documents, with the number of pages in
+// each counted from its scan.
+func Build(header Header, documents []Document) ([]byte, error) {
+	if len(documents) == 0 {
+		return nil, errors.New("a set must contain at least one document")
+	}
+
+	s := set{
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "SET.xsd",
+		Header: &types.BaseHeader{
+			CaseNo:          header.CaseNo,
+			Scanner:         header.Scanner,
+			ScanTime:        header.ScanTime,
+			ScannerOperator: header.ScannerOperator,
+			Schedule:        header.Schedule,
+			FeeNumber:       header.FeeNumber,
+		},
+	}
+
+	for i, document := range documents {
+		baseDocument, err := buildDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		s.Body.Documents = append(s.Body.Documents, baseDocument)
+	}
+
+	body, err := xml.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
+
+func buildDocument(document Document) (types.BaseDocument, error) {
+	if document.Type == "" {
+		return types.BaseDocument{}, errors.New("type is missing")
+	}
+
+	if len(document.XML) == 0 {
+		return types.BaseDocument{}, errors.New("XML is empty")
+	}
+
+	pages, err := CountPages(document.PDF)
+	if err != nil {
+		return types.BaseDocument{}, fmt.Errorf("unable to count pages: %w", err)
+	}
+
+	id := document.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	return types.BaseDocument{
+		Type:        document.Type,
+		Encoding:    "UTF-8",
+		NoPages:     pages,
+		ID:          id,
+		EmbeddedXML: base64.StdEncoding.EncodeToString(document.XML),
+		EmbeddedPDF: base64.StdEncoding.EncodeToString(document.PDF),
+	}, nil
+}
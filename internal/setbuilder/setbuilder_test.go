@@ -0,0 +1,75 @@
+package setbuilder
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	lp1f, err := os.ReadFile("../../testdata/xml/LP1F-valid.xml")
+	require.NoError(t, err)
+	lpc, err := os.ReadFile("../../testdata/xml/LPC-valid.xml")
+	require.NoError(t, err)
+	pdf, err := os.ReadFile("../../testdata/pdf/dummy.pdf")
+	require.NoError(t, err)
+
+	body, err := Build(Header{
+		Scanner:         "9",
+		ScanTime:        "2014-09-26T12:38:53",
+		ScannerOperator: "Administrator",
+		Schedule:        "02-0001112-20160909185000",
+	}, []Document{
+		{Type: "LP1F", ID: "lp1f", XML: lp1f, PDF: pdf},
+		{Type: "LPC", XML: lpc, PDF: tiff(2)},
+	})
+	require.NoError(t, err)
+
+	appConfig := &config.Config{}
+	appConfig.App.XSDPath = "../../xsd"
+
+	xsdValidator, err := ingestion.NewXSDValidator(appConfig, "SET.xsd", body)
+	require.NoError(t, err)
+	assert.NoError(t, xsdValidator.ValidateXsd())
+
+	set, err := parser.BaseParserXml(body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "02-0001112-20160909185000", set.Header.Schedule)
+	require.Len(t, set.Body.Documents, 2)
+
+	assert.Equal(t, "LP1F", set.Body.Documents[0].Type)
+	assert.Equal(t, "lp1f", set.Body.Documents[0].ID)
+	assert.Equal(t, 1, set.Body.Documents[0].NoPages)
+
+	assert.Equal(t, "LPC", set.Body.Documents[1].Type)
+	assert.Equal(t, 2, set.Body.Documents[1].NoPages)
+	assert.NoError(t, uuid.Validate(set.Body.Documents[1].ID))
+
+	xml, err := util.DecodeEmbeddedXML(set.Body.Documents[1].EmbeddedXML)
+	require.NoError(t, err)
+	assert.Equal(t, lpc, xml)
+}
+
+func TestBuildErrors(t *testing.T) {
+	testCases := map[string][]Document{
+		"no documents": nil,
+		"missing type": {{XML: []byte("<LP1F/>"), PDF: tiff(1)}},
+		"missing XML":  {{Type: "LP1F", PDF: tiff(1)}},
+		"unknown scan": {{Type: "LP1F", XML: []byte("<LP1F/>"), PDF: []byte("not a scan")}},
+	}
+
+	for name, documents := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Build(Header{}, documents)
+			assert.Error(t, err)
+		})
+	}
+}
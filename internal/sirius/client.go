@@ -9,7 +9,6 @@ import (
 	"net/http"
 
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -17,13 +16,19 @@ type doer interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// tokenSource mints the tokens requests to Sirius are authenticated with.
+type tokenSource interface {
+	ServiceToken(ctx context.Context) (string, error)
+}
+
 type client struct {
 	httpClient        doer
+	tokens            tokenSource
 	attachDocumentURL string
 	caseStubURL       string
 }
 
-func newClient(config *config.Config) *client {
+func newClient(config *config.Config, tokens tokenSource) *client {
 	httpClient := &http.Client{
 		Timeout: config.HTTP.Timeout,
 	}
@@ -31,6 +36,7 @@ func newClient(config *config.Config) *client {
 
 	return &client{
 		httpClient:        httpClient,
+		tokens:            tokens,
 		attachDocumentURL: fmt.Sprintf("%s/%s", config.App.SiriusBaseURL, config.App.SiriusAttachDocURL),
 		caseStubURL:       fmt.Sprintf("%s/%s", config.App.SiriusBaseURL, config.App.SiriusCaseStubURL),
 	}
@@ -49,7 +55,7 @@ type ScannedDocumentResponse struct {
 }
 
 func (c *client) AttachDocument(ctx context.Context, data *scannedDocumentRequest) (*ScannedDocumentResponse, error) {
-	req, err := newRequest(ctx, c.attachDocumentURL, data)
+	req, err := c.newRequest(ctx, c.attachDocumentURL, data)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +81,7 @@ type ScannedCaseResponse struct {
 }
 
 func (c *client) CreateCaseStub(ctx context.Context, data *scannedCaseRequest) (*ScannedCaseResponse, error) {
-	req, err := newRequest(ctx, c.caseStubURL, data)
+	req, err := c.newRequest(ctx, c.caseStubURL, data)
 	if err != nil {
 		return nil, err
 	}
@@ -88,14 +94,14 @@ func (c *client) CreateCaseStub(ctx context.Context, data *scannedCaseRequest) (
 	return v, nil
 }
 
-func newRequest(ctx context.Context, url string, data any) (*http.Request, error) {
+func (c *client) newRequest(ctx context.Context, url string, data any) (*http.Request, error) {
 	if data == nil {
 		return nil, fmt.Errorf("data is nil")
 	}
 
-	token, ok := ctx.Value(constants.TokenContextKey).(string)
-	if !ok {
-		return nil, fmt.Errorf("could not fetch user token from context")
+	token, err := c.tokens.ServiceToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service token: %w", err)
 	}
 
 	body, err := json.Marshal(data)
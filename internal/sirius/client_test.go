@@ -3,18 +3,30 @@ package sirius
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-var ctx = context.WithValue(context.Background(), constants.TokenContextKey, "testing-key")
+var (
+	ctx           = context.Background()
+	expectedError = errors.New("err")
+)
+
+func newTestTokenSource(t *testing.T) *mockTokenSource {
+	tokens := newMockTokenSource(t)
+	tokens.EXPECT().
+		ServiceToken(ctx).
+		Return("testing-key", nil)
+
+	return tokens
+}
 
 func TestNewClient(t *testing.T) {
 	config := &config.Config{}
@@ -22,8 +34,10 @@ func TestNewClient(t *testing.T) {
 	config.App.SiriusAttachDocURL = "attach"
 	config.App.SiriusCaseStubURL = "case"
 
-	client := newClient(config)
+	tokens := newMockTokenSource(t)
+	client := newClient(config, tokens)
 
+	assert.Equal(t, tokens, client.tokens)
 	assert.Equal(t, "http://example.com/attach", client.attachDocumentURL)
 	assert.Equal(t, "http://example.com/case", client.caseStubURL)
 }
@@ -48,6 +62,7 @@ func TestClientAttachDocument(t *testing.T) {
 
 	client := &client{
 		httpClient:        doer,
+		tokens:            newTestTokenSource(t),
 		attachDocumentURL: "http://example.com/attach",
 	}
 
@@ -76,6 +91,7 @@ func TestClientCreateCaseStub(t *testing.T) {
 
 	client := &client{
 		httpClient:  doer,
+		tokens:      newTestTokenSource(t),
 		caseStubURL: "http://example.com/case",
 	}
 
@@ -85,7 +101,9 @@ func TestClientCreateCaseStub(t *testing.T) {
 }
 
 func TestNewRequest(t *testing.T) {
-	req, err := newRequest(ctx, "url", "data")
+	client := &client{tokens: newTestTokenSource(t)}
+
+	req, err := client.newRequest(ctx, "url", "data")
 
 	assert.Nil(t, err)
 	assert.Equal(t, ctx, req.Context())
@@ -99,13 +117,22 @@ func TestNewRequest(t *testing.T) {
 }
 
 func TestNewRequest_NilData(t *testing.T) {
-	_, err := newRequest(ctx, "url", nil)
+	client := &client{tokens: newMockTokenSource(t)}
+
+	_, err := client.newRequest(ctx, "url", nil)
 	assert.Error(t, err)
 }
 
-func TestNewRequest_MissingUserToken(t *testing.T) {
-	_, err := newRequest(context.Background(), "url", "data")
-	assert.Error(t, err)
+func TestNewRequest_ServiceTokenError(t *testing.T) {
+	tokens := newMockTokenSource(t)
+	tokens.EXPECT().
+		ServiceToken(ctx).
+		Return("", expectedError)
+
+	client := &client{tokens: tokens}
+
+	_, err := client.newRequest(ctx, "url", "data")
+	assert.ErrorIs(t, err, expectedError)
 }
 
 func TestClientDo(t *testing.T) {
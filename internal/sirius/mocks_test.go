@@ -100,6 +100,93 @@ func (_c *mockDoer_Do_Call) RunAndReturn(run func(request *http.Request) (*http.
 	return _c
 }
 
+// newMockTokenSource creates a new instance of mockTokenSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockTokenSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockTokenSource {
+	mock := &mockTokenSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockTokenSource is an autogenerated mock type for the tokenSource type
+type mockTokenSource struct {
+	mock.Mock
+}
+
+type mockTokenSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockTokenSource) EXPECT() *mockTokenSource_Expecter {
+	return &mockTokenSource_Expecter{mock: &_m.Mock}
+}
+
+// ServiceToken provides a mock function for the type mockTokenSource
+func (_mock *mockTokenSource) ServiceToken(ctx context.Context) (string, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceToken")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockTokenSource_ServiceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceToken'
+type mockTokenSource_ServiceToken_Call struct {
+	*mock.Call
+}
+
+// ServiceToken is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockTokenSource_Expecter) ServiceToken(ctx interface{}) *mockTokenSource_ServiceToken_Call {
+	return &mockTokenSource_ServiceToken_Call{Call: _e.mock.On("ServiceToken", ctx)}
+}
+
+func (_c *mockTokenSource_ServiceToken_Call) Run(run func(ctx context.Context)) *mockTokenSource_ServiceToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockTokenSource_ServiceToken_Call) Return(s string, err error) *mockTokenSource_ServiceToken_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockTokenSource_ServiceToken_Call) RunAndReturn(run func(ctx context.Context) (string, error)) *mockTokenSource_ServiceToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockSiriusClient creates a new instance of mockSiriusClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockSiriusClient(t interface {
@@ -21,9 +21,11 @@ type Service struct {
 	client siriusClient
 }
 
-func NewService(config *config.Config) *Service {
+// NewService returns a Service that authenticates to Sirius with tokens from
+// the given source, rather than the token of whoever made the request.
+func NewService(config *config.Config, tokens tokenSource) *Service {
 	return &Service{
-		client: newClient(config),
+		client: newClient(config, tokens),
 	}
 }
 
@@ -7,11 +7,11 @@ import (
 	"testing"
 
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
 	"github.com/pact-foundation/pact-go/v2/consumer"
 	"github.com/pact-foundation/pact-go/v2/matchers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type requestCaseStub struct {
@@ -167,9 +167,14 @@ func runStubCaseTest(t *testing.T, tt requestCaseStub) {
 			mockConfig.App.SiriusBaseURL = baseURL
 
 			// Mock dependencies
-			service := NewService(mockConfig)
+			tokens := newMockTokenSource(t)
+			tokens.EXPECT().
+				ServiceToken(mock.Anything).
+				Return("my-token", nil)
 
-			ctx := context.WithValue(context.Background(), constants.TokenContextKey, "my-token")
+			service := NewService(mockConfig, tokens)
+
+			ctx := context.Background()
 
 			response, err := service.CreateCaseStub(ctx, &set)
 
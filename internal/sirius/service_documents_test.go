@@ -8,11 +8,11 @@ import (
 	"testing"
 
 	"github.com/ministryofjustice/opg-scanning/internal/config"
-	"github.com/ministryofjustice/opg-scanning/internal/constants"
 	"github.com/ministryofjustice/opg-scanning/internal/types"
 	"github.com/pact-foundation/pact-go/v2/consumer"
 	"github.com/pact-foundation/pact-go/v2/matchers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,8 +72,13 @@ func TestAttachDocument_Correspondence(t *testing.T) {
 		mockConfig.App.SiriusBaseURL = baseURL
 
 		// Prepare service instance
+		tokens := newMockTokenSource(t)
+		tokens.EXPECT().
+			ServiceToken(mock.Anything).
+			Return("my-token", nil)
+
 		service := &Service{
-			client: newClient(mockConfig),
+			client: newClient(mockConfig, tokens),
 		}
 
 		set := &types.BaseSet{
@@ -92,7 +97,7 @@ func TestAttachDocument_Correspondence(t *testing.T) {
 			UID: "7000-3764-4871",
 		}
 
-		ctx := context.WithValue(context.Background(), constants.TokenContextKey, "my-token")
+		ctx := context.Background()
 
 		response, decodedXML, err := service.AttachDocuments(ctx, set, originalDoc, caseResponse)
 		if err != nil {
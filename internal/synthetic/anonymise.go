@@ -0,0 +1,429 @@
+package synthetic
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/date"
+	"github.com/ministryofjustice/opg-scanning/internal/setbuilder"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
+)
+
+// Kinds of personal detail, each replaced from its own pool of made-up
+// values.
+const (
+	kindGivenName  = "given-name"
+	kindFamilyName = "family-name"
+	kindFullName   = "full-name"
+	kindStreet     = "street"
+	kindPlace      = "place"
+	kindPostcode   = "postcode"
+	kindDate       = "date"
+	kindEmail      = "email"
+	kindPhone      = "phone"
+	kindFreeText   = "free-text"
+)
+
+// personalFields maps the names of the form elements that hold personal
+// details to the kind of detail they hold. It covers the elements in the XSDs
+// of every supported document type, so a new form that names its fields
+// differently needs adding here.
+var personalFields = map[string]string{
+	"FirstName":                 kindGivenName,
+	"Forename":                  kindGivenName,
+	"OtherForenames":            kindGivenName,
+	"OtherNames":                kindGivenName,
+	"MiddleName":                kindGivenName,
+	"LastName":                  kindFamilyName,
+	"FullName":                  kindFullName,
+	"DonorsName":                kindFullName,
+	"NameOfApplicant":           kindFullName,
+	"DiscussedInPresenceOfName": kindFullName,
+	"OtherKnownNames":           kindFullName,
+	"ReadBy":                    kindFullName,
+	"Address1":                  kindStreet,
+	"FullAddress":               kindStreet,
+	"Address2":                  kindPlace,
+	"Address3":                  kindPlace,
+	"Address4":                  kindPlace,
+	"TownCity":                  kindPlace,
+	"County":                    kindPlace,
+	"Postcode":                  kindPostcode,
+	"DOB":                       kindDate,
+	"Email":                     kindEmail,
+	"EmailAddress":              kindEmail,
+	"Telephone":                 kindPhone,
+	"PhoneNumber":               kindPhone,
+	"Mobile":                    kindPhone,
+	"AdditionalInfo":            kindFreeText,
+	"AddtionalPersonDetails":    kindFreeText,
+	"Because":                   kindFreeText,
+	"CompanyName":               kindFreeText,
+	"ContactDetails":            kindFreeText,
+	"CorrespondenceDetails":     kindFreeText,
+	"Details":                   kindFreeText,
+	"DonorKnowledge":            kindFreeText,
+	"EPALPADetails":             kindFreeText,
+	"Guidance":                  kindFreeText,
+	"HowKnown":                  kindFreeText,
+	"IfText":                    kindFreeText,
+	"Information":               kindFreeText,
+	"KeyWords":                  kindFreeText,
+	"Occupation":                kindFreeText,
+	"OtherDetail":               kindFreeText,
+	"PersonalKnowledge":         kindFreeText,
+	"RelevantSkills":            kindFreeText,
+	"Relevantskills":            kindFreeText,
+	"Restrictions":              kindFreeText,
+	"ShouldNotBeExecuted":       kindFreeText,
+	"SkillsAndExpertise":        kindFreeText,
+}
+
+// maxAttempts is how many made-up values are tried before making one unique
+// by combining two.
+const maxAttempts = 16
+
+// Anonymiser replaces the personal details in sets with made-up ones. The
+// same detail is always replaced with the same value, so documents that refer
+// to the same person still do after being anonymised, and different details
+// are given different values. An Anonymiser remembers the values it has used,
+// so sets anonymised by the same one are consistent with each other. It is not
+// safe for concurrent use.
+type Anonymiser struct {
+	key   []byte
+	fakes map[string]string
+	used  map[string]string
+}
+
+// NewAnonymiser creates an anonymiser. The values chosen for each detail are
+// derived from the key, so cannot be reversed without it.
+func NewAnonymiser(key []byte) *Anonymiser {
+	return &Anonymiser{
+		key:   key,
+		fakes: map[string]string{},
+		used:  map[string]string{},
+	}
+}
+
+// Set anonymises each form in a set and replaces each scan with a blank PDF
+// of the same number of pages.
+func (a *Anonymiser) Set(body []byte) ([]byte, error) {
+	set, err := parser.BaseParserXml(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse set: %w", err)
+	}
+	if set.Header == nil {
+		return nil, errors.New("set has no header")
+	}
+
+	var documents []setbuilder.Document
+	for i, doc := range set.Body.Documents {
+		data, err := util.DecodeEmbeddedXML(strings.TrimSpace(doc.EmbeddedXML))
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		form, err := a.Form(data)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		pages := doc.NoPages
+		if scan, err := base64.StdEncoding.DecodeString(strings.TrimSpace(doc.EmbeddedPDF)); err == nil {
+			if counted, err := setbuilder.CountPages(scan); err == nil {
+				pages = counted
+			}
+		}
+
+		documents = append(documents, setbuilder.Document{
+			Type: doc.Type,
+			ID:   doc.ID,
+			XML:  form,
+			PDF:  blankPDF(max(pages, 1)),
+		})
+	}
+
+	return setbuilder.Build(setbuilder.Header{
+		CaseNo:          set.Header.CaseNo,
+		Scanner:         set.Header.Scanner,
+		ScanTime:        set.Header.ScanTime,
+		ScannerOperator: set.Header.ScannerOperator,
+		Schedule:        set.Header.Schedule,
+		FeeNumber:       set.Header.FeeNumber,
+	}, documents)
+}
+
+// textRange is the text of an element in a form.
+type textRange struct {
+	start, end int64
+	name, text string
+	parent     *frame
+}
+
+// frame is an element being read.
+type frame struct {
+	name     string
+	children map[string]bool
+	texts    []*textRange
+}
+
+// Form anonymises the XML of a form. Only the text of personal fields is
+// changed, so the rest of the document is left exactly as it was.
+func (a *Anonymiser) Form(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var stack []*frame
+	var leaves []*textRange
+
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read form: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(stack) > 0 {
+				stack[len(stack)-1].children[t.Name.Local] = true
+			}
+			stack = append(stack, &frame{name: t.Name.Local, children: map[string]bool{}})
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errors.New("failed to read form: unexpected end element")
+			}
+
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			// Text alongside other elements is only layout.
+			if len(current.children) == 0 && len(current.texts) > 0 {
+				leaf := &textRange{start: current.texts[0].start, end: current.texts[len(current.texts)-1].end, name: current.name}
+				for _, text := range current.texts {
+					leaf.text += text.text
+				}
+				if len(stack) > 0 {
+					leaf.parent = stack[len(stack)-1]
+				}
+				leaves = append(leaves, leaf)
+			}
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				current := stack[len(stack)-1]
+				current.texts = append(current.texts, &textRange{start: start, end: decoder.InputOffset(), text: string(t)})
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	offset := int64(0)
+
+	for _, leaf := range leaves {
+		replacement, ok := a.replace(leaf)
+		if !ok {
+			continue
+		}
+
+		out.Write(data[offset:leaf.start])
+		if err := xml.EscapeText(&out, []byte(replacement)); err != nil {
+			return nil, err
+		}
+		offset = leaf.end
+	}
+
+	out.Write(data[offset:])
+	return out.Bytes(), nil
+}
+
+// replace returns the made-up value for the text of an element, if it holds
+// a personal detail.
+func (a *Anonymiser) replace(leaf *textRange) (string, bool) {
+	kind, ok := personalFields[leaf.name]
+	value := strings.TrimSpace(leaf.text)
+	if !ok || value == "" {
+		return "", false
+	}
+
+	switch kind {
+	case kindGivenName:
+		return a.names(kindGivenName, strings.Fields(value)), true
+
+	case kindFamilyName:
+		return a.names(kindFamilyName, strings.Fields(value)), true
+
+	case kindFullName:
+		// The last name is replaced as a family name, so that a full name
+		// matches the same name given in parts elsewhere.
+		words := strings.Fields(value)
+		var title []string
+		if len(words) > 1 && isTitle(words[0]) {
+			title, words = words[:1], words[1:]
+		}
+		if len(words) == 1 {
+			return strings.Join(append(title, a.names(kindFamilyName, words)), " "), true
+		}
+
+		return strings.Join(append(title, a.names(kindGivenName, words[:len(words)-1]), a.names(kindFamilyName, words[len(words)-1:])), " "), true
+
+	case kindDate:
+		// The LP1H records the dates the donor signed in elements named DOB.
+		if leaf.parent != nil && leaf.parent.children["Signature"] {
+			return "", false
+		}
+		return a.date(value)
+
+	case kindEmail:
+		if !strings.Contains(value, "@") {
+			return "", false
+		}
+
+	case kindPhone:
+		if !strings.ContainsAny(value, "0123456789") {
+			return "", false
+		}
+	}
+
+	return a.fake(kind, value), true
+}
+
+func (a *Anonymiser) names(kind string, words []string) string {
+	var names []string
+	for _, word := range words {
+		names = append(names, a.fake(kind, word))
+	}
+
+	return strings.Join(names, " ")
+}
+
+// date replaces a date with another in the same year, but never the date
+// itself, keeping its format when that is the format used by the scanning
+// supplier.
+func (a *Anonymiser) date(value string) (string, bool) {
+	parsed, err := date.Parse(value)
+	if err != nil {
+		return "", false
+	}
+
+	var fake time.Time
+	for attempt := 0; attempt == 0 || fake.Equal(parsed); attempt++ {
+		sum := a.sum(kindDate, value, attempt)
+		fake = time.Date(parsed.Year(), time.Month(1+pickFrom(sum, 0, 12)), 1+pickFrom(sum, 1, 28), 0, 0, 0, 0, time.UTC)
+	}
+
+	if _, err := time.Parse(dateLayout, value); err == nil {
+		return fake.Format(dateLayout), true
+	}
+
+	return fake.Format(time.DateOnly), true
+}
+
+// fake returns the made-up value for a detail. Names, streets, postcodes and
+// email addresses identify people, so each is given one not already used for
+// a different detail of the same kind, and never the detail itself.
+func (a *Anonymiser) fake(kind, value string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(value), " "))
+
+	switch kind {
+	case kindGivenName, kindFamilyName, kindStreet, kindPostcode, kindEmail:
+	default:
+		return a.candidate(kind, key, 0)
+	}
+
+	if fake, ok := a.fakes[kind+"\x00"+key]; ok {
+		return fake
+	}
+
+	for attempt := 0; ; attempt++ {
+		fake := a.candidate(kind, key, attempt)
+
+		if strings.EqualFold(fake, key) {
+			continue
+		}
+
+		usedKey := kind + "\x00" + strings.ToLower(fake)
+		if _, taken := a.used[usedKey]; !taken {
+			a.used[usedKey] = key
+			a.fakes[kind+"\x00"+key] = fake
+			return fake
+		}
+	}
+}
+
+// sum derives the choices made for a detail from the key.
+func (a *Anonymiser) sum(kind, key string, attempt int) []byte {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind + "\x00" + key))
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(attempt))) //#nosec G115 attempts are never negative
+
+	return mac.Sum(nil)
+}
+
+// pickFrom uses the i'th four bytes of a sum to choose a number below limit.
+func pickFrom(sum []byte, i, limit int) int {
+	return int(binary.BigEndian.Uint32(sum[i*4:]) % uint32(limit)) //#nosec G115 limits are small
+}
+
+// candidate makes up a value for a detail. After many attempts values are
+// combined, so there is always an unused one.
+func (a *Anonymiser) candidate(kind, key string, attempt int) string {
+	sum := a.sum(kind, key, attempt)
+
+	fromPool := func(pool []string) string {
+		if attempt < maxAttempts {
+			return pool[pickFrom(sum, 0, len(pool))]
+		}
+		return pool[pickFrom(sum, 0, len(pool))] + "-" + pool[pickFrom(sum, 1, len(pool))]
+	}
+
+	letter := func(i int) rune {
+		return rune('A' + pickFrom(sum, i, 26))
+	}
+
+	switch kind {
+	case kindGivenName:
+		return fromPool(firstNames)
+	case kindFamilyName:
+		return fromPool(lastNames)
+	case kindStreet:
+		return fmt.Sprintf("%d %s", 1+pickFrom(sum, 2, 200+attempt), fromPool(streets))
+	case kindPlace:
+		return fromPool(append(towns[:len(towns):len(towns)], counties...))
+	case kindPostcode:
+		return fmt.Sprintf("%c%c%d %d%c%c", letter(0), letter(1), 1+pickFrom(sum, 2, 29), pickFrom(sum, 3, 10), letter(4), letter(5))
+	case kindEmail:
+		local := strings.ToLower(fromPool(firstNames) + "." + strings.ReplaceAll(lastNames[pickFrom(sum, 2, len(lastNames))], "'", ""))
+		if attempt > 0 {
+			local += fmt.Sprint(attempt)
+		}
+		return local + "@example.com"
+	case kindPhone:
+		return fmt.Sprintf("07700 900%03d", pickFrom(sum, 0, 1000))
+	}
+
+	return "Redacted"
+}
+
+func isTitle(word string) bool {
+	for _, title := range append(titles, "Dr", "Rev", "Sir", "Lady", "Lord") {
+		if strings.EqualFold(strings.TrimSuffix(word, "."), title) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,253 @@
+package synthetic
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lp1h_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lpa115_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/setbuilder"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpa115_types"
+	"github.com/ministryofjustice/opg-scanning/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureSet(t *testing.T, fixtures map[string]string) []byte {
+	t.Helper()
+
+	pdf, err := os.ReadFile("../../testdata/pdf/dummy.pdf")
+	require.NoError(t, err)
+
+	var documents []setbuilder.Document
+	for _, documentType := range []string{"LP1H", "LPC", "LPA115", "Correspondence"} {
+		fixture, ok := fixtures[documentType]
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile("../../testdata/xml/" + fixture)
+		require.NoError(t, err)
+
+		documents = append(documents, setbuilder.Document{Type: documentType, ID: documentType, XML: data, PDF: pdf})
+	}
+
+	body, err := setbuilder.Build(setbuilder.Header{Scanner: "9", ScanTime: "2014-09-26T12:38:53", ScannerOperator: "Administrator", Schedule: "02-0001112-20160909185000"}, documents)
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestAnonymiserSet(t *testing.T) {
+	original := fixtureSet(t, map[string]string{"LP1H": "LP1H-valid.xml", "LPC": "LPC-valid.xml", "LPA115": "LPA115-valid.xml"})
+
+	anonymised, err := NewAnonymiser([]byte("key")).Set(original)
+	require.NoError(t, err)
+
+	// The anonymised set is checked in just the same way as the original.
+	before := validateSet(t, original)
+	after := validateSet(t, anonymised)
+	assert.True(t, after.Valid)
+	assert.Equal(t, issueCodes(before.Issues), issueCodes(after.Issues))
+
+	set, err := parser.BaseParserXml(anonymised)
+	require.NoError(t, err)
+	require.Len(t, set.Body.Documents, 3)
+
+	forms := map[string][]byte{}
+	for _, document := range set.Body.Documents {
+		assert.Equal(t, 1, document.NoPages)
+
+		scan, err := base64.StdEncoding.DecodeString(document.EmbeddedPDF)
+		require.NoError(t, err)
+		assert.Contains(t, string(scan), "/MediaBox")
+
+		forms[document.Type], err = util.DecodeEmbeddedXML(document.EmbeddedXML)
+		require.NoError(t, err)
+
+		for _, personal := range []string{"John", "Doe", "Jane", "Smith", "Mary", "Jones", "Andrew", "Anderson"} {
+			assert.NotContains(t, string(forms[document.Type]), ">"+personal+"<", document.Type)
+		}
+	}
+
+	// The LPA115 is still signed by the donor named on the LP1H.
+	lp1h, err := lp1h_parser.Parse(forms["LP1H"])
+	require.NoError(t, err)
+	lpa115, err := lpa115_parser.Parse(forms["LPA115"])
+	require.NoError(t, err)
+
+	donor := lp1h.(*lp1h_types.LP1HDocument).Page1.Section1
+	assert.Equal(t, donor.FirstName+" "+donor.LastName, lpa115.(*lpa115_types.LPA115Document).Page3[0].PartA.FullName)
+	assert.NotEqual(t, "John Doe", lpa115.(*lpa115_types.LPA115Document).Page3[0].PartA.FullName)
+}
+
+func TestAnonymiserForm(t *testing.T) {
+	form := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<LP1H xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="LP1H.xsd">
+  <Section1>
+    <Title>Mr</Title>
+    <FirstName>John Paul</FirstName>
+    <LastName>O'Doe &amp; Co</LastName>
+    <DOB>02011950</DOB>
+    <EmailAddress>john@doe.test</EmailAddress>
+    <PhoneNumber></PhoneNumber>
+  </Section1>
+  <Section5>
+    <Signature>true</Signature>
+    <DOB>02012020</DOB>
+  </Section5>
+  <Witness><FullName>Mr John Doe</FullName></Witness>
+  <Other><FirstName>Jane</FirstName></Other>
+</LP1H>
+`)
+
+	anonymiser := NewAnonymiser([]byte("key"))
+
+	anonymised, err := anonymiser.Form(form)
+	require.NoError(t, err)
+
+	again, err := NewAnonymiser([]byte("key")).Form(form)
+	require.NoError(t, err)
+	assert.Equal(t, anonymised, again)
+
+	differentKey, err := NewAnonymiser([]byte("another key")).Form(form)
+	require.NoError(t, err)
+	assert.NotEqual(t, anonymised, differentKey)
+
+	var doc struct {
+		Section1 struct {
+			Title, FirstName, LastName, DOB, EmailAddress, PhoneNumber string
+		}
+		Section5 struct {
+			DOB string
+		}
+		Witness struct {
+			FullName string
+		}
+		Other struct {
+			FirstName string
+		}
+	}
+	require.NoError(t, xml.Unmarshal(anonymised, &doc))
+
+	section1 := doc.Section1
+	assert.Equal(t, "Mr", section1.Title)
+	assert.Len(t, section1.DOB, 8)
+	assert.Equal(t, "1950", section1.DOB[4:])
+	assert.NotEqual(t, "02011950", section1.DOB)
+	assert.Regexp(t, `@example\.com$`, section1.EmailAddress)
+	assert.Empty(t, section1.PhoneNumber)
+
+	// The signature date on the LP1H is not a date of birth.
+	assert.Equal(t, "02012020", doc.Section5.DOB)
+
+	// Names are replaced word by word, so a full name matches its parts.
+	given := strings.Fields(section1.FirstName)
+	require.Len(t, given, 2)
+	witness := strings.Fields(doc.Witness.FullName)
+	require.Len(t, witness, 3)
+	assert.Equal(t, "Mr", witness[0])
+	assert.Equal(t, given[0], witness[1])
+	assert.NotEqual(t, given[0], doc.Other.FirstName)
+	assert.NotContains(t, string(anonymised), "John")
+}
+
+// schemaNames gives the XSD for the supported document types that are not
+// written to an XSD of their own name.
+var schemaNames = map[string]string{
+	constants.DocumentSupCorresp:     "Correspondence",
+	constants.DocumentTypeDEPREPORTS: "DeputyshipCorrespondence",
+	constants.DocumentTypeDEPCORRES:  "DeputyshipCorrespondence",
+	constants.DocumentTypeFINDOCS:    "FinancialEvidence",
+}
+
+// looksPersonal matches the names of elements that probably hold a personal
+// detail, so a field missing from personalFields is noticed.
+var looksPersonal = regexp.MustCompile(`Name|Address|Postcode|Phone|Mobile|Email|DOB`)
+
+// personalFixture fills every personal field declared in a schema with a
+// different value, returning the form and the values.
+func personalFixture(t *testing.T, schema *element) ([]byte, []string) {
+	t.Helper()
+
+	var values []string
+
+	var build func(e *element, parent *element) *node
+	build = func(e *element, parent *element) *node {
+		n := &node{schema: e}
+
+		if len(e.children) == 0 {
+			kind, ok := personalFields[e.name]
+
+			// Tick boxes are not personal, and OtherName is the title given
+			// when none of the boxes fits.
+			if !ok && e.kind != "xs:boolean" && e.name != "OtherName" {
+				assert.NotRegexp(t, looksPersonal, e.name, "%s is not anonymised", e.name)
+			}
+
+			i := len(values)
+			switch {
+			case !ok || e.kind == "xs:boolean":
+				return n
+			case kind == kindDate && parent != nil && parent.child("Signature") != nil:
+				// The LP1H records the date the donor signed in a DOB.
+				return n
+			case kind == kindDate:
+				n.value = fmt.Sprintf("%02d%02d19%02d", 1+i%28, 1+i%12, i%100)
+			case kind == kindEmail:
+				n.value = fmt.Sprintf("person%d@real.test", i)
+			case kind == kindPhone:
+				n.value = fmt.Sprintf("0161 496 %04d", i)
+			default:
+				n.value = fmt.Sprintf("Personal%d", i)
+			}
+
+			values = append(values, n.value)
+			return n
+		}
+
+		for _, child := range e.children {
+			n.children = append(n.children, build(child, e))
+		}
+
+		return n
+	}
+
+	data, err := build(schema, nil).marshal()
+	require.NoError(t, err)
+
+	return data, values
+}
+
+func TestAnonymiserFormSupportedTypes(t *testing.T) {
+	documentTypes := append(constants.SupportedDocumentTypes[:len(constants.SupportedDocumentTypes):len(constants.SupportedDocumentTypes)], constants.DocumentTypeLP0002R)
+
+	for _, documentType := range documentTypes {
+		t.Run(documentType, func(t *testing.T) {
+			schemaName := documentType
+			if name, ok := schemaNames[documentType]; ok {
+				schemaName = name
+			}
+
+			schema, err := loadSchema("../../xsd", schemaName)
+			require.NoError(t, err)
+
+			form, values := personalFixture(t, schema)
+
+			anonymised, err := NewAnonymiser([]byte("key")).Form(form)
+			require.NoError(t, err)
+
+			for _, value := range values {
+				assert.NotContains(t, string(anonymised), ">"+value+"<")
+			}
+		})
+	}
+}
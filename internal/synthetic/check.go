@@ -0,0 +1,94 @@
+package synthetic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/factory"
+)
+
+// readable leaves out, or blanks, the elements of a form that its type has no
+// field for. The XSDs and types are maintained separately, and the service
+// drops such elements when it parses a form, so they are left blank rather
+// than hold details that would never be read.
+func readable(n *node, t reflect.Type) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if n.isLeaf() {
+		return
+	}
+
+	if t.Kind() != reflect.Struct {
+		n.clear()
+		return
+	}
+
+	children := n.children[:0]
+	for _, child := range n.children {
+		field, ok := xmlField(t, child.name())
+		switch {
+		case ok:
+			readable(child, field.Type)
+		case child.schema.minOccurs == 0:
+			continue
+		default:
+			child.clear()
+		}
+
+		children = append(children, child)
+	}
+
+	n.children = children
+}
+
+// xmlField finds the field of a struct that encoding/xml decodes the named
+// element into. As with Go's own field selection, fields of embedded structs
+// are only used when the struct doesn't have one of its own.
+func xmlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var embedded []reflect.Type
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		tag, hasTag := field.Tag.Lookup("xml")
+		tagName, flags, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, field.Type)
+			continue
+		}
+
+		if !field.IsExported() || field.Name == "XMLName" || tagName == "-" || flags != "" && flags != "omitempty" {
+			continue
+		}
+
+		if tagName == name || tagName == "" && field.Name == name {
+			return field, true
+		}
+	}
+
+	for _, t := range embedded {
+		if field, ok := xmlField(t, name); ok {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// checkForm parses a generated form as the service would.
+func checkForm(documentType string, data []byte) error {
+	registry, err := factory.NewRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	if _, err := registry.ParseXML(documentType, data); err != nil {
+		return fmt.Errorf("generated %s cannot be parsed: %w", documentType, err)
+	}
+
+	return nil
+}
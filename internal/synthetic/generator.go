@@ -0,0 +1,354 @@
+// Package synthetic generates random forms, and sets of them, that match the
+// XSDs the scanning supplier works to. The forms are filled with made-up
+// people so they can be used freely for load testing, and particular mistakes
+// can be made on them to exercise the validators. Real sets can be anonymised
+// in the same spirit, replacing the personal details on them with made-up
+// ones so production problems can be reproduced elsewhere.
+package synthetic
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/setbuilder"
+)
+
+// Defect is a mistake that can be made on a generated form.
+type Defect string
+
+const (
+	// MissingWitness leaves every witness section blank and unsigned.
+	MissingWitness Defect = "missing-witness"
+	// FutureDate moves the signature dates on a form into the future.
+	FutureDate Defect = "future-date"
+	// WrongAttorneyCount says that one attorney is appointed, and that there
+	// are no more attorneys, on an instrument that names several.
+	WrongAttorneyCount Defect = "wrong-attorney-count"
+)
+
+// Defects lists the defects that can be made.
+var Defects = []Defect{MissingWitness, FutureDate, WrongAttorneyCount}
+
+// ErrDefectNotApplicable is returned when a defect cannot be made on any of
+// the forms being generated.
+var ErrDefectNotApplicable = errors.New("defect does not apply")
+
+// Generator makes random forms. The same seed gives the same forms, with
+// dates relative to the day they are generated.
+type Generator struct {
+	xsdPath string
+	rand    *rand.Rand
+	now     time.Time
+	schemas map[string]*element
+}
+
+// New creates a generator using the XSDs in xsdPath.
+func New(xsdPath string, seed uint64) *Generator {
+	return &Generator{
+		xsdPath: xsdPath,
+		rand:    rand.New(rand.NewPCG(seed, seed)), //#nosec G404 the forms only need to look random
+		now:     time.Now(),
+		schemas: map[string]*element{},
+	}
+}
+
+// Types returns the document types that can be generated.
+func Types() []string {
+	var documentTypes []string
+	for documentType := range profiles {
+		documentTypes = append(documentTypes, documentType)
+	}
+	slices.Sort(documentTypes)
+
+	return documentTypes
+}
+
+// Form returns the XML of a form of the given type, with the given defects.
+func (g *Generator) Form(documentType string, defects ...Defect) ([]byte, error) {
+	c := g.newCast(g.caseNumber(), g.attorneyCount([]string{documentType}))
+
+	data, _, err := g.form(documentType, c, defects)
+	return data, err
+}
+
+// Set returns a set containing a form of each of the given types, with a
+// blank page scanned for each page of the form. The forms are about the same
+// power of attorney, so should pass the checks made across a set. Each defect
+// is made on every form it applies to.
+func (g *Generator) Set(documentTypes []string, defects ...Defect) ([]byte, error) {
+	if len(documentTypes) == 0 {
+		return nil, errors.New("a set must contain at least one document")
+	}
+
+	// Only sets that do not create a case are filed against one.
+	caseNo := g.caseNumber()
+	for _, documentType := range documentTypes {
+		if slices.Contains(constants.CreateLPADocuments, documentType) || slices.Contains(constants.CreateEPADocuments, documentType) {
+			caseNo = ""
+		}
+	}
+
+	c := g.newCast(caseNo, g.attorneyCount(documentTypes))
+
+	for _, defect := range defects {
+		if !slices.ContainsFunc(documentTypes, func(documentType string) bool { return profiles[documentType].supports(defect) }) {
+			return nil, fmt.Errorf("%w to any document in the set: %s", ErrDefectNotApplicable, defect)
+		}
+	}
+
+	var documents []setbuilder.Document
+	for _, documentType := range documentTypes {
+		var applicable []Defect
+		for _, defect := range defects {
+			if profiles[documentType].supports(defect) {
+				applicable = append(applicable, defect)
+			}
+		}
+
+		data, pages, err := g.form(documentType, c, applicable)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, setbuilder.Document{
+			Type: documentType,
+			ID:   g.id(),
+			XML:  data,
+			PDF:  scan(pages),
+		})
+	}
+
+	scanTime := g.now.Add(-time.Duration(g.rand.IntN(3600)) * time.Second)
+
+	return setbuilder.Build(setbuilder.Header{
+		CaseNo:          caseNo,
+		Scanner:         strconv.Itoa(1 + g.rand.IntN(9)),
+		ScanTime:        scanTime.Format("2006-01-02T15:04:05"),
+		ScannerOperator: "Synthetic",
+		Schedule:        fmt.Sprintf("02-%07d-%s", g.rand.IntN(10000000), scanTime.Format("20060102150405")),
+	}, documents)
+}
+
+// form generates a form, returning its XML and number of pages.
+func (g *Generator) form(documentType string, c *cast, defects []Defect) ([]byte, int, error) {
+	profile, ok := profiles[documentType]
+	if !ok {
+		return nil, 0, fmt.Errorf("unable to generate documents of type %s", documentType)
+	}
+
+	for _, defect := range defects {
+		if !profile.supports(defect) {
+			return nil, 0, fmt.Errorf("%w to %s: %s", ErrDefectNotApplicable, documentType, defect)
+		}
+	}
+
+	schema, err := g.schema(documentType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f := &form{Generator: g, cast: c}
+	root := f.build(schema, nil, nil)
+
+	profile.fill(f, root)
+	for _, defect := range defects {
+		profile.defects[defect](f, root)
+	}
+
+	pages := root.descendants("PhysicalPage")
+	for i, page := range pages {
+		page.value = strconv.Itoa(i + 1)
+	}
+
+	readable(root, profile.document)
+
+	data, err := root.marshal()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := checkForm(documentType, data); err != nil {
+		return nil, 0, err
+	}
+
+	return data, max(len(pages), 1), nil
+}
+
+func (g *Generator) schema(documentType string) (*element, error) {
+	if schema, ok := g.schemas[documentType]; ok {
+		return schema, nil
+	}
+
+	schema, err := loadSchema(g.xsdPath, documentType)
+	if err != nil {
+		return nil, err
+	}
+
+	g.schemas[documentType] = schema
+	return schema, nil
+}
+
+// attorneyCount decides how many attorneys to appoint. An instrument has room
+// for four, so there are more when continuation sheets are needed.
+func (g *Generator) attorneyCount(documentTypes []string) int {
+	if slices.Contains(documentTypes, constants.DocumentTypeLPC) {
+		return 5 + g.rand.IntN(4)
+	}
+
+	return 2 + g.rand.IntN(3)
+}
+
+func (g *Generator) today() time.Time {
+	year, month, day := g.now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (g *Generator) caseNumber() string {
+	return fmt.Sprintf("7000-%04d-%04d", g.rand.IntN(10000), g.rand.IntN(10000))
+}
+
+func (g *Generator) id() string {
+	return fmt.Sprintf("%08d", g.rand.IntN(100000000))
+}
+
+// form is a form being generated.
+type form struct {
+	*Generator
+	cast *cast
+}
+
+// build creates an element and everything it must contain, filling in
+// values for the person, if any, the element is about. Optional elements are
+// left out.
+func (f *form) build(e, parent *element, p *person) *node {
+	n := &node{schema: e}
+
+	if len(e.children) == 0 {
+		n.value = f.value(e, parent, p)
+		return n
+	}
+
+	switch {
+	case namesPerson(e) && e.name != "Name":
+		p = f.newPerson(18, 90)
+	case p == nil && e.child("Address1") != nil:
+		p = f.newPerson(18, 90)
+	}
+
+	for _, child := range e.children {
+		for range child.minOccurs {
+			n.children = append(n.children, f.build(child, e, p))
+		}
+	}
+
+	return n
+}
+
+// add creates an optional or repeated child of an element, placing it after
+// any of the same name.
+func (f *form) add(parent *node, name string) *node {
+	e := parent.schema.child(name)
+	child := f.build(e, parent.schema, nil)
+
+	position := slices.Index(parent.schema.children, e)
+	at := 0
+	for i, existing := range parent.children {
+		if slices.Index(parent.schema.children, existing.schema) <= position {
+			at = i + 1
+		}
+	}
+
+	parent.children = slices.Insert(parent.children, at, child)
+	return child
+}
+
+// fillPerson replaces the details of the people in elements with those of p.
+func fillPerson(nodes []*node, p *person) {
+	for _, n := range nodes {
+		for _, child := range n.children {
+			if child.isLeaf() {
+				if value, ok := personValue(child.schema, n.schema, p); ok {
+					child.value = truncate(value, child.schema)
+				}
+			} else {
+				fillPerson([]*node{child}, p)
+			}
+		}
+	}
+}
+
+// signatureDate returns a date shortly after the donor signed.
+func (f *form) signatureDate() string {
+	return f.cast.signed.AddDate(0, 0, f.rand.IntN(14)).Format(dateLayout)
+}
+
+// value generates the value of a simple element.
+func (f *form) value(e, parent *element, p *person) string {
+	return truncate(f.rawValue(e, parent, p), e)
+}
+
+func (f *form) rawValue(e, parent *element, p *person) string {
+	signatureBlock := parent != nil && parent.child("Signature") != nil
+
+	switch {
+	case e.name == "Signature" || e.name == "Signed":
+		return "true"
+	case e.name == "Date" || e.name == "NoticeDate" || e.name == "DOB" && signatureBlock:
+		return f.signatureDate()
+	case e.name == "PhysicalPage":
+		return "0"
+	case e.name == "BURN":
+		return fmt.Sprintf("%010d", f.rand.IntN(10000000000))
+	case e.name == "ContinuationSheetNo" || e.name == "TotalSheets":
+		return "1"
+	case e.name == "Occupation":
+		return pick(f.Generator, occupations)
+	}
+
+	if parent != nil {
+		if p == nil {
+			p = f.newPerson(18, 90)
+		}
+		if value, ok := personValue(e, parent, p); ok {
+			return value
+		}
+	}
+
+	return zeroValue(e)
+}
+
+// zeroValue is the value of an element left blank.
+func zeroValue(e *element) string {
+	switch e.kind {
+	case "xs:boolean":
+		return "false"
+	case "xs:int", "xs:float":
+		return "0"
+	}
+
+	return ""
+}
+
+func truncate(value string, e *element) string {
+	if e.maxLength > 0 && len(value) > e.maxLength {
+		return value[:e.maxLength]
+	}
+
+	return value
+}
+
+// namesPerson reports whether an element names someone.
+func namesPerson(e *element) bool {
+	for _, name := range []string{"FirstName", "LastName", "FullName", "Forename", "Name"} {
+		if e.child(name) != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,237 @@
+package synthetic
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/config"
+	"github.com/ministryofjustice/opg-scanning/internal/factory"
+	"github.com/ministryofjustice/opg-scanning/internal/ingestion"
+	"github.com/ministryofjustice/opg-scanning/internal/parser"
+	"github.com/ministryofjustice/opg-scanning/internal/parser/lpc_parser"
+	"github.com/ministryofjustice/opg-scanning/internal/setbuilder"
+	"github.com/ministryofjustice/opg-scanning/internal/types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+	"github.com/ministryofjustice/opg-scanning/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() *config.Config {
+	appConfig := &config.Config{}
+	appConfig.App.XSDPath = "../../xsd"
+
+	return appConfig
+}
+
+// formIssues checks a form against its XSD, then returns the issues raised
+// by its validator.
+func formIssues(t *testing.T, documentType string, data []byte) []validation.Issue {
+	t.Helper()
+
+	xsdValidator, err := ingestion.NewXSDValidator(testConfig(), documentType+".xsd", data)
+	require.NoError(t, err)
+	require.NoError(t, xsdValidator.ValidateXsd(), string(data))
+
+	registry, err := factory.NewRegistry()
+	require.NoError(t, err)
+
+	document := &types.BaseDocument{Type: documentType, EmbeddedXML: base64.StdEncoding.EncodeToString(data)}
+	processor, err := factory.NewDocumentProcessor(document, documentType, registry, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	_, err = processor.Process(context.Background())
+	require.NoError(t, err)
+
+	return processor.Issues()
+}
+
+func issueCodes(issues []validation.Issue) []string {
+	var codes []string
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+
+	return codes
+}
+
+func TestForm(t *testing.T) {
+	for _, documentType := range Types() {
+		t.Run(documentType, func(t *testing.T) {
+			for seed := range uint64(5) {
+				data, err := New("../../xsd", seed).Form(documentType)
+				require.NoError(t, err)
+
+				assert.Empty(t, formIssues(t, documentType, data), "seed %d", seed)
+			}
+		})
+	}
+}
+
+func TestFormOnlyFillsElementsItsTypeReads(t *testing.T) {
+	// The EP2PG type has no town or county in its addresses, and the LP1H type
+	// reads its attorneys from page 3 only.
+	ep2pg, err := New("../../xsd", 1).Form("EP2PG")
+	require.NoError(t, err)
+	assert.Contains(t, string(ep2pg), "<TownCity></TownCity>")
+	assert.NotRegexp(t, `<TownCity>[^<]`, string(ep2pg))
+
+	lp1h, err := New("../../xsd", 1).Form("LP1H")
+	require.NoError(t, err)
+
+	page2 := string(lp1h)[strings.Index(string(lp1h), "<Page2>"):strings.Index(string(lp1h), "</Page2>")]
+	assert.NotRegexp(t, `<FirstName>[^<]`, page2)
+}
+
+func TestFormIsRepeatable(t *testing.T) {
+	a, err := New("../../xsd", 7).Form("LP1F")
+	require.NoError(t, err)
+	b, err := New("../../xsd", 7).Form("LP1F")
+	require.NoError(t, err)
+	c, err := New("../../xsd", 8).Form("LP1F")
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestFormDefects(t *testing.T) {
+	testCases := map[Defect][]string{
+		MissingWitness: {parser.CodeSignatureMissing, parser.CodeRequiredFieldMissing, parser.CodeAddressInvalid},
+		FutureDate:     {parser.CodeDateInFuture},
+	}
+
+	for _, documentType := range []string{"LP1F", "LP1H"} {
+		for defect, codes := range testCases {
+			t.Run(documentType+"/"+string(defect), func(t *testing.T) {
+				data, err := New("../../xsd", 1).Form(documentType, defect)
+				require.NoError(t, err)
+
+				issues := formIssues(t, documentType, data)
+				assert.Subset(t, issueCodes(issues), codes)
+				assert.NotContains(t, issueCodes(issues), parser.CodeDateOrder)
+			})
+		}
+	}
+}
+
+func TestFormFutureDateOnOtherForms(t *testing.T) {
+	signatureDate := regexp.MustCompile(`<Date>([0-9]{8})</Date>`)
+	today := time.Now().Format("20060102")
+
+	for _, documentType := range []string{"LP2", "LPC", "EP2PG", "LPA115", "LPA120"} {
+		t.Run(documentType, func(t *testing.T) {
+			data, err := New("../../xsd", 1).Form(documentType, FutureDate)
+			require.NoError(t, err)
+
+			// The dates are rearranged so they can be compared as strings.
+			var future bool
+			for _, match := range signatureDate.FindAllStringSubmatch(string(data), -1) {
+				if date := match[1][4:] + match[1][2:4] + match[1][:2]; date > today {
+					future = true
+				}
+			}
+			assert.True(t, future, string(data))
+
+			assert.NotContains(t, issueCodes(formIssues(t, documentType, data)), parser.CodeDateInvalid)
+		})
+	}
+}
+
+func TestFormMissingWitnessOnLPC(t *testing.T) {
+	data, err := New("../../xsd", 1).Form("LPC", MissingWitness)
+	require.NoError(t, err)
+
+	lpc, err := lpc_parser.Parse(data)
+	require.NoError(t, err)
+
+	pages := lpc.(*lpc_types.LPCDocument).Page3
+	require.Len(t, pages, 1)
+	assert.NotEmpty(t, pages[0].ContinuationSheet3.Donor.FullName)
+	assert.NotEmpty(t, pages[0].ContinuationSheet3.Signatory.FullName)
+	for _, witness := range pages[0].ContinuationSheet3.Witnesses {
+		assert.False(t, witness.Signature)
+		assert.Empty(t, witness.FullName)
+	}
+}
+
+func TestFormUnsupported(t *testing.T) {
+	generator := New("../../xsd", 1)
+
+	_, err := generator.Form("LP2", MissingWitness)
+	assert.ErrorIs(t, err, ErrDefectNotApplicable)
+
+	_, err = generator.Form("COPORD")
+	assert.Error(t, err)
+}
+
+func validateSet(t *testing.T, body []byte) *ingestion.Report {
+	t.Helper()
+
+	worker := ingestion.NewWorker(slog.New(slog.DiscardHandler), testConfig(), nil, nil, nil)
+
+	report, err := worker.Validate(context.Background(), body)
+	require.NoError(t, err)
+
+	return report
+}
+
+func TestSet(t *testing.T) {
+	testCases := map[string][]string{
+		"instrument with continuation sheets": {"LP1F", "LPC", "LPA115"},
+		"health and welfare instrument":       {"LP1H"},
+		"registration":                        {"EP2PG", "LPA120"},
+		"correspondence":                      {"Correspondence"},
+	}
+
+	for name, documentTypes := range testCases {
+		t.Run(name, func(t *testing.T) {
+			body, err := New("../../xsd", 3).Set(documentTypes)
+			require.NoError(t, err)
+
+			report := validateSet(t, body)
+			assert.True(t, report.Valid)
+			assert.Empty(t, report.Issues)
+
+			set, err := parser.BaseParserXml(body)
+			require.NoError(t, err)
+			require.Len(t, set.Body.Documents, len(documentTypes))
+			for i, document := range set.Body.Documents {
+				assert.Equal(t, documentTypes[i], document.Type)
+				assert.Positive(t, document.NoPages)
+			}
+
+			if documentTypes[0] == "Correspondence" {
+				assert.NotEmpty(t, set.Header.CaseNo)
+			} else {
+				assert.Empty(t, set.Header.CaseNo)
+			}
+		})
+	}
+}
+
+func TestSetWrongAttorneyCount(t *testing.T) {
+	body, err := New("../../xsd", 3).Set([]string{"LP1F", "LPC"}, WrongAttorneyCount)
+	require.NoError(t, err)
+
+	report := validateSet(t, body)
+	assert.Equal(t, []string{ingestion.CodeContinuationAttorneyCount}, issueCodes(report.Issues))
+}
+
+func TestSetDefectNotApplicable(t *testing.T) {
+	_, err := New("../../xsd", 1).Set([]string{"LP2", "LPA120"}, MissingWitness)
+	assert.ErrorIs(t, err, ErrDefectNotApplicable)
+}
+
+func TestScan(t *testing.T) {
+	for _, pages := range []int{1, 3} {
+		count, err := setbuilder.CountPages(scan(pages))
+		require.NoError(t, err)
+		assert.Equal(t, pages, count)
+	}
+}
@@ -0,0 +1,122 @@
+package synthetic
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// node is an element of a generated form.
+type node struct {
+	schema   *element
+	value    string
+	children []*node
+}
+
+func (n *node) name() string {
+	return n.schema.name
+}
+
+// isLeaf reports whether the element holds a value rather than other elements.
+func (n *node) isLeaf() bool {
+	return len(n.schema.children) == 0
+}
+
+// find returns the elements at a path of element names separated by slashes,
+// such as "Page12/Section11/Attorney". Every element matching each name is
+// followed, so repeated elements all match.
+func (n *node) find(path string) []*node {
+	matches := []*node{n}
+
+	for _, name := range strings.Split(path, "/") {
+		var next []*node
+		for _, match := range matches {
+			for _, child := range match.children {
+				if child.name() == name {
+					next = append(next, child)
+				}
+			}
+		}
+		matches = next
+	}
+
+	return matches
+}
+
+// set sets the value of every element at a path.
+func (n *node) set(path, value string) {
+	for _, match := range n.find(path) {
+		match.value = value
+	}
+}
+
+// descendants returns every element below this one with the given name.
+func (n *node) descendants(name string) []*node {
+	var matches []*node
+	for _, child := range n.children {
+		if child.name() == name {
+			matches = append(matches, child)
+		}
+		matches = append(matches, child.descendants(name)...)
+	}
+
+	return matches
+}
+
+// clear empties the element and everything below it, unticking boxes.
+func (n *node) clear() {
+	if n.isLeaf() {
+		n.value = zeroValue(n.schema)
+	}
+
+	for _, child := range n.children {
+		child.clear()
+	}
+}
+
+// marshal writes the element as an XML document referring to its XSD.
+func (n *node) marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: n.name()},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns:xsi"}, Value: "http://www.w3.org/2001/XMLSchema-instance"},
+			{Name: xml.Name{Local: "xsi:noNamespaceSchemaLocation"}, Value: n.name() + ".xsd"},
+		},
+	}
+
+	if err := n.encode(encoder, start); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func (n *node) encode(encoder *xml.Encoder, start xml.StartElement) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if n.isLeaf() && n.value != "" {
+		if err := encoder.EncodeToken(xml.CharData(n.value)); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range n.children {
+		if err := child.encode(encoder, xml.StartElement{Name: xml.Name{Local: child.name()}}); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,142 @@
+package synthetic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The people on generated forms are made up from these lists. Telephone
+// numbers are from the range Ofcom reserves for drama, and email addresses
+// use the example.com domain, so nothing generated belongs to anyone.
+var (
+	titles      = []string{"Mr", "Mrs", "Ms", "Miss"}
+	firstNames  = []string{"Ada", "Alan", "Amara", "Arthur", "Beatrice", "Callum", "Carys", "Dafydd", "Edith", "Eric", "Fatima", "Florence", "George", "Grace", "Harold", "Imran", "Iris", "Jack", "Joan", "Kwame", "Leila", "Mabel", "Niamh", "Oliver", "Priya", "Rhys", "Rosa", "Samuel", "Siobhan", "Thomas", "Vera", "Wilfred"}
+	lastNames   = []string{"Abbott", "Bevan", "Chowdhury", "Davies", "Ellison", "Fairweather", "Gallagher", "Hughes", "Ibrahim", "Jenkins", "Kowalski", "Lloyd", "McAllister", "Nwosu", "O'Connell", "Patel", "Quigley", "Roberts", "Singh", "Thornton", "Underwood", "Vaughan", "Whitlock", "Yardley"}
+	streets     = []string{"Acacia Avenue", "Beech Grove", "Castle Street", "Church Lane", "High Street", "Mill Road", "Orchard Close", "Park View", "Station Road", "The Green"}
+	towns       = []string{"Ashbourne", "Brackley", "Carnforth", "Dunmow", "Eyemouth", "Fakenham", "Glossop", "Hexham", "Ilkley", "Kendal", "Ludlow", "Malton"}
+	counties    = []string{"Cumbria", "Derbyshire", "Essex", "Norfolk", "Northumberland", "Shropshire", "Yorkshire"}
+	occupations = []string{"Retired", "Teacher", "Nurse", "Engineer", "Accountant", "Shop assistant", "Carer"}
+)
+
+// dateLayout is the layout of dates written on forms by the scanning
+// supplier.
+const dateLayout = "02012006"
+
+type address struct {
+	line1, town, county, postcode string
+}
+
+type person struct {
+	title, firstName, lastName string
+	dob                        time.Time
+	address                    address
+	email, phone               string
+}
+
+func (p *person) fullName() string {
+	return p.firstName + " " + p.lastName
+}
+
+// cast is the people and dates shared by the forms in a set, so that the
+// forms describe the same power of attorney.
+type cast struct {
+	caseNo    string
+	donor     *person
+	attorneys []*person
+	signed    time.Time // when the donor signed; other signatures follow it
+}
+
+func (g *Generator) newCast(caseNo string, attorneys int) *cast {
+	c := &cast{
+		caseNo: caseNo,
+		donor:  g.newPerson(65, 95),
+		signed: g.today().AddDate(0, 0, -30-g.rand.IntN(365)),
+	}
+
+	for range attorneys {
+		c.attorneys = append(c.attorneys, g.newPerson(25, 70))
+	}
+
+	return c
+}
+
+// newPerson makes up a person aged between the given ages.
+func (g *Generator) newPerson(minAge, maxAge int) *person {
+	p := &person{
+		title:     pick(g, titles),
+		firstName: pick(g, firstNames),
+		lastName:  pick(g, lastNames),
+		dob:       g.today().AddDate(-minAge-g.rand.IntN(maxAge-minAge+1), 0, -g.rand.IntN(365)),
+		address: address{
+			line1:    fmt.Sprintf("%d %s", 1+g.rand.IntN(200), pick(g, streets)),
+			town:     pick(g, towns),
+			county:   pick(g, counties),
+			postcode: fmt.Sprintf("%c%c%d %d%c%c", g.letter(), g.letter(), 1+g.rand.IntN(29), g.rand.IntN(10), g.letter(), g.letter()),
+		},
+		phone: fmt.Sprintf("07700 900%03d", g.rand.IntN(1000)),
+	}
+
+	p.email = strings.ToLower(strings.ReplaceAll(p.firstName+"."+p.lastName, "'", "")) + "@example.com"
+
+	return p
+}
+
+// personValue returns the value of an element describing a person, if it is
+// one.
+func personValue(e, parent *element, p *person) (string, bool) {
+	switch e.name {
+	case "Title":
+		return p.title, true
+	case "FirstName", "Forename":
+		return p.firstName, true
+	case "LastName":
+		return p.lastName, true
+	case "FullName":
+		return p.fullName(), true
+	case "OtherNames", "OtherForenames", "MiddleName":
+		return "", true
+	case "DOB":
+		return p.dob.Format(dateLayout), true
+	case "Address1":
+		return p.address.line1, true
+	case "Address2":
+		if parent.child("TownCity") == nil {
+			return p.address.town, true
+		}
+		return "", true
+	case "Address3":
+		if parent.child("County") == nil {
+			return p.address.county, true
+		}
+		return "", true
+	case "Address4":
+		return "", true
+	case "TownCity":
+		return p.address.town, true
+	case "County":
+		return p.address.county, true
+	case "Postcode":
+		return p.address.postcode, true
+	case "Telephone", "PhoneNumber", "Mobile":
+		return p.phone, true
+	case "Email", "EmailAddress":
+		if e.kind == "xs:string" {
+			return p.email, true
+		}
+	}
+
+	if parent.name == "Salutation" && e.kind == "xs:boolean" {
+		return fmt.Sprint(e.name == p.title), true
+	}
+
+	return "", false
+}
+
+func pick[T any](g *Generator, from []T) T {
+	return from[g.rand.IntN(len(from))]
+}
+
+func (g *Generator) letter() rune {
+	return rune('A' + g.rand.IntN(26))
+}
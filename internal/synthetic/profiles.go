@@ -0,0 +1,313 @@
+package synthetic
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+	"github.com/ministryofjustice/opg-scanning/internal/types/corresp_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/ep2pg_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1f_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp1h_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lp2_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpa115_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpa120_types"
+	"github.com/ministryofjustice/opg-scanning/internal/types/lpc_types"
+)
+
+// profile describes how to complete a form of a particular type, once the
+// elements required by its XSD have been generated, and how to make each
+// defect that applies to it. The document is the type the service parses the
+// form into, which decides the elements that are filled in.
+type profile struct {
+	document reflect.Type
+	fill     func(f *form, root *node)
+	defects  map[Defect]func(f *form, root *node)
+}
+
+func (p profile) supports(defect Defect) bool {
+	_, ok := p.defects[defect]
+	return ok
+}
+
+// instrumentDefects are the defects that can be made on an LP1.
+var instrumentDefects = map[Defect]func(f *form, root *node){
+	MissingWitness:     missingWitness,
+	FutureDate:         futureDate,
+	WrongAttorneyCount: wrongAttorneyCount,
+}
+
+// signedDefects are the defects that can be made on forms that are signed and
+// dated, but not witnessed.
+var signedDefects = map[Defect]func(f *form, root *node){
+	FutureDate: futureDate,
+}
+
+var profiles = map[string]profile{
+	constants.DocumentTypeLP1F: {document: reflect.TypeFor[lp1f_types.LP1FDocument](), fill: fillLP1F, defects: instrumentDefects},
+	constants.DocumentTypeLP1H: {document: reflect.TypeFor[lp1h_types.LP1HDocument](), fill: fillLP1H, defects: instrumentDefects},
+	constants.DocumentTypeLP2: {
+		document: reflect.TypeFor[lp2_types.LP2Document](),
+		fill:     fillLP2,
+		defects:  map[Defect]func(f *form, root *node){FutureDate: lp2FutureDate},
+	},
+	constants.DocumentTypeLPC: {
+		document: reflect.TypeFor[lpc_types.LPCDocument](),
+		fill:     fillLPC,
+		defects:  map[Defect]func(f *form, root *node){MissingWitness: lpcMissingWitness, FutureDate: futureDate},
+	},
+	constants.DocumentTypeEP2PG:  {document: reflect.TypeFor[ep2pg_types.EP2PGDocument](), fill: fillEP2PG, defects: signedDefects},
+	constants.DocumentTypeLPA115: {document: reflect.TypeFor[lpa115_types.LPA115Document](), fill: fillLPA115, defects: signedDefects},
+	constants.DocumentTypeLPA120: {document: reflect.TypeFor[lpa120_types.LPA120Document](), fill: fillLPA120, defects: signedDefects},
+	"Correspondence":             {document: reflect.TypeFor[corresp_types.Correspondence](), fill: fillCorrespondence},
+}
+
+func fillLP1F(f *form, root *node) {
+	fillLP1(f, root)
+	root.set("Page10/Section9/Donor/Date", f.cast.signed.Format(dateLayout))
+}
+
+// fillLP1H completes an LP1H, on which the donor records the date they signed
+// in an element named DOB. The donor must have signed section 5 no earlier
+// than section 9.
+func fillLP1H(f *form, root *node) {
+	fillLP1(f, root)
+
+	signed := f.cast.signed.Format(dateLayout)
+	root.set("Page6/Section5/OptionA/DOB", signed)
+	for _, optionB := range root.find("Page6/Section5/OptionB") {
+		optionB.clear()
+	}
+	root.set("Page10/Section9/Donor/DOB", signed)
+}
+
+// fillLP1 completes the parts of the LP1F and LP1H that are the same. The
+// donor applies to register, and is the correspondent.
+func fillLP1(f *form, root *node) {
+	attorneys := f.cast.attorneys
+
+	fillPerson(root.find("Page1/Section1"), f.cast.donor)
+
+	var slots []*node
+	for _, path := range []string{"Page2/Section2/Attorney1", "Page2/Section2/Attorney2", "Page3/Section2/Attorney"} {
+		slots = append(slots, root.find(path)...)
+	}
+	for i, slot := range slots {
+		if i < len(attorneys) {
+			fillPerson([]*node{slot}, attorneys[i])
+		} else {
+			slot.clear()
+		}
+	}
+
+	root.set("Page3/Section2/MoreAttorneys", strconv.FormatBool(len(attorneys) > len(slots)))
+	root.set("Page4/Section3/AppointedOneAttorney", strconv.FormatBool(len(attorneys) == 1))
+	root.set("Page4/Section3/JointlyAndSeverally", strconv.FormatBool(len(attorneys) > 1))
+
+	// Each attorney signs their own copy of section 11.
+	for i, attorney := range attorneys {
+		page := root.find("Page12")[0]
+		if i > 0 {
+			page = f.add(root, "Page12")
+		}
+		fillPerson(page.find("Section11/Attorney"), attorney)
+	}
+
+	root.set("Page17/Section12/DonorApply", "true")
+	for _, attorney := range root.find("Page17/Section12/Attorney") {
+		attorney.clear()
+	}
+
+	for _, section := range root.find("Page18/Section13") {
+		section.clear()
+		section.set("TheDonor", "true")
+	}
+
+	root.set("Page19/Section14/Cheque", "true")
+
+	applied := f.cast.signed.AddDate(0, 0, 21).Format(dateLayout)
+	for _, page := range root.find("Page20") {
+		for i, applicant := range page.find("Section15/Applicant") {
+			if i > 0 {
+				applicant.clear()
+				continue
+			}
+			applicant.set("Signature", "true")
+			applicant.set("Date", applied)
+		}
+	}
+}
+
+// fillLP2 completes an LP2 on which the donor applies to register.
+func fillLP2(f *form, root *node) {
+	fillPerson(root.find("Page1/Section1"), f.cast.donor)
+
+	propertyAndAffairs := f.rand.IntN(2) == 0
+	root.set("Page1/Section1/PropertyFinancialAffairs", strconv.FormatBool(propertyAndAffairs))
+	root.set("Page1/Section1/HealthWelfare", strconv.FormatBool(!propertyAndAffairs))
+
+	root.set("Page2/Section2/DonorRegisteration", "true")
+	for _, path := range []string{"Page2/Section2/Attorney", "Page5/Section5/Attorney", "Page6/Section6/Addresses"} {
+		for _, n := range root.find(path) {
+			n.clear()
+		}
+	}
+
+	for _, section := range root.find("Page3/Section3") {
+		section.clear()
+		section.set("TheDonor", "true")
+	}
+
+	root.set("Page4/Section4/Cheque", "true")
+}
+
+// fillLPC completes continuation sheets naming the attorneys that do not fit
+// on the instrument.
+func fillLPC(f *form, root *node) {
+	attorneys := f.cast.attorneys[min(len(f.cast.attorneys), 4):]
+
+	for i := 0; i < len(attorneys); i += 2 {
+		page := f.add(root, "Page1")
+
+		for j, block := range page.find("ContinuationSheet1/Attorney") {
+			if i+j >= len(attorneys) {
+				block.clear()
+				continue
+			}
+
+			fillPerson([]*node{block}, attorneys[i+j])
+			block.set("Attorney", "true")
+		}
+
+		page.set("ContinuationSheet1/Donor/FullName", f.cast.donor.fullName())
+	}
+}
+
+// fillEP2PG completes an EP2PG on which the first attorney applies to
+// register.
+func fillEP2PG(f *form, root *node) {
+	fillPerson(root.find("Page1/Part1"), f.cast.donor)
+	fillPerson(root.find("Page2/Part2/Attorney"), f.cast.attorneys[0])
+	root.set("Page2/Part2/Attorney/JointlyAndSeverally", "true")
+	fillPerson(root.find("Page3/Part3/Attorney"), f.cast.attorneys[1])
+}
+
+// fillLPA115 completes an LPA115 signed by the first attorney and a
+// certificate provider.
+func fillLPA115(f *form, root *node) {
+	page3 := f.add(root, "Page3")
+	page3.set("PartA/FullName", f.cast.attorneys[0].fullName())
+	page3.set("PartA/OptionB/Signature", "false")
+
+	f.add(root, "Page6")
+}
+
+// fillLPA120 completes a fee remission application made by the donor.
+func fillLPA120(f *form, root *node) {
+	fillPerson(root.find("Page3/Section1"), f.cast.donor)
+	root.set("Page3/Section1/LPAPropertyFinance", "true")
+	root.set("Page3/Section2/Relationship/Donor", "true")
+	fillPerson(root.find("Page3/Section2"), f.cast.donor)
+
+	for _, answer := range root.descendants("YesorNo") {
+		answer.set("No", "true")
+	}
+}
+
+// fillCorrespondence completes a letter about the case, of a few pages.
+func fillCorrespondence(f *form, root *node) {
+	root.set("SubType", "Legal")
+	root.set("CaseNumber", f.cast.caseNo)
+
+	for range f.rand.IntN(3) {
+		f.add(root, "Page")
+	}
+}
+
+// missingWitness leaves every witness section blank.
+func missingWitness(_ *form, root *node) {
+	for _, name := range []string{"Witness", "Witnesses"} {
+		for _, witness := range root.descendants(name) {
+			witness.clear()
+		}
+	}
+}
+
+// futureDate moves every signature on the form into the future, keeping the
+// order in which they were made.
+func futureDate(f *form, root *node) {
+	shift := f.today().AddDate(0, 0, 1+f.rand.IntN(30)).Sub(f.cast.signed)
+
+	var signatureBlocks []*node
+	for _, name := range []string{"Signature", "Signed"} {
+		for _, signature := range root.descendants(name) {
+			signatureBlocks = append(signatureBlocks, parentOf(root, signature))
+		}
+	}
+
+	for _, block := range signatureBlocks {
+		for _, child := range block.children {
+			if child.name() != "Date" && child.name() != "DOB" {
+				continue
+			}
+
+			if signed, err := time.Parse(dateLayout, child.value); err == nil {
+				child.value = signed.Add(shift).Format(dateLayout)
+			}
+		}
+	}
+}
+
+// lp2FutureDate has the attorneys apply to register, as only they sign an LP2,
+// and date their signatures in the future.
+func lp2FutureDate(f *form, root *node) {
+	attorneys := f.cast.attorneys[:min(len(f.cast.attorneys), 4)]
+
+	root.set("Page2/Section2/DonorRegisteration", "false")
+	root.set("Page2/Section2/AttorneyRegisteration", "true")
+
+	for i, attorney := range root.find("Page2/Section2/Attorney") {
+		if i < len(attorneys) {
+			fillPerson([]*node{attorney}, attorneys[i])
+		}
+	}
+	for i, signature := range root.find("Page5/Section5/Attorney") {
+		if i < len(attorneys) {
+			signature.set("Signature", "true")
+			signature.set("Date", f.signatureDate())
+		}
+	}
+
+	futureDate(f, root)
+}
+
+// lpcMissingWitness adds a continuation sheet signed for a donor who cannot
+// sign, without the two witnesses it needs.
+func lpcMissingWitness(f *form, root *node) {
+	page := f.add(root, "Page3")
+	page.set("ContinuationSheet3/Donor/FullName", f.cast.donor.fullName())
+
+	missingWitness(f, page)
+}
+
+// wrongAttorneyCount says one attorney is appointed, and that there are no
+// more, whatever the number of attorneys named.
+func wrongAttorneyCount(_ *form, root *node) {
+	root.set("Page4/Section3/AppointedOneAttorney", "true")
+	root.set("Page4/Section3/JointlyAndSeverally", "false")
+	root.set("Page3/Section2/MoreAttorneys", "false")
+}
+
+func parentOf(root, n *node) *node {
+	for _, child := range root.children {
+		if child == n {
+			return root
+		}
+		if parent := parentOf(child, n); parent != nil {
+			return parent
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package synthetic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// TIFF tags used to describe a page.
+const (
+	tagImageWidth                = 256
+	tagImageLength               = 257
+	tagBitsPerSample             = 258
+	tagCompression               = 259
+	tagPhotometricInterpretation = 262
+	tagStripOffsets              = 273
+	tagRowsPerStrip              = 278
+	tagStripByteCounts           = 279
+)
+
+// scan returns a TIFF of blank pages, each a single white pixel, to stand in
+// for the scan of a form.
+func scan(pages int) []byte {
+	order := binary.LittleEndian
+	data := order.AppendUint32([]byte("II*\x00"), 8)
+
+	for i := range pages {
+		// Each page is its pixel, padded to a word, followed by its directory
+		// of eight entries.
+		pixel := uint32(len(data))
+		data = append(data, 0, 0)
+
+		entries := []struct {
+			tag, kind uint16
+			value     uint32
+		}{
+			{tagImageWidth, 3, 1},
+			{tagImageLength, 3, 1},
+			{tagBitsPerSample, 3, 1},
+			{tagCompression, 3, 1},
+			{tagPhotometricInterpretation, 3, 0},
+			{tagStripOffsets, 4, pixel},
+			{tagRowsPerStrip, 3, 1},
+			{tagStripByteCounts, 4, 1},
+		}
+
+		data = order.AppendUint16(data, uint16(len(entries)))
+		for _, entry := range entries {
+			data = order.AppendUint16(data, entry.tag)
+			data = order.AppendUint16(data, entry.kind)
+			data = order.AppendUint32(data, 1)
+			data = order.AppendUint32(data, entry.value)
+		}
+
+		next := uint32(0)
+		if i < pages-1 {
+			next = uint32(len(data)) + 4 + 2
+		}
+		data = order.AppendUint32(data, next)
+	}
+
+	// The first directory follows the first pixel.
+	order.PutUint32(data[4:8], 10)
+
+	return data
+}
+
+// blankPDF returns a PDF of blank A4 pages.
+func blankPDF(pages int) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(format string, args ...any) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\nendobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", i+3))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+	for range pages {
+		object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,192 @@
+package synthetic
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// maxSchemaDepth stops a schema that refers to itself from being resolved
+// forever.
+const maxSchemaDepth = 32
+
+// element is an element declared in an XSD, with references to top-level
+// elements resolved. The form XSDs only use sequences of elements with the
+// built-in simple types, so that is all that is supported.
+type element struct {
+	name      string
+	kind      string // the type of a simple element, empty for complex ones
+	maxLength int
+	minOccurs int
+	maxOccurs int // -1 when unbounded
+	children  []*element
+}
+
+// child returns the declaration of the named child element.
+func (e *element) child(name string) *element {
+	for _, child := range e.children {
+		if child.name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// xsdNode is a generic node of an XSD document.
+type xsdNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xsdNode  `xml:",any"`
+}
+
+func (n xsdNode) attr(name string) string {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// loadSchema reads the XSD for a document type, returning the declaration of
+// its root element.
+func loadSchema(xsdPath, documentType string) (*element, error) {
+	data, err := os.ReadFile(filepath.Join(xsdPath, documentType+".xsd")) //#nosec G304 the document type is checked against the supported types
+	if err != nil {
+		return nil, err
+	}
+
+	var schema xsdNode
+	if err := xml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse %s.xsd: %w", documentType, err)
+	}
+
+	globals := map[string]xsdNode{}
+	for _, child := range schema.Children {
+		if child.XMLName.Local == "element" {
+			globals[child.attr("name")] = child
+		}
+	}
+
+	root, ok := globals[documentType]
+	if !ok {
+		return nil, fmt.Errorf("%s.xsd does not declare a %s element", documentType, documentType)
+	}
+
+	return resolve(root, globals, 0)
+}
+
+// resolve builds the declaration of an element from the XSD, following any
+// reference to a top-level element.
+func resolve(n xsdNode, globals map[string]xsdNode, depth int) (*element, error) {
+	if depth > maxSchemaDepth {
+		return nil, errors.New("schema is nested too deeply")
+	}
+
+	e := &element{}
+
+	if ref := n.attr("ref"); ref != "" {
+		global, ok := globals[ref]
+		if !ok {
+			return nil, fmt.Errorf("element %q is not declared", ref)
+		}
+
+		resolved, err := resolve(global, globals, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		*e = *resolved
+	} else {
+		e.name = n.attr("name")
+		e.kind = n.attr("type")
+
+		for _, child := range n.Children {
+			switch child.XMLName.Local {
+			case "complexType":
+				children, err := resolveSequence(child, globals, depth)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", e.name, err)
+				}
+				e.children = children
+
+			case "simpleType":
+				for _, restriction := range child.Children {
+					if restriction.XMLName.Local != "restriction" {
+						continue
+					}
+
+					e.kind = restriction.attr("base")
+					for _, facet := range restriction.Children {
+						if facet.XMLName.Local == "maxLength" {
+							e.maxLength, _ = strconv.Atoi(facet.attr("value"))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var err error
+	if e.minOccurs, err = occurs(n.attr("minOccurs")); err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+	if e.maxOccurs, err = occurs(n.attr("maxOccurs")); err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+
+	return e, nil
+}
+
+// resolveSequence resolves the elements within a complex type.
+func resolveSequence(n xsdNode, globals map[string]xsdNode, depth int) ([]*element, error) {
+	var children []*element
+
+	for _, child := range n.Children {
+		switch child.XMLName.Local {
+		case "sequence":
+			elements, err := resolveSequence(child, globals, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			// A sequence may itself repeat, as in the EP2PG declaration.
+			repeats, err := occurs(child.attr("minOccurs"))
+			if err != nil {
+				return nil, err
+			}
+			for range max(repeats, 1) {
+				children = append(children, elements...)
+			}
+
+		case "element":
+			e, err := resolve(child, globals, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, e)
+
+		case "annotation", "attribute":
+
+		default:
+			return nil, fmt.Errorf("unsupported schema component %q", child.XMLName.Local)
+		}
+	}
+
+	return children, nil
+}
+
+func occurs(value string) (int, error) {
+	switch value {
+	case "":
+		return 1, nil
+	case "unbounded":
+		return -1, nil
+	}
+
+	return strconv.Atoi(value)
+}
@@ -31,6 +31,6 @@ type BaseDocument struct {
 }
 
 type BasePage struct {
-	BURN         string `xml:"BURN"`
-	PhysicalPage string `xml:"PhysicalPage"`
+	BURN         string `xml:"BURN" json:"burn"`
+	PhysicalPage string `xml:"PhysicalPage" json:"physicalPage"`
 }
@@ -7,8 +7,8 @@ import (
 )
 
 type Correspondence struct {
-	XMLName    xml.Name         `xml:"Correspondence"`
-	SubType    string           `xml:"SubType"`
-	CaseNumber []string         `xml:"CaseNumber"`
-	Page       []types.BasePage `xml:"Page"`
+	XMLName    xml.Name         `xml:"Correspondence" json:"-"`
+	SubType    string           `xml:"SubType" json:"subType"`
+	CaseNumber []string         `xml:"CaseNumber" json:"caseNumber"`
+	Page       []types.BasePage `xml:"Page" json:"page"`
 }
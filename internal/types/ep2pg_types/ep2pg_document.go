@@ -8,178 +8,178 @@ import (
 )
 
 type EP2PGDocument struct {
-	XMLName  xml.Name   `xml:"EP2PG"`
-	Page1    Page1      `xml:"Page1"`
-	Page2    Page2      `xml:"Page2"`
-	Page3    Page3      `xml:"Page3"`
-	Page4    Page4      `xml:"Page4"`
-	Page5    Page5      `xml:"Page5"`
-	Page6    Page6      `xml:"Page6"`
-	Page7    Page7      `xml:"Page7"`
-	InfoPage []types.BasePage
+	XMLName  xml.Name         `xml:"EP2PG" json:"-"`
+	Page1    Page1            `xml:"Page1" json:"page1"`
+	Page2    Page2            `xml:"Page2" json:"page2"`
+	Page3    Page3            `xml:"Page3" json:"page3"`
+	Page4    Page4            `xml:"Page4" json:"page4"`
+	Page5    Page5            `xml:"Page5" json:"page5"`
+	Page6    Page6            `xml:"Page6" json:"page6"`
+	Page7    Page7            `xml:"Page7" json:"page7"`
+	InfoPage []types.BasePage `json:"infoPage"`
 }
 
 type Page1 struct {
-	Part1        Part1  `xml:"Part1"`
+	Part1 Part1 `xml:"Part1" json:"part1"`
 	types.BasePage
 }
 
 type Part1 struct {
-	Name        lp1f_types.PersonName `xml:"Name"`
-	CompanyName string                `xml:"CompanyName"`
-	Address     lp1f_types.Address    `xml:"Address"`
-	DOB         string                `xml:"DOB"`
+	Name        lp1f_types.PersonName `xml:"Name" json:"name"`
+	CompanyName string                `xml:"CompanyName" json:"companyName"`
+	Address     lp1f_types.Address    `xml:"Address" json:"address"`
+	DOB         string                `xml:"DOB" json:"dob"`
 }
 
 type Page2 struct {
-	Part2        Part2  `xml:"Part2"`
+	Part2 Part2 `xml:"Part2" json:"part2"`
 	types.BasePage
 }
 
 type Part2 struct {
-	Attorney Attorney `xml:"Attorney"`
+	Attorney Attorney `xml:"Attorney" json:"attorney"`
 }
 
 type Attorney struct {
-	Name         lp1f_types.PersonName `xml:"Name"`
-	CompanyName  string                `xml:"CompanyName"`
-	Address      lp1f_types.Address    `xml:"Address"`
-	DXDetails    DXDetails             `xml:"DXDetails"`
-	DOB          string                `xml:"DOB"`
-	Telephone    string                `xml:"Telephone"`
-	Email        string                `xml:"Email"`
-	Occupation   string                `xml:"Occupation"`
-	Relationship EP2PGRelationship     `xml:"Relationship"`
+	Name         lp1f_types.PersonName `xml:"Name" json:"name"`
+	CompanyName  string                `xml:"CompanyName" json:"companyName"`
+	Address      lp1f_types.Address    `xml:"Address" json:"address"`
+	DXDetails    DXDetails             `xml:"DXDetails" json:"dxDetails"`
+	DOB          string                `xml:"DOB" json:"dob"`
+	Telephone    string                `xml:"Telephone" json:"telephone"`
+	Email        string                `xml:"Email" json:"email"`
+	Occupation   string                `xml:"Occupation" json:"occupation"`
+	Relationship EP2PGRelationship     `xml:"Relationship" json:"relationship"`
 }
 
 type DXDetails struct {
-	DXNumber   string `xml:"DXNumber"`
-	DXExchange string `xml:"DXExchange"`
+	DXNumber   string `xml:"DXNumber" json:"dxNumber"`
+	DXExchange string `xml:"DXExchange" json:"dxExchange"`
 }
 
 type EP2PGRelationship struct {
-	CivilPartnerSpouse bool   `xml:"CivilPartnerSpouse"`
-	Child              bool   `xml:"Child"`
-	OtherRelation      bool   `xml:"OtherRelation"`
-	NoRelation         bool   `xml:"NoRelation"`
-	Solicitor          bool   `xml:"Solicitor"`
-	OtherProfessional  bool   `xml:"OtherProfessional"`
-	OtherName          string `xml:"OtherName"`
+	CivilPartnerSpouse bool   `xml:"CivilPartnerSpouse" json:"civilPartnerSpouse"`
+	Child              bool   `xml:"Child" json:"child"`
+	OtherRelation      bool   `xml:"OtherRelation" json:"otherRelation"`
+	NoRelation         bool   `xml:"NoRelation" json:"noRelation"`
+	Solicitor          bool   `xml:"Solicitor" json:"solicitor"`
+	OtherProfessional  bool   `xml:"OtherProfessional" json:"otherProfessional"`
+	OtherName          string `xml:"OtherName" json:"otherName"`
 }
 
 type Page3 struct {
-	Part3        Part3  `xml:"Part3"`
-	Part4        Part4  `xml:"Part4"`
+	Part3 Part3 `xml:"Part3" json:"part3"`
+	Part4 Part4 `xml:"Part4" json:"part4"`
 	types.BasePage
 }
 
 type Part3 struct {
-	Attorney Attorney `xml:"Attorney"`
+	Attorney Attorney `xml:"Attorney" json:"attorney"`
 }
 
 type Part4 struct {
-	Salutation lp1f_types.Salutation `xml:"Salutation"`
-	LastName   string                `xml:"LastName"`
-	Forename   string                `xml:"Forename"`
+	Salutation lp1f_types.Salutation `xml:"Salutation" json:"salutation"`
+	LastName   string                `xml:"LastName" json:"lastName"`
+	Forename   string                `xml:"Forename" json:"forename"`
 }
 
 type Page4 struct {
-	Part4        Page4Part4 `xml:"Part4"`
-	Part5        Part5      `xml:"Part5"`
+	Part4 Page4Part4 `xml:"Part4" json:"part4"`
+	Part5 Part5      `xml:"Part5" json:"part5"`
 	types.BasePage
 }
 
 type Page4Part4 struct {
-	OtherForenames string              `xml:"OtherForenames"`
-	CompanyName    string              `xml:"CompanyName"`
-	Address        lp1f_types.Address  `xml:"Address"`
-	DXDetails      DXDetails           `xml:"DXDetails"`
-	DOB            string              `xml:"DOB"`
-	Telephone      string              `xml:"Telephone"`
-	Email          string              `xml:"Email"`
-	Occupation     string              `xml:"Occupation"`
-	Relationship   EP2PGRelationship   `xml:"Relationship"`
+	OtherForenames string             `xml:"OtherForenames" json:"otherForenames"`
+	CompanyName    string             `xml:"CompanyName" json:"companyName"`
+	Address        lp1f_types.Address `xml:"Address" json:"address"`
+	DXDetails      DXDetails          `xml:"DXDetails" json:"dxDetails"`
+	DOB            string             `xml:"DOB" json:"dob"`
+	Telephone      string             `xml:"Telephone" json:"telephone"`
+	Email          string             `xml:"Email" json:"email"`
+	Occupation     string             `xml:"Occupation" json:"occupation"`
+	Relationship   EP2PGRelationship  `xml:"Relationship" json:"relationship"`
 }
 
 type Part5 struct {
-	Date    string               `xml:"Date"`
-	YesorNo lp1f_types.YesOrNo   `xml:"YesorNo"`
-	Details string               `xml:"Details"`
+	Date    string             `xml:"Date" json:"date"`
+	YesorNo lp1f_types.YesOrNo `xml:"YesorNo" json:"yesorNo"`
+	Details string             `xml:"Details" json:"details"`
 }
 
 type Page5 struct {
-	Part6        Part6  `xml:"Part6"`
-	Part7        Part7  `xml:"Part7"`
+	Part6 Part6 `xml:"Part6" json:"part6"`
+	Part7 Part7 `xml:"Part7" json:"part7"`
 	types.BasePage
 }
 
 type Part6 struct {
-	Date     string               `xml:"Date"`
-	Address  lp1f_types.Address   `xml:"Address"`
-	FullName string               `xml:"FullName"`
+	Date     string             `xml:"Date" json:"date"`
+	Address  lp1f_types.Address `xml:"Address" json:"address"`
+	FullName string             `xml:"FullName" json:"fullName"`
 }
 
 type Part7 struct {
-	NoneEntitled bool       `xml:"NoneEntitled"`
-	Relative     []Relative `xml:"Relative"`
+	NoneEntitled bool       `xml:"NoneEntitled" json:"noneEntitled"`
+	Relative     []Relative `xml:"Relative" json:"relative"`
 }
 
 type Relative struct {
-	FullName    string `xml:"FullName"`
-	Relationship string `xml:"Relationship"`
-	Address1    string `xml:"Address1"`
-	Address2    string `xml:"Address2"`
-	Address3    string `xml:"Address3"`
-	Date        string `xml:"Date"`
+	FullName     string `xml:"FullName" json:"fullName"`
+	Relationship string `xml:"Relationship" json:"relationship"`
+	Address1     string `xml:"Address1" json:"address1"`
+	Address2     string `xml:"Address2" json:"address2"`
+	Address3     string `xml:"Address3" json:"address3"`
+	Date         string `xml:"Date" json:"date"`
 }
 
 type Page6 struct {
-	Part8        Part8  `xml:"Part8"`
-	Part9        Part9  `xml:"Part9"`
-	Part10       Part10 `xml:"Part10"`
-	BURN         string `xml:"BURN"`
-	PhysicalPage int    `xml:"PhysicalPage"`
+	Part8        Part8  `xml:"Part8" json:"part8"`
+	Part9        Part9  `xml:"Part9" json:"part9"`
+	Part10       Part10 `xml:"Part10" json:"part10"`
+	BURN         string `xml:"BURN" json:"burn"`
+	PhysicalPage int    `xml:"PhysicalPage" json:"physicalPage"`
 }
 
 type Part8 struct {
-	YesorNo  lp1f_types.YesOrNo `xml:"YesorNo"`
-	Relative []Relative         `xml:"Relative"`
+	YesorNo  lp1f_types.YesOrNo `xml:"YesorNo" json:"yesorNo"`
+	Relative []Relative         `xml:"Relative" json:"relative"`
 }
 
 type Part9 struct {
-	ChequeFee          ChequeFee          `xml:"ChequeFee"`
-	ExemptionRemission ExemptionRemission `xml:"ExemptionRemission"`
+	ChequeFee          ChequeFee          `xml:"ChequeFee" json:"chequeFee"`
+	ExemptionRemission ExemptionRemission `xml:"ExemptionRemission" json:"exemptionRemission"`
 }
 
 type ChequeFee struct {
-	YesorNo lp1f_types.YesOrNo `xml:"YesorNo"`
+	YesorNo lp1f_types.YesOrNo `xml:"YesorNo" json:"yesorNo"`
 }
 
 type ExemptionRemission struct {
-	YesorNo lp1f_types.YesOrNo `xml:"YesorNo"`
+	YesorNo lp1f_types.YesOrNo `xml:"YesorNo" json:"yesorNo"`
 }
 
 type Part10 struct {
-	Declaration lp1f_types.Declaration `xml:"Declaration"`
+	Declaration lp1f_types.Declaration `xml:"Declaration" json:"declaration"`
 }
 
 type Page7 struct {
-	Part11       Part11 `xml:"Part11"`
-	Part12       Part12 `xml:"Part12"`
-	BURN         string `xml:"BURN"`
-	PhysicalPage int    `xml:"PhysicalPage"`
+	Part11       Part11 `xml:"Part11" json:"part11"`
+	Part12       Part12 `xml:"Part12" json:"part12"`
+	BURN         string `xml:"BURN" json:"burn"`
+	PhysicalPage int    `xml:"PhysicalPage" json:"physicalPage"`
 }
 
 type Part11 struct {
-	Name             lp1f_types.PersonName `xml:"Name"`
-	CompanyName      string                `xml:"CompanyName"`
-	CompanyReference string                `xml:"CompanyReference"`
-	Address          lp1f_types.Address    `xml:"Address"`
-	DXDetails        DXDetails             `xml:"DXDetails"`
-	Telephone        string                `xml:"Telephone"`
-	Email            string                `xml:"Email"`
+	Name             lp1f_types.PersonName `xml:"Name" json:"name"`
+	CompanyName      string                `xml:"CompanyName" json:"companyName"`
+	CompanyReference string                `xml:"CompanyReference" json:"companyReference"`
+	Address          lp1f_types.Address    `xml:"Address" json:"address"`
+	DXDetails        DXDetails             `xml:"DXDetails" json:"dxDetails"`
+	Telephone        string                `xml:"Telephone" json:"telephone"`
+	Email            string                `xml:"Email" json:"email"`
 }
 
 type Part12 struct {
-	AdditionalInfo string `xml:"AdditionalInfo"`
-}
\ No newline at end of file
+	AdditionalInfo string `xml:"AdditionalInfo" json:"additionalInfo"`
+}
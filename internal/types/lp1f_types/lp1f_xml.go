@@ -3,314 +3,319 @@ package lp1f_types
 import "github.com/ministryofjustice/opg-scanning/internal/types"
 
 type Address struct {
-	Address1 string `xml:"Address1"`
-	Address2 string `xml:"Address2,omitempty"`
-	Address3 string `xml:"Address3,omitempty"`
-	Postcode string `xml:"Postcode"`
+	Address1 string `xml:"Address1" json:"address1"`
+	Address2 string `xml:"Address2,omitempty" json:"address2"`
+	Address3 string `xml:"Address3,omitempty" json:"address3"`
+	Postcode string `xml:"Postcode" json:"postcode"`
 }
 
 type Salutation struct {
-	Mr        bool   `xml:"Mr"`
-	Mrs       bool   `xml:"Mrs"`
-	Ms        bool   `xml:"Ms"`
-	Miss      bool   `xml:"Miss"`
-	Other     bool   `xml:"Other"`
-	OtherName string `xml:"OtherName,omitempty"`
+	Mr        bool   `xml:"Mr" json:"mr"`
+	Mrs       bool   `xml:"Mrs" json:"mrs"`
+	Ms        bool   `xml:"Ms" json:"ms"`
+	Miss      bool   `xml:"Miss" json:"miss"`
+	Other     bool   `xml:"Other" json:"other"`
+	OtherName string `xml:"OtherName,omitempty" json:"otherName"`
 }
 
 type PersonName struct {
-	Salutation     Salutation `xml:"Salutation"`
-	LastName       string     `xml:"LastName"`
-	Forename       string     `xml:"Forename"`
-	OtherForenames string     `xml:"OtherForenames,omitempty"`
+	Salutation     Salutation `xml:"Salutation" json:"salutation"`
+	LastName       string     `xml:"LastName" json:"lastName"`
+	Forename       string     `xml:"Forename" json:"forename"`
+	OtherForenames string     `xml:"OtherForenames,omitempty" json:"otherForenames"`
 }
 
 type Declaration struct {
-	Signature bool   `xml:"Signature,omitempty"`
-	Date      string `xml:"Date,omitempty"`
+	Signature bool   `xml:"Signature,omitempty" json:"signature"`
+	Date      string `xml:"Date,omitempty" json:"date"`
 }
 
 type Notification struct {
-	NoticeDate string  `xml:"NoticeDate"`
-	LastName   string  `xml:"LastName"`
-	FirstName  string  `xml:"FirstName"`
-	Address    Address `xml:"Address"`
+	NoticeDate string  `xml:"NoticeDate" json:"noticeDate"`
+	LastName   string  `xml:"LastName" json:"lastName"`
+	FirstName  string  `xml:"FirstName" json:"firstName"`
+	Address    Address `xml:"Address" json:"address"`
 }
 
 type Appointment struct {
-	Jointly             bool `xml:"Jointly"`
-	JointlyAndSeverally bool `xml:"JointlyAndSeverally"`
-	Alone               bool `xml:"Alone"`
+	Jointly             bool `xml:"Jointly" json:"jointly"`
+	JointlyAndSeverally bool `xml:"JointlyAndSeverally" json:"jointlyAndSeverally"`
+	Alone               bool `xml:"Alone" json:"alone"`
 }
 
 type Attorney struct {
-	Title            string  `xml:"Title"`
-	FirstName        string  `xml:"FirstName"`
-	LastName         string  `xml:"LastName"`
-	DOB              string  `xml:"DOB,omitempty"`
-	Address          Address `xml:"Address,omitempty"`
-	EmailAddress     string  `xml:"EmailAddress,omitempty"`
-	TrustCorporation *bool   `xml:"TrustCorporation,omitempty"`
+	Title            string  `xml:"Title" json:"title"`
+	FirstName        string  `xml:"FirstName" json:"firstName"`
+	LastName         string  `xml:"LastName" json:"lastName"`
+	DOB              string  `xml:"DOB,omitempty" json:"dob"`
+	Address          Address `xml:"Address,omitempty" json:"address"`
+	EmailAddress     string  `xml:"EmailAddress,omitempty" json:"emailAddress"`
+	TrustCorporation *bool   `xml:"TrustCorporation,omitempty" json:"trustCorporation"`
 	Declaration
 }
 
 type PeopleToNotify struct {
-	Title     string  `xml:"Title"`
-	FirstName string  `xml:"FirstName"`
-	LastName  string  `xml:"LastName"`
-	Address   Address `xml:"Address"`
+	Title     string  `xml:"Title" json:"title"`
+	FirstName string  `xml:"FirstName" json:"firstName"`
+	LastName  string  `xml:"LastName" json:"lastName"`
+	Address   Address `xml:"Address" json:"address"`
 }
 
 type SkillCertification struct {
-	RegisteredProfessional     bool   `xml:"RegisteredProfessional"`
-	BarristerSolicitorAdvocate bool   `xml:"BarristerSolicitorAdvocate"`
-	SocialWorker               bool   `xml:"SocialWorker"`
-	IMCA                       bool   `xml:"IMCA"`
-	NoneOfTheAbove             bool   `xml:"NoneOfTheAbove"`
-	SkillsAndExpertise         string `xml:"SkillsAndExpertise,omitempty"`
+	RegisteredProfessional     bool   `xml:"RegisteredProfessional" json:"registeredProfessional"`
+	BarristerSolicitorAdvocate bool   `xml:"BarristerSolicitorAdvocate" json:"barristerSolicitorAdvocate"`
+	SocialWorker               bool   `xml:"SocialWorker" json:"socialWorker"`
+	IMCA                       bool   `xml:"IMCA" json:"imca"`
+	NoneOfTheAbove             bool   `xml:"NoneOfTheAbove" json:"noneOfTheAbove"`
+	SkillsAndExpertise         string `xml:"SkillsAndExpertise,omitempty" json:"skillsAndExpertise"`
 }
 
 type Witness struct {
-	Signature bool    `xml:"Signature"`
-	FullName  string  `xml:"FullName"`
-	Address   Address `xml:"Address"`
+	Signature bool    `xml:"Signature" json:"signature"`
+	FullName  string  `xml:"FullName" json:"fullName"`
+	Address   Address `xml:"Address" json:"address"`
 }
 
 type YesOrNo struct {
-	Yes bool `xml:"Yes"`
-	No  bool `xml:"No"`
+	Yes bool `xml:"Yes" json:"yes"`
+	No  bool `xml:"No" json:"no"`
 }
 
 type Section1 struct {
-	Title        string  `xml:"Title"`
-	FirstName    string  `xml:"FirstName"`
-	LastName     string  `xml:"LastName"`
-	OtherNames   string  `xml:"OtherNames,omitempty"`
-	DOB          string  `xml:"DOB"`
-	Address      Address `xml:"Address"`
-	EmailAddress string  `xml:"EmailAddress"`
+	Title        string  `xml:"Title" json:"title"`
+	FirstName    string  `xml:"FirstName" json:"firstName"`
+	LastName     string  `xml:"LastName" json:"lastName"`
+	OtherNames   string  `xml:"OtherNames,omitempty" json:"otherNames"`
+	DOB          string  `xml:"DOB" json:"dob"`
+	Address      Address `xml:"Address" json:"address"`
+	EmailAddress string  `xml:"EmailAddress" json:"emailAddress"`
 }
 
 type Section2 struct {
-	Attorney1 Attorney `xml:"Attorney1"`
-	Attorney2 Attorney `xml:"Attorney2"`
+	Attorney1 Attorney `xml:"Attorney1" json:"attorney1"`
+	Attorney2 Attorney `xml:"Attorney2" json:"attorney2"`
+}
+
+type Section2Continued struct {
+	Attorney      []Attorney `xml:"Attorney" json:"attorney"`
+	MoreAttorneys bool       `xml:"MoreAttorneys" json:"moreAttorneys"`
 }
 
 type Section3 struct {
-	AppointedOneAttorney bool `xml:"AppointedOneAttorney"`
-	JointlyAndSeverally  bool `xml:"JointlyAndSeverally"`
-	Jointly              bool `xml:"Jointly"`
-	JointlyForSome       bool `xml:"JointlyForSome"`
+	AppointedOneAttorney bool `xml:"AppointedOneAttorney" json:"appointedOneAttorney"`
+	JointlyAndSeverally  bool `xml:"JointlyAndSeverally" json:"jointlyAndSeverally"`
+	Jointly              bool `xml:"Jointly" json:"jointly"`
+	JointlyForSome       bool `xml:"JointlyForSome" json:"jointlyForSome"`
 }
 
 type Section4 struct {
-	Attorney1             Attorney `xml:"Attorney1"`
-	Attorney2             Attorney `xml:"Attorney2,omitempty"`
-	MoreReplacements      bool     `xml:"MoreReplacements"`
-	ChangeHowAttorneysAct bool     `xml:"ChangeHowAttorneysAct"`
+	Attorney1             Attorney `xml:"Attorney1" json:"attorney1"`
+	Attorney2             Attorney `xml:"Attorney2,omitempty" json:"attorney2"`
+	MoreReplacements      bool     `xml:"MoreReplacements" json:"moreReplacements"`
+	ChangeHowAttorneysAct bool     `xml:"ChangeHowAttorneysAct" json:"changeHowAttorneysAct"`
 }
 
 type Section5 struct {
-	LPARegistered  bool `xml:"LPARegistered"`
-	MentalCapacity bool `xml:"MentalCapacity"`
+	LPARegistered  bool `xml:"LPARegistered" json:"lpaRegistered"`
+	MentalCapacity bool `xml:"MentalCapacity" json:"mentalCapacity"`
 }
 
 type Section6 struct {
-	PeopleToNotify []PeopleToNotify `xml:"PeopleToNotify"`
-	AppointAnother bool             `xml:"AppointAnother"`
+	PeopleToNotify []PeopleToNotify `xml:"PeopleToNotify" json:"peopleToNotify"`
+	AppointAnother bool             `xml:"AppointAnother" json:"appointAnother"`
 }
 
 type Section7 struct {
-	Preferences           bool `xml:"Preferences"`
-	PreferencesMoreSpace  bool `xml:"PreferencesMoreSpace"`
-	Instructions          bool `xml:"Instructions"`
-	InstructionsMoreSpace bool `xml:"InstructionsMoreSpace"`
+	Preferences           bool `xml:"Preferences" json:"preferences"`
+	PreferencesMoreSpace  bool `xml:"PreferencesMoreSpace" json:"preferencesMoreSpace"`
+	Instructions          bool `xml:"Instructions" json:"instructions"`
+	InstructionsMoreSpace bool `xml:"InstructionsMoreSpace" json:"instructionsMoreSpace"`
 }
 
 type Section9 struct {
-	Donor   Declaration `xml:"Donor"`
-	Witness Witness     `xml:"Witness"`
+	Donor   Declaration `xml:"Donor" json:"donor"`
+	Witness Witness     `xml:"Witness" json:"witness"`
 }
 
 type Section10 struct {
-	Title     string  `xml:"Title"`
-	FirstName string  `xml:"FirstName"`
-	LastName  string  `xml:"LastName"`
-	Address   Address `xml:"Address"`
+	Title     string  `xml:"Title" json:"title"`
+	FirstName string  `xml:"FirstName" json:"firstName"`
+	LastName  string  `xml:"LastName" json:"lastName"`
+	Address   Address `xml:"Address" json:"address"`
 	Declaration
 }
 
 type Section11 struct {
-	Attorney Attorney `xml:"Attorney"`
-	Witness  Witness  `xml:"Witness"`
+	Attorney Attorney `xml:"Attorney" json:"attorney"`
+	Witness  Witness  `xml:"Witness" json:"witness"`
 }
 
 type Section12 struct {
-	DonorApply    bool       `xml:"DonorApply"`
-	AttorneyApply bool       `xml:"AttorneyApply"`
-	Attorney      []Attorney `xml:"Attorney"`
+	DonorApply    bool       `xml:"DonorApply" json:"donorApply"`
+	AttorneyApply bool       `xml:"AttorneyApply" json:"attorneyApply"`
+	Attorney      []Attorney `xml:"Attorney" json:"attorney"`
 }
 
 type Section13 struct {
-	TheDonor     bool    `xml:"TheDonor"`
-	AnAttorney   bool    `xml:"AnAttorney"`
-	Other        bool    `xml:"Other"`
-	Title        string  `xml:"Title"`
-	FirstName    string  `xml:"FirstName"`
-	LastName     string  `xml:"LastName"`
-	CompanyName  string  `xml:"CompanyName"`
-	Address      Address `xml:"Address"`
-	Post         string  `xml:"Post"`
-	Phone        string  `xml:"Phone"`
-	PhoneNumber  string  `xml:"PhoneNumber"`
-	Email        string  `xml:"Email"`
-	EmailAddress string  `xml:"EmailAddress"`
-	Welsh        bool    `xml:"Welsh"`
+	TheDonor     bool    `xml:"TheDonor" json:"theDonor"`
+	AnAttorney   bool    `xml:"AnAttorney" json:"anAttorney"`
+	Other        bool    `xml:"Other" json:"other"`
+	Title        string  `xml:"Title" json:"title"`
+	FirstName    string  `xml:"FirstName" json:"firstName"`
+	LastName     string  `xml:"LastName" json:"lastName"`
+	CompanyName  string  `xml:"CompanyName" json:"companyName"`
+	Address      Address `xml:"Address" json:"address"`
+	Post         string  `xml:"Post" json:"post"`
+	Phone        string  `xml:"Phone" json:"phone"`
+	PhoneNumber  string  `xml:"PhoneNumber" json:"phoneNumber"`
+	Email        string  `xml:"Email" json:"email"`
+	EmailAddress string  `xml:"EmailAddress" json:"emailAddress"`
+	Welsh        bool    `xml:"Welsh" json:"welsh"`
 }
 
 type Section14 struct {
-	Cheque                bool   `xml:"Cheque"`
-	Card                  bool   `xml:"Card"`
-	PhoneNumber           string `xml:"PhoneNumber"`
-	ReducedApplicationFee bool   `xml:"ReducedApplicationFee"`
-	RepeatApplication     bool   `xml:"RepeatApplication"`
-	CaseNumber            string `xml:"CaseNumber"`
-	OnlineLPA             bool   `xml:"OnlineLPA,omitempty"`
-	OnlineLPAID           string `xml:"OnlineLPAID,omitempty"`
+	Cheque                bool   `xml:"Cheque" json:"cheque"`
+	Card                  bool   `xml:"Card" json:"card"`
+	PhoneNumber           string `xml:"PhoneNumber" json:"phoneNumber"`
+	ReducedApplicationFee bool   `xml:"ReducedApplicationFee" json:"reducedApplicationFee"`
+	RepeatApplication     bool   `xml:"RepeatApplication" json:"repeatApplication"`
+	CaseNumber            string `xml:"CaseNumber" json:"caseNumber"`
+	OnlineLPA             bool   `xml:"OnlineLPA,omitempty" json:"onlineLPA"`
+	OnlineLPAID           string `xml:"OnlineLPAID,omitempty" json:"onlineLPAID"`
 }
 
 type Section15 struct {
-	Applicant []Declaration `xml:"Applicant"`
+	Applicant []Declaration `xml:"Applicant" json:"applicant"`
 }
 
 type Page1 struct {
 	types.BasePage
-	Section1 Section1 `xml:"Section1"`
+	Section1 Section1 `xml:"Section1" json:"section1"`
 }
 
 type Page2 struct {
 	types.BasePage
-	Section2 Section2 `xml:"Section2"`
+	Section2 Section2 `xml:"Section2" json:"section2"`
 }
 
 type Page3 struct {
 	types.BasePage
-	Section2 Section3 `xml:"Section3"`
+	Section2 Section2Continued `xml:"Section2" json:"section2"`
 }
 
 type Page4 struct {
 	types.BasePage
-	Section3 Section3 `xml:"Section3"`
+	Section3 Section3 `xml:"Section3" json:"section3"`
 }
 
 type Page5 struct {
 	types.BasePage
-	Section4 Section4 `xml:"Section4"`
+	Section4 Section4 `xml:"Section4" json:"section4"`
 }
 
 type Page6 struct {
 	types.BasePage
-	Section5 Section5 `xml:"Section5"`
+	Section5 Section5 `xml:"Section5" json:"section5"`
 }
 
 type Page7 struct {
 	types.BasePage
-	Section6 Section6 `xml:"Section6"`
+	Section6 Section6 `xml:"Section6" json:"section6"`
 }
 
 type Page8 struct {
 	types.BasePage
-	Section7 Section7 `xml:"Section7"`
+	Section7 Section7 `xml:"Section7" json:"section7"`
 }
 
 type Page9 struct {
 	types.BasePage
-	Section8 string `xml:"Section8"`
+	Section8 string `xml:"Section8" json:"section8"`
 }
 
 type Page10 struct {
 	types.BasePage
-	Section9 Section9 `xml:"Section9"`
+	Section9 Section9 `xml:"Section9" json:"section9"`
 }
 
 type Page11 struct {
 	types.BasePage
-	Section10 Section10 `xml:"Section10"`
+	Section10 Section10 `xml:"Section10" json:"section10"`
 }
 
 type Page12 struct {
 	types.BasePage
-	Section11 Section11 `xml:"Section11"`
+	Section11 Section11 `xml:"Section11" json:"section11"`
 }
 
 type Page16 struct {
 	types.BasePage
-	Section16 string `xml:"Section16"`
+	Section16 string `xml:"Section16" json:"section16"`
 }
 
 type Page17 struct {
 	types.BasePage
-	Section12 Section12 `xml:"Section12"`
+	Section12 Section12 `xml:"Section12" json:"section12"`
 }
 
 type Page18 struct {
 	types.BasePage
-	Section13 Section13 `xml:"Section13"`
+	Section13 Section13 `xml:"Section13" json:"section13"`
 }
 
 type Page19 struct {
 	types.BasePage
-	Section14 Section14 `xml:"Section14"`
+	Section14 Section14 `xml:"Section14" json:"section14"`
 }
 
 type Page20 struct {
 	types.BasePage
-	Section15 Section15 `xml:"Section15"`
+	Section15 Section15 `xml:"Section15" json:"section15"`
 }
 
 type ContinuationPage1 struct {
 	types.BasePage
-	ContinuationSheet1 ContinuationSheet1 `xml:"ContinuationSheet1"`
+	ContinuationSheet1 ContinuationSheet1 `xml:"ContinuationSheet1" json:"continuationSheet1"`
 }
 
 type ContinuationPage2 struct {
 	types.BasePage
-	ContinuationSheet2 ContinuationSheet2 `xml:"ContinuationSheet2"`
+	ContinuationSheet2 ContinuationSheet2 `xml:"ContinuationSheet2" json:"continuationSheet2"`
 }
 
 type ContinuationPage3 struct {
 	types.BasePage
-	ContinuationSheet3 ContinuationSheet3 `xml:"ContinuationSheet3"`
+	ContinuationSheet3 ContinuationSheet3 `xml:"ContinuationSheet3" json:"continuationSheet3"`
 }
 
 type ContinuationPage4 struct {
 	types.BasePage
-	ContinuationSheet4 ContinuationSheet4 `xml:"ContinuationSheet4"`
+	ContinuationSheet4 ContinuationSheet4 `xml:"ContinuationSheet4" json:"continuationSheet4"`
 }
 
 type ContinuationSheet1 struct {
-	Attorney []Attorney  `xml:"Attorney"`
-	Donor    Declaration `xml:"Donor"`
+	Attorney []Attorney  `xml:"Attorney" json:"attorney"`
+	Donor    Declaration `xml:"Donor" json:"donor"`
 }
 
 type ContinuationSheet2 struct {
-	AdditionalInformation AdditionalInformation `xml:"AdditionalInformation"`
-	Donor                 Declaration           `xml:"Donor"`
+	AdditionalInformation AdditionalInformation `xml:"AdditionalInformation" json:"additionalInformation"`
+	Donor                 Declaration           `xml:"Donor" json:"donor"`
 }
 
 type ContinuationSheet3 struct {
-	Donor     PersonName       `xml:"Donor"`
-	Signatory AuthorisedPerson `xml:"Signatory"`
-	Witnesses []Witness        `xml:"Witnesses"`
+	Donor     PersonName       `xml:"Donor" json:"donor"`
+	Signatory AuthorisedPerson `xml:"Signatory" json:"signatory"`
+	Witnesses []Witness        `xml:"Witnesses" json:"witnesses"`
 }
 
 type ContinuationSheet4 struct {
-	CompanyRegistration string             `xml:"CompanyRegistration"`
-	AuthorisedPerson    []AuthorisedPerson `xml:"AuthorisedPerson"`
+	CompanyRegistration string             `xml:"CompanyRegistration" json:"companyRegistration"`
+	AuthorisedPerson    []AuthorisedPerson `xml:"AuthorisedPerson" json:"authorisedPerson"`
 }
 
 type AuthorisedPerson struct {
-	FullName string `xml:"FullName"`
+	FullName string `xml:"FullName" json:"fullName"`
 	Declaration
 }
 
@@ -319,9 +324,9 @@ type InfoPage struct {
 }
 
 type AdditionalInformation struct {
-	Notes                bool `xml:"Notes"`
-	Instructions         bool `xml:"Instructions"`
-	Preferences          bool `xml:"Preferences"`
-	ReplacementAttorneys bool `xml:"ReplacementAttorneys"`
-	Jointly              bool `xml:"Jointly"`
+	Notes                bool `xml:"Notes" json:"notes"`
+	Instructions         bool `xml:"Instructions" json:"instructions"`
+	Preferences          bool `xml:"Preferences" json:"preferences"`
+	ReplacementAttorneys bool `xml:"ReplacementAttorneys" json:"replacementAttorneys"`
+	Jointly              bool `xml:"Jointly" json:"jointly"`
 }
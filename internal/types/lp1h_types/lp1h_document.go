@@ -8,31 +8,31 @@ import (
 
 type LP1HDocument struct {
 	lp1f_types.LP1FDocument
-	XMLName xml.Name `xml:"LP1H" required:"true"`
-	Page6   Page6    `xml:"Page6"`
-	Page10  Page10   `xml:"Page10"`
+	XMLName xml.Name `xml:"LP1H" required:"true" json:"-"`
+	Page6   Page6    `xml:"Page6" json:"page6"`
+	Page10  Page10   `xml:"Page10" json:"page10"`
 }
 
 type Page10 struct {
-	Section9 Section9 `xml:"Section9"`
+	Section9 Section9 `xml:"Section9" json:"section9"`
 }
 
 type Page6 struct {
-	Section5 Section5 `xml:"Section5"`
+	Section5 Section5 `xml:"Section5" json:"section5"`
 }
 
 type Section9 struct {
-	Donor   SignatureAndDOB    `xml:"Donor"`
-	Witness lp1f_types.Witness `xml:"Witness"`
+	Donor   SignatureAndDOB    `xml:"Donor" json:"donor"`
+	Witness lp1f_types.Witness `xml:"Witness" json:"witness"`
 }
 
 type Section5 struct {
-	OptionA SignatureAndDOB    `xml:"OptionA"`
-	OptionB SignatureAndDOB    `xml:"OptionB"`
-	Witness lp1f_types.Witness `xml:"Witness"`
+	OptionA SignatureAndDOB    `xml:"OptionA" json:"optionA"`
+	OptionB SignatureAndDOB    `xml:"OptionB" json:"optionB"`
+	Witness lp1f_types.Witness `xml:"Witness" json:"witness"`
 }
 
 type SignatureAndDOB struct {
-	Signature string `xml:"Signature"`
-	DOB       string `xml:"DOB"`
+	Signature string `xml:"Signature" json:"signature"`
+	DOB       string `xml:"DOB" json:"dob"`
 }
@@ -8,105 +8,105 @@ import (
 )
 
 type LP2Document struct {
-	XMLName  xml.Name   `xml:"LP2"`
-	Page1    Page1      `xml:"Page1"`
-	Page2    Page2      `xml:"Page2"`
-	Page3    Page3      `xml:"Page3"`
-	Page4    Page4      `xml:"Page4"`
-	Page5    Page5      `xml:"Page5"`
-	Page6    []Page6    `xml:"Page6"`            
-	InfoPage []InfoPage `xml:"InfoPage,omitempty"`
+	XMLName  xml.Name   `xml:"LP2" json:"-"`
+	Page1    Page1      `xml:"Page1" json:"page1"`
+	Page2    Page2      `xml:"Page2" json:"page2"`
+	Page3    Page3      `xml:"Page3" json:"page3"`
+	Page4    Page4      `xml:"Page4" json:"page4"`
+	Page5    Page5      `xml:"Page5" json:"page5"`
+	Page6    []Page6    `xml:"Page6" json:"page6"`
+	InfoPage []InfoPage `xml:"InfoPage,omitempty" json:"infoPage"`
 }
 
 type Page1 struct {
-	Section1  Section1    `xml:"Section1"`
-	types.BasePage  
+	Section1 Section1 `xml:"Section1" json:"section1"`
+	types.BasePage
 }
 
 type Section1 struct {
-	Title                    string `xml:"Title"`
-	FirstName                string `xml:"FirstName"`
-	LastName                 string `xml:"LastName"`
-	PropertyFinancialAffairs bool   `xml:"PropertyFinancialAffairs"`
-	HealthWelfare            bool   `xml:"HealthWelfare"`
+	Title                    string `xml:"Title" json:"title"`
+	FirstName                string `xml:"FirstName" json:"firstName"`
+	LastName                 string `xml:"LastName" json:"lastName"`
+	PropertyFinancialAffairs bool   `xml:"PropertyFinancialAffairs" json:"propertyFinancialAffairs"`
+	HealthWelfare            bool   `xml:"HealthWelfare" json:"healthWelfare"`
 }
 
 type Page2 struct {
-	Section2  Section2    `xml:"Section2"`
+	Section2 Section2 `xml:"Section2" json:"section2"`
 	types.BasePage
 }
 
 type Section2 struct {
-	DonorRegisteration    bool       `xml:"DonorRegisteration"`
-	AttorneyRegisteration bool       `xml:"AttorneyRegisteration"`
-	Attorney              []Attorney `xml:"Attorney"`
+	DonorRegisteration    bool       `xml:"DonorRegisteration" json:"donorRegisteration"`
+	AttorneyRegisteration bool       `xml:"AttorneyRegisteration" json:"attorneyRegisteration"`
+	Attorney              []Attorney `xml:"Attorney" json:"attorney"`
 }
 
 type Attorney struct {
-	Title     string `xml:"Title"`
-	FirstName string `xml:"FirstName"`
-	LastName  string `xml:"LastName"`
-	DOB       string `xml:"DOB"`
+	Title     string `xml:"Title" json:"title"`
+	FirstName string `xml:"FirstName" json:"firstName"`
+	LastName  string `xml:"LastName" json:"lastName"`
+	DOB       string `xml:"DOB" json:"dob"`
 }
 
 type Page3 struct {
-	Section3  Section3    `xml:"Section3"`
+	Section3 Section3 `xml:"Section3" json:"section3"`
 	types.BasePage
 }
 
 type Section3 struct {
-	TheDonor     bool               `xml:"TheDonor"`
-	AnAttorney   bool               `xml:"AnAttorney"`
-	Other        bool               `xml:"Other"`
-	Title        string             `xml:"Title"`
-	FirstName    string             `xml:"FirstName"`
-	LastName     string             `xml:"LastName"`
-	Company      string             `xml:"Company"`
-	Address      lp1f_types.Address `xml:"Address"`
-	Post         bool               `xml:"Post"`
-	Phone        bool               `xml:"Phone"`
-	PhoneNumber  string             `xml:"PhoneNumber"`
-	Email        bool               `xml:"Email"`
-	EmailAddress string             `xml:"EmailAddress"`
-	Welsh        bool               `xml:"Welsh"`
+	TheDonor     bool               `xml:"TheDonor" json:"theDonor"`
+	AnAttorney   bool               `xml:"AnAttorney" json:"anAttorney"`
+	Other        bool               `xml:"Other" json:"other"`
+	Title        string             `xml:"Title" json:"title"`
+	FirstName    string             `xml:"FirstName" json:"firstName"`
+	LastName     string             `xml:"LastName" json:"lastName"`
+	Company      string             `xml:"Company" json:"company"`
+	Address      lp1f_types.Address `xml:"Address" json:"address"`
+	Post         bool               `xml:"Post" json:"post"`
+	Phone        bool               `xml:"Phone" json:"phone"`
+	PhoneNumber  string             `xml:"PhoneNumber" json:"phoneNumber"`
+	Email        bool               `xml:"Email" json:"email"`
+	EmailAddress string             `xml:"EmailAddress" json:"emailAddress"`
+	Welsh        bool               `xml:"Welsh" json:"welsh"`
 }
 
 type Page4 struct {
-	Section4  Section4    `xml:"Section4"`
+	Section4 Section4 `xml:"Section4" json:"section4"`
 	types.BasePage
 }
 
 type Section4 struct {
-	Cheque      bool   `xml:"Cheque"`
-	Card        bool   `xml:"Card"`
-	PhoneNumber string `xml:"PhoneNumber"`
-	ReducedFee  bool   `xml:"ReducedFee"`
+	Cheque      bool   `xml:"Cheque" json:"cheque"`
+	Card        bool   `xml:"Card" json:"card"`
+	PhoneNumber string `xml:"PhoneNumber" json:"phoneNumber"`
+	ReducedFee  bool   `xml:"ReducedFee" json:"reducedFee"`
 }
 
 type Page5 struct {
-	Section5  Section5    `xml:"Section5"`
+	Section5 Section5 `xml:"Section5" json:"section5"`
 	types.BasePage
 }
 
 type Section5 struct {
-	Attorney []lp1f_types.Declaration `xml:"Attorney"`
+	Attorney []lp1f_types.Declaration `xml:"Attorney" json:"attorney"`
 }
 
 type Page6 struct {
-	Section6  Section6    `xml:"Section6"`
+	Section6 Section6 `xml:"Section6" json:"section6"`
 	types.BasePage
 }
 
 type Section6 struct {
-	Addresses []AddressEntry `xml:"Addresses"`
+	Addresses []AddressEntry `xml:"Addresses" json:"addresses"`
 }
 
 type AddressEntry struct {
-	Title        string             `xml:"Title"`
-	FirstName    string             `xml:"FirstName"`
-	LastName     string             `xml:"LastName"`
-	Address      lp1f_types.Address `xml:"Address"`
-	EmailAddress string             `xml:"EmailAddress"`
+	Title        string             `xml:"Title" json:"title"`
+	FirstName    string             `xml:"FirstName" json:"firstName"`
+	LastName     string             `xml:"LastName" json:"lastName"`
+	Address      lp1f_types.Address `xml:"Address" json:"address"`
+	EmailAddress string             `xml:"EmailAddress" json:"emailAddress"`
 }
 
 type InfoPage struct {
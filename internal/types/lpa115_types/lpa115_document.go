@@ -8,38 +8,38 @@ import (
 )
 
 type LPA115Document struct {
-	XMLName xml.Name `xml:"LPA115"`
-	Page3   []Page3  `xml:"Page3"`
-	Page6   []Page6  `xml:"Page6"`
+	XMLName xml.Name `xml:"LPA115" json:"-"`
+	Page3   []Page3  `xml:"Page3" json:"page3"`
+	Page6   []Page6  `xml:"Page6" json:"page6"`
 }
 
 type Page3 struct {
-	PartA             PartA3 `xml:"PartA"`
-	Signature         bool   `xml:"Signature"`
-	Date              string `xml:"Date"`
-	ContinuationSheet int    `xml:"ContinuationSheetNo"`
-	TotalSheets       string `xml:"TotalSheets"`
+	PartA             PartA3 `xml:"PartA" json:"partA"`
+	Signature         bool   `xml:"Signature" json:"signature"`
+	Date              string `xml:"Date" json:"date"`
+	ContinuationSheet int    `xml:"ContinuationSheetNo" json:"continuationSheetNo"`
+	TotalSheets       string `xml:"TotalSheets" json:"totalSheets"`
 	types.BasePage
 }
 
 type PartA3 struct {
-	FullName string                 `xml:"FullName"`
-	OptionA  lp1f_types.Declaration `xml:"OptionA"`
-	OptionB  lp1f_types.Declaration `xml:"OptionB"`
+	FullName string                 `xml:"FullName" json:"fullName"`
+	OptionA  lp1f_types.Declaration `xml:"OptionA" json:"optionA"`
+	OptionB  lp1f_types.Declaration `xml:"OptionB" json:"optionB"`
 }
 
 type Page6 struct {
-	PartB PartBPage6 `xml:"PartB"`
+	PartB PartBPage6 `xml:"PartB" json:"partB"`
 	types.BasePage
 }
 
 type PartBPage6 struct {
-	Salutation        lp1f_types.Salutation `xml:"Salutation"`
-	FirstName         string                `xml:"FirstName"`
-	LastName          string                `xml:"LastName"`
-	Address           lp1f_types.Address    `xml:"Address"`
-	Signature         bool                  `xml:"Signature"`
-	Date              string                `xml:"Date"`
-	ContinuationSheet int                   `xml:"ContinuationSheetNo"`
-	TotalSheets       int                   `xml:"TotalSheets"`
+	Salutation        lp1f_types.Salutation `xml:"Salutation" json:"salutation"`
+	FirstName         string                `xml:"FirstName" json:"firstName"`
+	LastName          string                `xml:"LastName" json:"lastName"`
+	Address           lp1f_types.Address    `xml:"Address" json:"address"`
+	Signature         bool                  `xml:"Signature" json:"signature"`
+	Date              string                `xml:"Date" json:"date"`
+	ContinuationSheet int                   `xml:"ContinuationSheetNo" json:"continuationSheetNo"`
+	TotalSheets       int                   `xml:"TotalSheets" json:"totalSheets"`
 }
@@ -8,27 +8,23 @@ import (
 )
 
 type LPA116Document struct {
-	XMLName  xml.Name `xml:"LPA116"`
-	Page1    Page     `xml:"Page1"`
-	PartC    PartC    `xml:"PartC"`
-	Attorney Attorney `xml:"Attorney"`
+	XMLName  xml.Name         `xml:"LPA116" json:"-"`
+	Page1    []Page1          `xml:"Page1" json:"page1"`
+	InfoPage []types.BasePage `xml:"InfoPage,omitempty" json:"infoPage"`
 }
 
-type Page struct {
+type Page1 struct {
+	PartC PartC `xml:"PartC" json:"partC"`
 	types.BasePage
 }
 
 type PartC struct {
-	FullName      string             `xml:"FullName"`
-	Address       lp1f_types.Address `xml:"Address"`
-	CaseReference string             `xml:"CaseReference"`
-	Declaration   string             `xml:"Declaration"`
+	Attorney Attorney           `xml:"Attorney" json:"attorney"`
+	Witness  lp1f_types.Witness `xml:"Witness" json:"witness"`
 }
 
 type Attorney struct {
-	FirstName string `xml:"FirstName"`
-	LastName  string `xml:"LastName"`
-	Address   lp1f_types.Address
-	Telephone string `xml:"Telephone"`
-	Email     string `xml:"Email"`
+	Signature bool   `xml:"Signature" json:"signature"`
+	FullName  string `xml:"FullName" json:"fullName"`
+	Date      string `xml:"Date" json:"date"`
 }
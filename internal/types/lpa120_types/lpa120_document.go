@@ -8,12 +8,12 @@ import (
 )
 
 type LPA120Document struct {
-	XMLName   xml.Name `xml:"LPA120"`
-	Page1     Page     `xml:"Page1"`
-	Page2     Page     `xml:"Page2"`
-	Page3     Page3    `xml:"Page3"`
-	Page4     Page4    `xml:"Page4"`
-	InfoPages []Page   `xml:"InfoPage,omitempty"`
+	XMLName   xml.Name `xml:"LPA120" json:"-"`
+	Page1     Page     `xml:"Page1" json:"page1"`
+	Page2     Page     `xml:"Page2" json:"page2"`
+	Page3     Page3    `xml:"Page3" json:"page3"`
+	Page4     Page4    `xml:"Page4" json:"page4"`
+	InfoPages []Page   `xml:"InfoPage,omitempty" json:"infoPage"`
 }
 
 type Page struct {
@@ -21,59 +21,59 @@ type Page struct {
 }
 
 type Page3 struct {
-	Section1 Section1 `xml:"Section1"`
-	Section2 Section2 `xml:"Section2"`
+	Section1 Section1 `xml:"Section1" json:"section1"`
+	Section2 Section2 `xml:"Section2" json:"section2"`
 	types.BasePage
 }
 
 type Section1 struct {
-	FullName                string             `xml:"FullName"`
-	Address                 lp1f_types.Address `xml:"Address"`
-	CaseReference           string             `xml:"CaseReference"`
-	LPAApplicationFee       bool               `xml:"LPAApplicationFee"`
-	EPAApplicationFee       bool               `xml:"EPAApplicationFee"`
-	RepeatLPAApplicationFee bool               `xml:"RepeatLPAApplicationFee"`
-	LPAHealthWelfare        bool               `xml:"LPAHealthWelfare"`
-	LPAPropertyFinance      bool               `xml:"LPAPropertyFinance"`
-	EPA                     bool               `xml:"EPA"`
+	FullName                string             `xml:"FullName" json:"fullName"`
+	Address                 lp1f_types.Address `xml:"Address" json:"address"`
+	CaseReference           string             `xml:"CaseReference" json:"caseReference"`
+	LPAApplicationFee       bool               `xml:"LPAApplicationFee" json:"lpaApplicationFee"`
+	EPAApplicationFee       bool               `xml:"EPAApplicationFee" json:"epaApplicationFee"`
+	RepeatLPAApplicationFee bool               `xml:"RepeatLPAApplicationFee" json:"repeatLPAApplicationFee"`
+	LPAHealthWelfare        bool               `xml:"LPAHealthWelfare" json:"lpaHealthWelfare"`
+	LPAPropertyFinance      bool               `xml:"LPAPropertyFinance" json:"lpaPropertyFinance"`
+	EPA                     bool               `xml:"EPA" json:"epa"`
 }
 
 type Section2 struct {
-	Relationship Relationship          `xml:"Relationship"`
-	Salutation   lp1f_types.Salutation `xml:"Salutation"`
-	FirstName    string                `xml:"FirstName"`
-	LastName     string                `xml:"LastName"`
-	Address      lp1f_types.Address
-	Telephone    string `xml:"Telephone"`
-	Mobile       string `xml:"Mobile"`
-	Email        string `xml:"Email"`
-	FeePaidTo    string `xml:"FeePaidTo"`
+	Relationship Relationship          `xml:"Relationship" json:"relationship"`
+	Salutation   lp1f_types.Salutation `xml:"Salutation" json:"salutation"`
+	FirstName    string                `xml:"FirstName" json:"firstName"`
+	LastName     string                `xml:"LastName" json:"lastName"`
+	Address      lp1f_types.Address    `json:"address"`
+	Telephone    string                `xml:"Telephone" json:"telephone"`
+	Mobile       string                `xml:"Mobile" json:"mobile"`
+	Email        string                `xml:"Email" json:"email"`
+	FeePaidTo    string                `xml:"FeePaidTo" json:"feePaidTo"`
 }
 
 type Page4 struct {
-	Section3 Section3 `xml:"Section3"`
-	Section4 Section4 `xml:"Section4"`
-	Section5 Section5 `xml:"Section5"`
+	Section3 Section3 `xml:"Section3" json:"section3"`
+	Section4 Section4 `xml:"Section4" json:"section4"`
+	Section5 Section5 `xml:"Section5" json:"section5"`
 	types.BasePage
 }
 
 type Section3 struct {
-	DonorReceivesBenefits lp1f_types.YesOrNo `xml:"DonorReceivesBenefits"`
-	DonorAwardedInjuries  lp1f_types.YesOrNo `xml:"DonorAwardedInjuries"`
+	DonorReceivesBenefits lp1f_types.YesOrNo `xml:"DonorReceivesBenefits" json:"donorReceivesBenefits"`
+	DonorAwardedInjuries  lp1f_types.YesOrNo `xml:"DonorAwardedInjuries" json:"donorAwardedInjuries"`
 }
 
 type Section4 struct {
-	DonorOver12000               lp1f_types.YesOrNo `xml:"DonorOver12000"`
-	DonorReceivesUniversalCredit lp1f_types.YesOrNo `xml:"DonorReceivesUniversalCredit"`
+	DonorOver12000               lp1f_types.YesOrNo `xml:"DonorOver12000" json:"donorOver12000"`
+	DonorReceivesUniversalCredit lp1f_types.YesOrNo `xml:"DonorReceivesUniversalCredit" json:"donorReceivesUniversalCredit"`
 }
 
 type Section5 struct {
-	Declaration lp1f_types.Declaration `xml:"Declaration"`
+	Declaration lp1f_types.Declaration `xml:"Declaration" json:"declaration"`
 }
 
 type Relationship struct {
-	Donor       bool   `xml:"Donor"`
-	Attorney    bool   `xml:"Attorney"`
-	Other       bool   `xml:"Other"`
-	OtherDetail string `xml:"OtherDetail,omitempty"`
+	Donor       bool   `xml:"Donor" json:"donor"`
+	Attorney    bool   `xml:"Attorney" json:"attorney"`
+	Other       bool   `xml:"Other" json:"other"`
+	OtherDetail string `xml:"OtherDetail,omitempty" json:"otherDetail"`
 }
@@ -8,86 +8,86 @@ import (
 )
 
 type LPCDocument struct {
-	XMLName xml.Name `xml:"LPC"`
-	Page1   []Page1  `xml:"Page1,omitempty"`
-	Page2   []Page2  `xml:"Page2,omitempty"`
-	Page3   []Page3  `xml:"Page3,omitempty"`
-	Page4   []Page4  `xml:"Page4,omitempty"`
+	XMLName xml.Name `xml:"LPC" json:"-"`
+	Page1   []Page1  `xml:"Page1,omitempty" json:"page1"`
+	Page2   []Page2  `xml:"Page2,omitempty" json:"page2"`
+	Page3   []Page3  `xml:"Page3,omitempty" json:"page3"`
+	Page4   []Page4  `xml:"Page4,omitempty" json:"page4"`
 }
 
 type Page1 struct {
-	XMLName            xml.Name           `xml:"Page1"`
-	ContinuationSheet1 ContinuationSheet1 `xml:"ContinuationSheet1"`
+	XMLName            xml.Name           `xml:"Page1" json:"-"`
+	ContinuationSheet1 ContinuationSheet1 `xml:"ContinuationSheet1" json:"continuationSheet1"`
 	types.BasePage
 }
 
 type ContinuationSheet1 struct {
-	Attorneys []AttorneyContinuation1     `xml:"Attorney"`
-	Donor     lp1f_types.AuthorisedPerson `xml:"Donor"`
+	Attorneys []AttorneyContinuation1     `xml:"Attorney" json:"attorney"`
+	Donor     lp1f_types.AuthorisedPerson `xml:"Donor" json:"donor"`
 }
 
 type AttorneyContinuation1 struct {
-	Attorney            bool   `xml:"Attorney"`
-	ReplacementAttorney bool   `xml:"ReplacementAttorney"`
-	PersonToNotify      bool   `xml:"PersonToNotify"`
-	Title               string `xml:"Title"`
-	FirstName           string `xml:"FirstName"`
-	LastName            string `xml:"LastName"`
-	DOB                 string `xml:"DOB"`
-	Address             lp1f_types.Address
-	Email               string        `xml:"Email"`
-	Relationship        *Relationship `xml:"Relationship,omitempty"`
+	Attorney            bool               `xml:"Attorney" json:"attorney"`
+	ReplacementAttorney bool               `xml:"ReplacementAttorney" json:"replacementAttorney"`
+	PersonToNotify      bool               `xml:"PersonToNotify" json:"personToNotify"`
+	Title               string             `xml:"Title" json:"title"`
+	FirstName           string             `xml:"FirstName" json:"firstName"`
+	LastName            string             `xml:"LastName" json:"lastName"`
+	DOB                 string             `xml:"DOB" json:"dob"`
+	Address             lp1f_types.Address `json:"address"`
+	Email               string             `xml:"Email" json:"email"`
+	Relationship        *Relationship      `xml:"Relationship,omitempty" json:"relationship"`
 }
 
 type Page2 struct {
-	XMLName            xml.Name           `xml:"Page2"`
-	ContinuationSheet2 ContinuationSheet2 `xml:"ContinuationSheet2"`
+	XMLName            xml.Name           `xml:"Page2" json:"-"`
+	ContinuationSheet2 ContinuationSheet2 `xml:"ContinuationSheet2" json:"continuationSheet2"`
 	types.BasePage
 }
 
 type ContinuationSheet2 struct {
-	AdditionalInformation lp1f_types.AdditionalInformation `xml:"AdditionalInformation"`
-	Donor                 lp1f_types.AuthorisedPerson      `xml:"Donor"`
+	AdditionalInformation lp1f_types.AdditionalInformation `xml:"AdditionalInformation" json:"additionalInformation"`
+	Donor                 lp1f_types.AuthorisedPerson      `xml:"Donor" json:"donor"`
 }
 
 type Page3 struct {
-	XMLName            xml.Name           `xml:"Page3"`
-	ContinuationSheet3 ContinuationSheet3 `xml:"ContinuationSheet3"`
+	XMLName            xml.Name           `xml:"Page3" json:"-"`
+	ContinuationSheet3 ContinuationSheet3 `xml:"ContinuationSheet3" json:"continuationSheet3"`
 	types.BasePage
 }
 
 type ContinuationSheet3 struct {
-	Donor     DonorNameOnly               `xml:"Donor"`
-	Signatory lp1f_types.AuthorisedPerson `xml:"Signatory"`
-	Witnesses []WitnessContinuation3      `xml:"Witnesses"`
+	Donor     DonorNameOnly               `xml:"Donor" json:"donor"`
+	Signatory lp1f_types.AuthorisedPerson `xml:"Signatory" json:"signatory"`
+	Witnesses []WitnessContinuation3      `xml:"Witnesses" json:"witnesses"`
 }
 
 type DonorNameOnly struct {
-	FullName string `xml:"FullName"`
+	FullName string `xml:"FullName" json:"fullName"`
 }
 
 type WitnessContinuation3 struct {
-	Signature bool               `xml:"Signature"`
-	FullName  string             `xml:"FullName"`
-	Address   lp1f_types.Address `xml:"Address"`
+	Signature bool               `xml:"Signature" json:"signature"`
+	FullName  string             `xml:"FullName" json:"fullName"`
+	Address   lp1f_types.Address `xml:"Address" json:"address"`
 }
 
 type Page4 struct {
-	XMLName            xml.Name           `xml:"Page4"`
-	ContinuationSheet4 ContinuationSheet4 `xml:"ContinuationSheet4"`
+	XMLName            xml.Name           `xml:"Page4" json:"-"`
+	ContinuationSheet4 ContinuationSheet4 `xml:"ContinuationSheet4" json:"continuationSheet4"`
 	types.BasePage
 }
 
 type ContinuationSheet4 struct {
-	CompanyRegistration string                        `xml:"CompanyRegistration"`
-	AuthorisedPerson    []lp1f_types.AuthorisedPerson `xml:"AuthorisedPerson"`
+	CompanyRegistration string                        `xml:"CompanyRegistration" json:"companyRegistration"`
+	AuthorisedPerson    []lp1f_types.AuthorisedPerson `xml:"AuthorisedPerson" json:"authorisedPerson"`
 }
 
 type Relationship struct {
-	CivilPartnerSpouse bool   `xml:"CivilPartnerSpouse"`
-	Child              bool   `xml:"Child"`
-	Solicitor          bool   `xml:"Solicitor"`
-	Other              bool   `xml:"Other"`
-	OtherProfessional  bool   `xml:"OtherProfessional"`
-	OtherName          string `xml:"OtherName,omitempty"`
+	CivilPartnerSpouse bool   `xml:"CivilPartnerSpouse" json:"civilPartnerSpouse"`
+	Child              bool   `xml:"Child" json:"child"`
+	Solicitor          bool   `xml:"Solicitor" json:"solicitor"`
+	Other              bool   `xml:"Other" json:"other"`
+	OtherProfessional  bool   `xml:"OtherProfessional" json:"otherProfessional"`
+	OtherName          string `xml:"OtherName,omitempty" json:"otherName"`
 }
@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ministryofjustice/opg-scanning/internal/constants"
+)
+
+// Severity decides what happens to a set when an issue is found.
+type Severity string
+
+const (
+	// SeverityError rejects the set.
+	SeverityError Severity = "error"
+	// SeverityWarning lets the set through but reports the issue.
+	SeverityWarning Severity = "warning"
+	// SeverityIgnore drops the issue entirely.
+	SeverityIgnore Severity = "ignore"
+)
+
+// Issue is a single problem found while validating a set. Field is the path to
+// the element concerned within the document, such as
+// "Page12[1].Section11.Attorney.Date", when there is one.
+type Issue struct {
+	DocumentID   string   `json:"documentId,omitempty"`
+	DocumentType string   `json:"documentType,omitempty"`
+	Field        string   `json:"field,omitempty"`
+	Code         string   `json:"code"`
+	Severity     Severity `json:"severity"`
+	Message      string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return i.Message
+}
+
+// Policy overrides the default severity of issues by code.
+type Policy map[string]Severity
+
+// ParsePolicy reads a policy written as comma separated code=severity pairs,
+// for example "case-number-mismatch=warning,donor-name-mismatch=error".
+func ParsePolicy(s string) (Policy, error) {
+	return parseSeverities(s, "validation policy")
+}
+
+// parseSeverities reads comma separated key=severity pairs. The name of the
+// policy is used in errors.
+func parseSeverities(s, name string) (map[string]Severity, error) {
+	severities := map[string]Severity{}
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, severity, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry %q", name, pair)
+		}
+
+		switch s := Severity(strings.TrimSpace(severity)); s {
+		case SeverityError, SeverityWarning, SeverityIgnore:
+			severities[strings.TrimSpace(key)] = s
+		default:
+			return nil, fmt.Errorf("invalid severity %q for %s", severity, key)
+		}
+	}
+
+	return severities, nil
+}
+
+// Apply sets the severity of each issue from the policy, dropping those that
+// are ignored. Issues without an entry keep the severity they were raised with.
+func (p Policy) Apply(issues []Issue) []Issue {
+	var applied []Issue
+
+	for _, issue := range issues {
+		if severity, ok := p[issue.Code]; ok {
+			issue.Severity = severity
+		}
+
+		if issue.Severity != SeverityIgnore {
+			applied = append(applied, issue)
+		}
+	}
+
+	return applied
+}
+
+// DuplicatePolicy sets the severity of content that was submitted before, by
+// document type.
+type DuplicatePolicy map[string]Severity
+
+// ParseDuplicatePolicy reads a duplicate policy written as comma separated
+// type=severity pairs, for example "LP1F=error,Correspondence=ignore". Each
+// type must be a supported document type.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	severities, err := parseSeverities(s, "duplicate policy")
+	if err != nil {
+		return nil, err
+	}
+
+	for documentType := range severities {
+		if !slices.Contains(constants.SupportedDocumentTypes, documentType) {
+			return nil, fmt.Errorf("unsupported document type %q in duplicate policy", documentType)
+		}
+	}
+
+	return severities, nil
+}
+
+// Severity returns the severity of a repeated document of the given type.
+// Types without an entry are only warned about.
+func (p DuplicatePolicy) Severity(documentType string) Severity {
+	if severity, ok := p[documentType]; ok {
+		return severity
+	}
+
+	return SeverityWarning
+}
+
+// Errors returns the issues that should reject a set.
+func Errors(issues []Issue) []Issue {
+	var errors []Issue
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errors = append(errors, issue)
+		}
+	}
+
+	return errors
+}
+
+// Warnings returns the issues that should be reported without rejecting a set.
+func Warnings(issues []Issue) []Issue {
+	var warnings []Issue
+	for _, issue := range issues {
+		if issue.Severity == SeverityWarning {
+			warnings = append(warnings, issue)
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy(" case-number-mismatch=warning, donor-name-mismatch = ignore ,")
+	assert.NoError(t, err)
+	assert.Equal(t, Policy{
+		"case-number-mismatch": SeverityWarning,
+		"donor-name-mismatch":  SeverityIgnore,
+	}, policy)
+
+	policy, err = ParsePolicy("")
+	assert.NoError(t, err)
+	assert.Empty(t, policy)
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	_, err := ParsePolicy("case-number-mismatch")
+	assert.ErrorContains(t, err, "invalid validation policy entry")
+
+	_, err = ParsePolicy("case-number-mismatch=fatal")
+	assert.ErrorContains(t, err, "invalid severity")
+}
+
+func TestPolicyApply(t *testing.T) {
+	issues := []Issue{
+		{Code: "a", Severity: SeverityError},
+		{Code: "b", Severity: SeverityError},
+		{Code: "c", Severity: SeverityWarning},
+	}
+
+	applied := Policy{"a": SeverityWarning, "c": SeverityIgnore}.Apply(issues)
+
+	assert.Equal(t, []Issue{
+		{Code: "a", Severity: SeverityWarning},
+		{Code: "b", Severity: SeverityError},
+	}, applied)
+	assert.Equal(t, []Issue{{Code: "b", Severity: SeverityError}}, Errors(applied))
+	assert.Equal(t, []Issue{{Code: "a", Severity: SeverityWarning}}, Warnings(applied))
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	policy, err := ParseDuplicatePolicy("LP1F=error, Correspondence = ignore")
+	assert.NoError(t, err)
+	assert.Equal(t, DuplicatePolicy{
+		"LP1F":           SeverityError,
+		"Correspondence": SeverityIgnore,
+	}, policy)
+
+	assert.Equal(t, SeverityError, policy.Severity("LP1F"))
+	assert.Equal(t, SeverityWarning, policy.Severity("LP2"))
+}
+
+func TestParseDuplicatePolicyInvalid(t *testing.T) {
+	_, err := ParseDuplicatePolicy("LP1F")
+	assert.ErrorContains(t, err, "invalid duplicate policy entry")
+
+	_, err = ParseDuplicatePolicy("LP1F=fatal")
+	assert.ErrorContains(t, err, "invalid severity")
+
+	_, err = ParseDuplicatePolicy("duplicate-set=error")
+	assert.ErrorContains(t, err, `unsupported document type "duplicate-set"`)
+}
@@ -6,11 +6,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/ministryofjustice/opg-scanning/internal/api"
+	"github.com/ministryofjustice/opg-scanning/internal/audit"
 	appaws "github.com/ministryofjustice/opg-scanning/internal/aws"
 	"github.com/ministryofjustice/opg-scanning/internal/config"
 	"github.com/ministryofjustice/opg-scanning/internal/logger"
@@ -63,7 +65,9 @@ func main() {
 
 	dynamoClient := dynamodb.NewFromConfig(cfg)
 
-	controller := api.NewIndexController(logWrapper, awsClient, appConfig, dynamoClient)
+	auditLog := audit.NewLog(audit.NewStore(appConfig, dynamoClient, awsClient.S3), logWrapper)
+
+	controller := api.NewIndexController(logWrapper, awsClient, appConfig, dynamoClient, auditLog)
 	logWrapper.Info("Service started...")
 
 	go func() {
@@ -77,6 +81,21 @@ func main() {
 
 	// Start shutdown sequence
 	logWrapper.Info("Shutting down gracefully...")
+
+	// Let requests in progress finish, so their audit entries are queued
+	// before the audit log is closed
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := controller.Shutdown(shutdownCtx); err != nil {
+		logWrapper.Error("Failed to finish requests in progress", slog.String("error", err.Error()))
+	}
 	cancel()
+
+	// Give queued audit entries a last chance to be appended
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer closeCancel()
+	if err := auditLog.Close(closeCtx); err != nil {
+		logWrapper.Error("Failed to record queued audit entries", slog.String("error", err.Error()))
+	}
 	logWrapper.Info("All jobs processed. Exiting.")
 }
@@ -64,6 +64,10 @@ awslocal secretsmanager create-secret --name local/jwt-key \
     --description "JWT secret for Go services authentication" \
     --secret-string "mysupersecrettestkeythatis128bits"
 
+awslocal secretsmanager create-secret --name local/sirius-jwt-key \
+    --description "JWT secret for calls to Sirius" \
+    --secret-string "mysiriussecrettestkeythatis128bit"
+
 awslocal ssm put-parameter --name "/local/local-credentials" --type "SecureString" --value '{"opg_document_and_d@publicguardian.gsi.gov.uk":"$2y$10$Xlq5mrdU6ZSh7kU5Yi.vpuCOrWCekNl9BwLcAg5G5bwr22ehTEpEa"}' --overwrite
 
 # S3
@@ -77,3 +81,8 @@ awslocal dynamodb create-table \
  --attribute-definitions AttributeName=PK,AttributeType=S AttributeName=SK,AttributeType=S \
  --key-schema AttributeName=PK,KeyType=HASH AttributeName=SK,KeyType=RANGE \
  --provisioned-throughput ReadCapacityUnits=1000,WriteCapacityUnits=1000
+
+awslocal dynamodb update-time-to-live \
+ --region eu-west-1 \
+ --table-name Documents \
+ --time-to-live-specification Enabled=true,AttributeName=ExpiresAt
@@ -8,6 +8,7 @@ echo $queues | grep '"http://sqs.eu-west-1.localhost.localstack.cloud:4566/00000
 secrets=$(awslocal secretsmanager list-secrets)
 
 echo $secrets | grep "local/jwt-key" || exit 1
+echo $secrets | grep "local/sirius-jwt-key" || exit 1
 
 # SSM
 awslocal ssm get-parameter --name=/local/local-credentials | grep "SecureString" || exit 1